@@ -1,52 +1,134 @@
 package auth
 
 import (
+	"crypto/rand"
+	"errors"
 	"fmt"
+	"os"
+	"strings"
 	"time"
 
 	"supra/logger"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
-// Define your JWT signing key (KEEP THIS SECURE AND SECRET)
-var jwtSecret = []byte("YOUR_SUPER_SECURE_JWT_SIGNING_KEY_12345")
+// minKeyLength is the shortest HS256 secret accepted, in bytes.
+const minKeyLength = 32
 
-// init runs once when the package is imported
-func init() {
-	// Log that the JWT configuration has been loaded upon package initialization
-	logger.Log.Info("[auth] JWT configuration loaded and signing key initialized.")
+// signingKeys holds the HS256 keys by kid. The active key signs new tokens;
+// the others only verify tokens issued before the last rotation.
+var signingKeys = struct {
+	activeID string
+	byID     map[string][]byte
+}{}
+
+// InitJWTKeys loads the signing keys from JWT_SIGNING_KEYS, a comma separated
+// list of kid:secret pairs. The first pair signs new tokens; keep the previous
+// keys listed after it for at least JWT_ACCESS_TTL after rotating. A single
+// JWT_SECRET (kid "default") is accepted as well. Without either, a random
+// key is generated and tokens do not survive a restart.
+func InitJWTKeys() error {
+	raw := os.Getenv("JWT_SIGNING_KEYS")
+	if raw == "" && os.Getenv("JWT_SECRET") != "" {
+		raw = "default:" + os.Getenv("JWT_SECRET")
+	}
+	if raw == "" {
+		b := make([]byte, minKeyLength)
+		if _, err := rand.Read(b); err != nil {
+			return fmt.Errorf("failed to generate JWT signing key: %w", err)
+		}
+		logger.Log.Warn("[auth] JWT_SIGNING_KEYS not set. Using an ephemeral signing key; sessions end on restart.")
+		signingKeys.activeID, signingKeys.byID = "ephemeral", map[string][]byte{"ephemeral": b}
+		return nil
+	}
+
+	byID := make(map[string][]byte)
+	activeID := ""
+	for _, pair := range strings.Split(raw, ",") {
+		kid, secret, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok || kid == "" {
+			return errors.New("invalid JWT_SIGNING_KEYS entry: want kid:secret")
+		}
+		if len(secret) < minKeyLength {
+			return fmt.Errorf("invalid JWT signing key %q: must be at least %d bytes", kid, minKeyLength)
+		}
+		if _, dup := byID[kid]; dup {
+			return fmt.Errorf("invalid JWT_SIGNING_KEYS: duplicate kid %q", kid)
+		}
+		byID[kid] = []byte(secret)
+		if activeID == "" {
+			activeID = kid
+		}
+	}
+	signingKeys.activeID, signingKeys.byID = activeID, byID
+	logger.Log.Info(fmt.Sprintf("[auth] JWT signing keys loaded. Active kid: %s, %d verify-only.", activeID, len(byID)-1))
+	return nil
+}
+
+// AccessTokenTTL reads JWT_ACCESS_TTL (a Go duration, default 15m).
+func AccessTokenTTL() time.Duration {
+	if d, err := time.ParseDuration(os.Getenv("JWT_ACCESS_TTL")); err == nil && d > 0 {
+		return d
+	}
+	return 15 * time.Minute
 }
 
 // Claims structure to store user info in the token
 type UserClaims struct {
-	UserID string `json:"user_id"`
-	Email  string `json:"email"`
-	Role   string `json:"role"`
+	UserID    string `json:"user_id"`
+	Email     string `json:"email"`
+	Role      string `json:"role"`
+	SessionID string `json:"sid,omitempty"` // refresh token family the token was issued for
 	jwt.RegisteredClaims
 }
 
-// GenerateJWT creates a new signed JWT for the user.
-func GenerateJWT(userID, email, role string) (string, error) {
+// GenerateJWT creates a new short-lived access token for the user, signed
+// with the active key.
+func GenerateJWT(userID, email, role, sessionID string) (string, time.Time, error) {
+	key, ok := signingKeys.byID[signingKeys.activeID]
+	if !ok {
+		return "", time.Time{}, errors.New("JWT signing keys not initialized")
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(AccessTokenTTL())
 	claims := UserClaims{
-		UserID: userID,
-		Email:  email,
-		Role:   role,
+		UserID:    userID,
+		Email:     email,
+		Role:      role,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour * 24)), // 24-hour expiry
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ID:        uuid.NewString(),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString(jwtSecret)
+	token.Header["kid"] = signingKeys.activeID
+	tokenString, err := token.SignedString(key)
 
 	if err != nil {
 		logger.Log.Error(fmt.Sprintf("[auth] Failed to sign JWT for user %s (%s): %v", userID, email, err))
-		return "", err
+		return "", time.Time{}, err
 	}
 
 	logger.Log.Info(fmt.Sprintf("[auth] Successfully generated JWT for user %s (Role: %s).", userID, role))
 
-	return tokenString, nil
+	return tokenString, expiresAt, nil
+}
+
+// verificationKey picks the key named by the token's kid header.
+func verificationKey(token *jwt.Token) (interface{}, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+	kid, _ := token.Header["kid"].(string)
+	key, ok := signingKeys.byID[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	return key, nil
 }
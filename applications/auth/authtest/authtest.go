@@ -0,0 +1,49 @@
+// Package authtest provides the callers and the request helper shared by the
+// route policy tests.
+package authtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"supra/applications/auth"
+	"supra/applications/user"
+	"supra/db/dbtest"
+
+	"github.com/labstack/echo/v4"
+)
+
+var (
+	Alice = auth.Principal{UserID: dbtest.AliceID, Email: "alice@example.com", Role: user.RoleUser}
+	Bob   = auth.Principal{UserID: dbtest.BobID, Email: "bob@example.com", Role: user.RoleUser}
+	Admin = auth.Principal{UserID: dbtest.AdminID, Email: "admin@example.com", Role: user.RoleAdmin}
+	// Guest's address is cased differently from the one on their booking.
+	Guest = auth.Principal{UserID: dbtest.GuestID, Email: "Guest@Example.com", Role: user.RoleUser}
+)
+
+// Serve runs a GET for target as p through mw, on a route that answers 200
+// once mw lets it through, and returns the status code.
+func Serve(t testing.TB, p auth.Principal, route, target string, mw echo.MiddlewareFunc) int {
+	t.Helper()
+	e := echo.New()
+	asPrincipal := func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set("userID", p.UserID)
+			c.Set("userEmail", p.Email)
+			c.Set("userRole", p.Role)
+			return next(c)
+		}
+	}
+	e.GET(route, func(c echo.Context) error { return c.NoContent(http.StatusOK) }, asPrincipal, mw)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	if rec.Code != http.StatusOK && rec.Code != http.StatusForbidden {
+		var body map[string]string
+		_ = json.Unmarshal(rec.Body.Bytes(), &body)
+		t.Logf("%s -> %d: %s", target, rec.Code, body["error"])
+	}
+	return rec.Code
+}
@@ -5,9 +5,11 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"html"
 	"net/http"
 	"os"
 	"strings"
+	"time"
 
 	"supra/logger"
 )
@@ -85,43 +87,77 @@ func SendOTP(toEmail, code string) error {
 }
 
 // Admin notification (includes inline preview + attachment)
-func SendBookingNotificationEmail(toEmail, bookingID, userEmail, seatType string, total float64, receiptBase64, userNotes string) error {
+func SendBookingNotificationEmail(toEmail, bookingID, userEmail, seatType string, total float64, currency, receiptBase64, contentType, userNotes, duplicateNote string) error {
 	html := fmt.Sprintf(`
 		<h2>🎟️ New Booking Notification</h2>
+		%s
 		<p><b>Booking ID:</b> %s</p>
 		<p><b>User:</b> %s</p>
 		<p><b>User Notes:</b> %s</p>
 		<p><b>Seat Type:</b> %s</p>
-		<p><b>Total:</b> ₹%.2f</p>
+		<p><b>Total:</b> %s</p>
 		<p>Status: <b style="color:#007bff;">Pending Verification</b></p>
 		<p>Receipt (preview):</p>
-		<img src="data:image/png;base64,%s" style="max-width:500px;border-radius:8px;" />
-	`, bookingID, userEmail, userNotes, seatType, total, receiptBase64)
+		%s
+	`, duplicateWarningHTML(duplicateNote), bookingID, userEmail, userNotes, seatType, money(total, currency), receiptPreviewHTML(receiptBase64, contentType, "max-width:500px;border-radius:8px;"))
 
-	att := Attachment{Filename: "receipt.png", Content: receiptBase64}
+	att := receiptAttachment(receiptBase64, contentType)
 	return sendEmailResend(toEmail, fmt.Sprintf("🆕 New Booking Created [%s]", bookingID), html, "", att)
 }
 
 // Re-upload notification (with Approve/Reject + attachment)
-func SendReceiptReuploadNotification(toEmail, bookingID, userEmail, seatType string, amount float64, base64Receipt, userNotes string) error {
+func SendReceiptReuploadNotification(toEmail, bookingID, userEmail, seatType string, amount float64, currency, base64Receipt, contentType, userNotes, duplicateNote string) error {
 	html := fmt.Sprintf(`
 		<h2>🔄 Receipt Re-upload Alert</h2>
+		%s
 		<p>User <b>%s</b> re-uploaded payment receipt for:</p>
 		<p><b>User Notes:</b> %s</p>
 		<ul>
 			<li><b>Booking ID:</b> %s</li>
 			<li><b>Seat Type:</b> %s</li>
-			<li><b>Amount:</b> ₹%.2f</li>
+			<li><b>Amount:</b> %s</li>
 		</ul>
 
 		<p>Receipt (preview):</p>
-		<img src="data:image/png;base64,%s" style="max-width:450px;margin-top:15px;border-radius:6px;" />
-	`, userEmail, userNotes, bookingID, seatType, amount, base64Receipt)
+		%s
+	`, duplicateWarningHTML(duplicateNote), userEmail, userNotes, bookingID, seatType, money(amount, currency), receiptPreviewHTML(base64Receipt, contentType, "max-width:450px;margin-top:15px;border-radius:6px;"))
 
-	att := Attachment{Filename: "receipt.png", Content: base64Receipt}
+	att := receiptAttachment(base64Receipt, contentType)
 	return sendEmailResend(toEmail, fmt.Sprintf("🔄 Receipt Re-uploaded [%s]", bookingID), html, "", att)
 }
 
+// receiptPreviewHTML renders an inline preview for image receipts; other formats
+// (PDF) are only attached.
+func receiptPreviewHTML(receiptBase64, contentType, style string) string {
+	if !strings.HasPrefix(contentType, "image/") {
+		return "<p><i>Receipt attached (" + contentType + ").</i></p>"
+	}
+	return fmt.Sprintf(`<img src="data:%s;base64,%s" style="%s" />`, contentType, receiptBase64, style)
+}
+
+// duplicateWarningHTML highlights receipts that were already used for another booking.
+func duplicateWarningHTML(note string) string {
+	if note == "" {
+		return ""
+	}
+	return fmt.Sprintf(`<p style="color:#d9534f;"><b>⚠️ Possible duplicate receipt:</b> %s</p>`, note)
+}
+
+func receiptAttachment(receiptBase64, contentType string) Attachment {
+	ext := ".bin"
+	switch contentType {
+	case "image/jpeg":
+		ext = ".jpg"
+	case "image/png":
+		ext = ".png"
+	case "image/webp":
+		ext = ".webp"
+	case "application/pdf":
+		ext = ".pdf"
+	}
+	return Attachment{Filename: "receipt" + ext, Content: receiptBase64}
+}
+
 // Status updates
 func SendBookingVerificationMail(toEmail, status, bookingID, base64Receipt, note string) error {
 	logger.Log.Info(fmt.Sprintf("[auth] Sending booking verification email (Status: %s) to %s", status, toEmail))
@@ -161,16 +197,64 @@ func SendBookingVerificationMail(toEmail, status, bookingID, base64Receipt, note
 	return sendEmailResend(toEmail, subject, html, "")
 }
 
+// Rejection mail — includes the stored reason and re-upload guidance
+func SendBookingRejectionMail(toEmail, bookingID, reason, hint string, allowReupload bool) error {
+	logger.Log.Info(fmt.Sprintf("[auth] Sending booking rejection email to %s", toEmail))
+
+	nextStep := "<p>If this was a mistake, please contact support.</p>"
+	if allowReupload {
+		nextStep = "<p>You can upload a new receipt from <b>My Bookings</b> and we will verify it again.</p>"
+	}
+
+	html := fmt.Sprintf(`
+		<h2>❌ Booking Rejected</h2>
+		<p>Your booking <b>%s</b> was rejected.</p>
+		<p><b>Reason:</b> %s</p>
+		<p><b>What to do:</b> %s</p>
+		%s
+	`, bookingID, reason, hint, nextStep)
+
+	return sendEmailResend(toEmail, "❌ Booking Rejected", html, "")
+}
+
+// PriceLine is one itemized row (ticket subtotal, discount, fee or tax).
+type PriceLine struct {
+	Label  string
+	Amount float64
+}
+
+// money formats an amount for a mail: ₹ for INR, the currency code otherwise.
+func money(amount float64, currency string) string {
+	if currency == "" || currency == "INR" {
+		return fmt.Sprintf("₹%.2f", amount)
+	}
+	return fmt.Sprintf("%.2f %s", amount, currency)
+}
+
+func priceLinesHTML(lines []PriceLine, total float64, currency string) string {
+	if len(lines) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(`<table style="border-collapse:collapse;min-width:280px">`)
+	for _, l := range lines {
+		fmt.Fprintf(&b, `<tr><td style="padding:2px 12px 2px 0">%s</td><td style="text-align:right">%s</td></tr>`, html.EscapeString(l.Label), money(l.Amount, currency))
+	}
+	fmt.Fprintf(&b, `<tr><td style="padding:4px 12px 0 0;border-top:1px solid #ccc"><b>Total</b></td><td style="text-align:right;border-top:1px solid #ccc"><b>%s</b></td></tr></table>`, money(total, currency))
+	return b.String()
+}
+
 // Approval mail — attach the PDF e-ticket
-func SendBookingApprovalMail(toEmail, bookingID, seatType string, qty int, total float64, pdfBytes []byte) error {
+func SendBookingApprovalMail(toEmail, bookingID, seatType string, qty int, total float64, currency string, lines []PriceLine, pdfBytes []byte) error {
 	pdfBase64 := base64.StdEncoding.EncodeToString(pdfBytes)
 
 	html := fmt.Sprintf(`
 		<h2>✅ Booking Approved!</h2>
 		<p>Your booking <b>%s</b> has been approved.</p>
-		<p>Seat Type: %s<br>Quantity: %d<br>Total: ₹%.2f</p>
+		<p>Seat Type: %s<br>Quantity: %d<br>Total: %s</p>
+		%s
 		<p>Your e-ticket PDF is attached to this email.</p>
-	`, bookingID, seatType, qty, total)
+	`, bookingID, seatType, qty, money(total, currency), priceLinesHTML(lines, total, currency))
 
 	att := Attachment{
 		Filename: fmt.Sprintf("e-ticket-%s.pdf", bookingID),
@@ -178,3 +262,161 @@ func SendBookingApprovalMail(toEmail, bookingID, seatType string, qty int, total
 	}
 	return sendEmailResend(toEmail, fmt.Sprintf("🎟️ Your BlackTickets e-Ticket [%s]", bookingID), html, "", att)
 }
+
+// Payment instructions — UPI link and QR for the exact booking amount
+func SendBookingPaymentMail(toEmail, bookingID, reference string, amount float64, upiLink, qrBase64 string) error {
+	logger.Log.Info(fmt.Sprintf("[auth] Sending UPI payment instructions for %s to %s", bookingID, toEmail))
+
+	html := fmt.Sprintf(`
+		<h2>💳 Complete Your Payment</h2>
+		<p>Booking <b>%s</b> is reserved. Pay <b>₹%.2f</b> by UPI to confirm it.</p>
+		<p>Scan the QR code with any UPI app, or tap <a href="%s">Pay with UPI</a> on your phone.</p>
+		<img src="data:image/png;base64,%s" style="max-width:260px;margin-top:10px;" />
+		<p><b>Payment reference:</b> %s<br>
+		<i>Please keep the reference in the payment note so we can match your payment.</i></p>
+		<p>After paying, upload the payment screenshot from <b>My Bookings</b>.</p>
+	`, bookingID, amount, upiLink, qrBase64, reference)
+
+	att := Attachment{Filename: fmt.Sprintf("upi-qr-%s.png", reference), Content: qrBase64}
+	return sendEmailResend(toEmail, fmt.Sprintf("💳 Payment Details for Booking [%s]", bookingID), html, "", att)
+}
+
+// Refund status updates — one mail per workflow step
+func SendRefundStatusMail(toEmail, bookingID, refundID, status string, amount float64, note string) error {
+	logger.Log.Info(fmt.Sprintf("[auth] Sending refund %s email for %s to %s", status, refundID, toEmail))
+
+	var subject, headline string
+	switch strings.ToUpper(status) {
+	case "REQUESTED":
+		subject, headline = "↩️ Refund Request Received", "We received your refund request and will review it shortly."
+	case "APPROVED":
+		subject, headline = "✅ Refund Approved", "Your refund has been approved and will be paid out soon. Your booking has been cancelled."
+	case "PAID":
+		subject, headline = "💸 Refund Paid", "Your refund has been paid."
+	case "DENIED":
+		subject, headline = "❌ Refund Denied", "Unfortunately your refund request was denied."
+	default:
+		subject, headline = "Refund Update", "Your refund status changed to "+status+"."
+	}
+
+	noteHTML := ""
+	if note != "" {
+		noteHTML = fmt.Sprintf("<p><b>Note:</b> %s</p>", note)
+	}
+	html := fmt.Sprintf(`
+		<h2>%s</h2>
+		<p>%s</p>
+		<p><b>Booking ID:</b> %s<br><b>Refund ID:</b> %s<br><b>Amount:</b> ₹%.2f</p>
+		%s
+	`, subject, headline, bookingID, refundID, amount, noteHTML)
+
+	return sendEmailResend(toEmail, fmt.Sprintf("%s [%s]", subject, bookingID), html, "")
+}
+
+// Admin notification for a new refund request
+func SendRefundRequestNotification(toEmail, bookingID, refundID, userEmail string, amount float64, method, reason string) error {
+	html := fmt.Sprintf(`
+		<h2>↩️ New Refund Request</h2>
+		<p><b>User:</b> %s</p>
+		<ul>
+			<li><b>Booking ID:</b> %s</li>
+			<li><b>Refund ID:</b> %s</li>
+			<li><b>Amount:</b> ₹%.2f</li>
+			<li><b>Method:</b> %s</li>
+		</ul>
+		<p><b>Reason:</b> %s</p>
+	`, userEmail, bookingID, refundID, amount, method, reason)
+
+	return sendEmailResend(toEmail, fmt.Sprintf("↩️ Refund Requested [%s]", bookingID), html, "")
+}
+
+// Waitlist offer — seats freed up; the claim link creates a hold for the user
+func SendWaitlistOfferMail(toEmail, seatType string, qty int, claimURL string, expiresAt time.Time) error {
+	logger.Log.Info(fmt.Sprintf("[auth] Sending waitlist offer for %d x %s to %s", qty, seatType, toEmail))
+
+	html := fmt.Sprintf(`
+		<h2>🎉 Seats are available!</h2>
+		<p>%d <b>%s</b> seat(s) you were waiting for have been released and are reserved for you.</p>
+		<p><a href="%s" style="background:#d81b60;color:#fff;padding:10px 18px;border-radius:6px;text-decoration:none">Claim my seats</a></p>
+		<p>This offer expires on <b>%s</b>. After that the seats go to the next person on the waitlist.</p>
+	`, qty, seatType, claimURL, expiresAt.Format("02 Jan 2006 15:04 MST"))
+
+	return sendEmailResend(toEmail, "🎟️ Your waitlisted seats are available", html, "")
+}
+
+// Ticket transfer offer — the recipient logs in with an OTP and accepts
+func SendTransferOfferMail(toEmail, fromEmail, bookingID, seatType string, qty int, acceptURL string, expiresAt time.Time) error {
+	logger.Log.Info(fmt.Sprintf("[auth] Sending transfer offer for %s to %s", bookingID, toEmail))
+
+	html := fmt.Sprintf(`
+		<h2>🎁 Tickets are waiting for you</h2>
+		<p><b>%s</b> wants to transfer %d <b>%s</b> ticket(s) to you.</p>
+		<p>Log in with this email address (we'll send you a one-time code) and accept the transfer:</p>
+		<p><a href="%s" style="background:#d81b60;color:#fff;padding:10px 18px;border-radius:6px;text-decoration:none">Accept tickets</a></p>
+		<p>The offer expires on <b>%s</b>.</p>
+	`, fromEmail, qty, seatType, acceptURL, expiresAt.Format("02 Jan 2006 15:04 MST"))
+
+	return sendEmailResend(toEmail, fmt.Sprintf("🎁 Ticket transfer from %s", fromEmail), html, "")
+}
+
+// Reissued eTicket after a transfer — older QR codes no longer work
+func SendReissuedTicketMail(toEmail, bookingID, seatType string, qty int, note string, pdfBytes []byte) error {
+	logger.Log.Info(fmt.Sprintf("[auth] Sending reissued ticket for %s to %s", bookingID, toEmail))
+
+	html := fmt.Sprintf(`
+		<h2>🎟️ Your tickets were reissued</h2>
+		<p>%s</p>
+		<p>Booking <b>%s</b><br>Seat Type: %s<br>Quantity: %d</p>
+		<p>Your new e-ticket PDF is attached. <b>Previously issued tickets for this booking are no longer valid.</b></p>
+	`, note, bookingID, seatType, qty)
+
+	att := Attachment{
+		Filename: fmt.Sprintf("e-ticket-%s.pdf", bookingID),
+		Content:  base64.StdEncoding.EncodeToString(pdfBytes),
+	}
+	return sendEmailResend(toEmail, fmt.Sprintf("🎟️ Reissued e-Ticket [%s]", bookingID), html, "", att)
+}
+
+// Transfer completed — tells the previous owner their old tickets are void
+func SendTransferCompletedMail(toEmail, bookingID, recipient string, qty int) error {
+	logger.Log.Info(fmt.Sprintf("[auth] Sending transfer confirmation for %s to %s", bookingID, toEmail))
+
+	html := fmt.Sprintf(`
+		<h2>✅ Transfer completed</h2>
+		<p>%d ticket(s) from booking <b>%s</b> now belong to <b>%s</b>.</p>
+		<p>The tickets you received earlier for them are no longer valid at the entrance.</p>
+	`, qty, bookingID, recipient)
+
+	return sendEmailResend(toEmail, fmt.Sprintf("✅ Ticket transfer completed [%s]", bookingID), html, "")
+}
+
+// Staff invitation — admins and global staff get a link to choose a password
+func SendStaffInviteMail(toEmail, role, concertID, invitedBy, link string) error {
+	logger.Log.Info(fmt.Sprintf("[auth] Sending %s invite to %s", role, toEmail))
+
+	scope := "all concerts"
+	if concertID != "" {
+		scope = "concert " + concertID
+	}
+	html := fmt.Sprintf(`
+		<h2>👋 You've been added to the BlackTickets team</h2>
+		<p><b>%s</b> gave you the <b>%s</b> role for %s.</p>
+		<p><a href="%s" style="background:#d81b60;color:#fff;padding:10px 18px;border-radius:6px;text-decoration:none">Get started</a></p>
+		<p>If you were not expecting this, you can ignore this email.</p>
+	`, invitedBy, role, scope, link)
+
+	return sendEmailResend(toEmail, "👋 Your BlackTickets staff invitation", html, "")
+}
+
+// Password reset — one-time link to choose a new admin/staff password
+func SendPasswordResetMail(toEmail, link string, expiresAt time.Time) error {
+	logger.Log.Info(fmt.Sprintf("[auth] Sending password reset link to %s", toEmail))
+
+	html := fmt.Sprintf(`
+		<h2>🔑 Reset your BlackTickets password</h2>
+		<p><a href="%s" style="background:#d81b60;color:#fff;padding:10px 18px;border-radius:6px;text-decoration:none">Choose a new password</a></p>
+		<p>The link can be used once and expires on <b>%s</b>. Your current password keeps working until then.</p>
+	`, link, expiresAt.Format("02 Jan 2006 15:04 MST"))
+
+	return sendEmailResend(toEmail, "🔑 Reset your BlackTickets password", html, "")
+}
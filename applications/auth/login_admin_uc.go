@@ -10,8 +10,9 @@ import (
 	"supra/logger"            // ⬅️ Assuming this import path
 )
 
-// LoginAdmin handles the secure login flow for the Administrator.
-func LoginAdmin(email, password string) (token string, role string, err error) {
+// LoginAdmin handles the secure password login flow for the Administrator and
+// for staff whose account role is a staff role.
+func LoginAdmin(email, password string) (*TokenPair, error) {
 	logger.Log.Info(fmt.Sprintf("[auth] Admin login attempt started for email: %s", email))
 
 	// 1. Retrieve the user record by email
@@ -19,34 +20,39 @@ func LoginAdmin(email, password string) (token string, role string, err error) {
 	if err != nil {
 		// Log the failure to find the user
 		logger.Log.Warn(fmt.Sprintf("[auth] Admin login failed for %s: User not found or DB error: %v", email, err))
-		return "", "", errors.New("invalid credentials")
+		return nil, errors.New("invalid credentials")
 	}
 
 	// 2. Check if the user has the necessary permissions
-	if u.Role != user.RoleAdmin {
-		logger.Log.Warn(fmt.Sprintf("[auth] Admin login blocked for %s: Role is '%s', not 'admin'.", email, u.Role))
-		return "", "", errors.New("access denied: account is not an administrator")
+	if !usesPassword(u.Role) {
+		logger.Log.Warn(fmt.Sprintf("[auth] Admin login blocked for %s: Role is '%s', not 'admin' or staff.", email, u.Role))
+		return nil, errors.New("access denied: account is not an administrator")
+	}
+	if u.DisabledAt != nil {
+		logger.Log.Warn(fmt.Sprintf("[auth] Admin login blocked for %s: account disabled.", email))
+		return nil, user.ErrAccountDisabled
 	}
 
-	logger.Log.Info(fmt.Sprintf("[auth] User %s found with role 'admin'. Proceeding to password comparison.", email))
+	logger.Log.Info(fmt.Sprintf("[auth] User %s found with role '%s'. Proceeding to password comparison.", email, u.Role))
 
 	// 3. Compare the provided password against the stored hash
 	err = bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(password))
 	if err != nil {
 		// This handles bcrypt.ErrMismatchedHashAndPassword
 		logger.Log.Warn(fmt.Sprintf("[auth] Admin login failed for %s: Password mismatch.", email))
-		return "", "", errors.New("invalid credentials")
+		return nil, errors.New("invalid credentials")
 	}
 
 	logger.Log.Info(fmt.Sprintf("[auth] Password matched for Admin %s. Generating JWT.", email))
 
-	// 4. If credentials match, generate the JWT
-	token, err = GenerateJWT(u.UserID.String(), u.Email, u.Role)
+	// 4. If credentials match, start a session (access JWT + refresh token)
+	pair, err := startSession(u)
 	if err != nil {
-		logger.Log.Error(fmt.Sprintf("[auth] Failed to generate JWT for %s: %v", email, err))
-		return "", "", fmt.Errorf("failed to generate JWT: %w", err)
+		logger.Log.Error(fmt.Sprintf("[auth] Failed to start session for %s: %v", email, err))
+		return nil, err
 	}
 
+	user.RecordLogin(u.UserID)
 	logger.Log.Info(fmt.Sprintf("[auth] Admin login successful for %s. JWT issued.", email))
-	return token, u.Role, nil
+	return pair, nil
 }
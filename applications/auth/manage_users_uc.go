@@ -0,0 +1,315 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"supra/applications/user"
+	"supra/logger"
+	"supra/validation"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrPasswordNotAllowed = errors.New("invalid account: only admin and staff accounts can have a password")
+	ErrInvalidResetToken  = errors.New("invalid or expired password reset token")
+	ErrSelfLockout        = errors.New("invalid request: you cannot disable or demote your own account")
+)
+
+// InviteUserParams is the body of POST /admin/users.
+type InviteUserParams struct {
+	Email     string `json:"email" validate:"required,email"`
+	Role      string `json:"role" validate:"required"`
+	ConcertID string `json:"concertID,omitempty" validate:"uuid"` // grant the staff role for one concert only
+}
+
+// SetPasswordParams is the body of PUT /admin/users/:userID/password.
+type SetPasswordParams struct {
+	Password string `json:"password" validate:"required,min=10,max=72"` // bcrypt ignores bytes past 72
+}
+
+// ResetPasswordParams is the body of POST /password/reset.
+type ResetPasswordParams struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required,min=10,max=72"`
+}
+
+// SetRoleParams is the body of PUT /admin/users/:userID/role.
+type SetRoleParams struct {
+	Role string `json:"role" validate:"required"`
+}
+
+// PasswordResetTTL reads PASSWORD_RESET_TTL (a Go duration, default 24h).
+func PasswordResetTTL() time.Duration {
+	if d, err := time.ParseDuration(os.Getenv("PASSWORD_RESET_TTL")); err == nil && d > 0 {
+		return d
+	}
+	return 24 * time.Hour
+}
+
+func passwordResetURL(token string) string {
+	base := os.Getenv("PASSWORD_RESET_URL")
+	if base == "" {
+		base = "https://bkentertainments.vercel.app/admin/set-password"
+	}
+	return fmt.Sprintf("%s?token=%s", base, token)
+}
+
+func staffLoginURL() string {
+	if u := os.Getenv("STAFF_LOGIN_URL"); u != "" {
+		return u
+	}
+	return "https://bkentertainments.vercel.app/admin/login"
+}
+
+// usesPassword reports whether accounts with role log in through LoginAdmin.
+func usesPassword(role string) bool {
+	return role == user.RoleAdmin || user.IsStaffRole(role)
+}
+
+func checkAssignableRole(role string) error {
+	if role == user.RoleAdmin || role == user.RoleUser || user.IsStaffRole(role) {
+		return nil
+	}
+	return fmt.Errorf("%w %q: must be admin, user or one of %s", user.ErrUnknownRole, role, strings.Join(user.StaffRoles, ", "))
+}
+
+// guardLastAdmin refuses changes that would leave no enabled admin account.
+func guardLastAdmin(u *user.User) error {
+	if u.Role != user.RoleAdmin || u.DisabledAt != nil {
+		return nil
+	}
+	n, err := user.CountActiveAdmins()
+	if err != nil {
+		return fmt.Errorf("failed to count admins: %w", err)
+	}
+	if n <= 1 {
+		return user.ErrLastAdmin
+	}
+	return nil
+}
+
+// InviteUser creates the account for an email and mails the invitation.
+// Admins and global staff get a link to choose a password; per-concert staff
+// keep their account role and sign in with an email code. Inviting an
+// existing account to a global role fails with user.ErrUserExists: role
+// changes go through SetRole and its self-demotion and last-admin guards.
+func InviteUser(payload []byte, invitedBy string) (*user.User, error) {
+	var p InviteUserParams
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return nil, fmt.Errorf("invalid payload: %w", err)
+	}
+	if err := validation.Struct(&p); err != nil {
+		return nil, err
+	}
+	p.Email = strings.ToLower(strings.TrimSpace(p.Email))
+	if err := checkAssignableRole(p.Role); err != nil {
+		return nil, err
+	}
+	if p.ConcertID != "" && !user.IsStaffRole(p.Role) {
+		return nil, fmt.Errorf("invalid concertID: only staff roles can be granted per concert")
+	}
+
+	existing, err := user.GetUserByEmail(p.Email)
+	if err != nil && !strings.Contains(err.Error(), "not found") {
+		return nil, err
+	}
+
+	var u *user.User
+	if p.ConcertID != "" {
+		if existing == nil {
+			if _, err := user.CreateUserWithRole(p.Email, user.RoleUser, invitedBy); err != nil && !errors.Is(err, user.ErrUserExists) {
+				return nil, err
+			}
+		}
+		grant, _ := json.Marshal(user.AssignStaffRoleParams{Email: p.Email, Role: p.Role, ConcertID: p.ConcertID})
+		if _, err := user.AssignStaffRole(grant, invitedBy); err != nil {
+			return nil, err
+		}
+		if u, err = user.GetUserByEmail(p.Email); err != nil {
+			return nil, err
+		}
+	} else {
+		if existing != nil {
+			return nil, fmt.Errorf("%w: %s already has the %s role; change it through the role endpoint", user.ErrUserExists, p.Email, existing.Role)
+		}
+		if u, err = user.CreateUserWithRole(p.Email, p.Role, invitedBy); err != nil {
+			return nil, err
+		}
+	}
+
+	link := staffLoginURL()
+	if usesPassword(u.Role) && !u.HasPassword {
+		if link, err = issuePasswordReset(u); err != nil {
+			return nil, err
+		}
+	}
+	if err := SendStaffInviteMail(u.Email, p.Role, p.ConcertID, invitedBy, link); err != nil {
+		logger.Log.Error(fmt.Sprintf("[auth] Failed to send invite to %s: %v", u.Email, err))
+	}
+
+	logger.Log.Info(fmt.Sprintf("[auth] %s invited %s as %s (concert %q)", invitedBy, u.Email, p.Role, p.ConcertID))
+	return u, nil
+}
+
+// SetPassword stores a new bcrypt password for an admin or staff account.
+func SetPassword(userID string, payload []byte, actor string) (*user.User, error) {
+	var p SetPasswordParams
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return nil, fmt.Errorf("invalid payload: %w", err)
+	}
+	if err := validation.Struct(&p); err != nil {
+		return nil, err
+	}
+	u, err := user.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if !usesPassword(u.Role) {
+		return nil, ErrPasswordNotAllowed
+	}
+	if u, err = storePassword(userID, p.Password); err != nil {
+		return nil, err
+	}
+	logger.Log.Info(fmt.Sprintf("[auth] %s set the password of %s", actor, u.Email))
+	return u, nil
+}
+
+// RequestPasswordReset mails the account a one-time link to choose a new
+// password. The current password keeps working until the link is used.
+func RequestPasswordReset(userID, actor string) error {
+	u, err := user.GetUserByID(userID)
+	if err != nil {
+		return err
+	}
+	if !usesPassword(u.Role) {
+		return ErrPasswordNotAllowed
+	}
+	link, err := issuePasswordReset(u)
+	if err != nil {
+		return err
+	}
+	if err := SendPasswordResetMail(u.Email, link, time.Now().Add(PasswordResetTTL())); err != nil {
+		return fmt.Errorf("failed to send password reset email: %w", err)
+	}
+	logger.Log.Info(fmt.Sprintf("[auth] %s requested a password reset for %s", actor, u.Email))
+	return nil
+}
+
+// ResetPassword sets the password of the account a reset link was issued to.
+func ResetPassword(payload []byte) error {
+	var p ResetPasswordParams
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return fmt.Errorf("invalid payload: %w", err)
+	}
+	if err := validation.Struct(&p); err != nil {
+		return err
+	}
+	u, err := user.UserByResetToken(hashToken(p.Token))
+	if errors.Is(err, user.ErrUserNotFound) {
+		return ErrInvalidResetToken
+	}
+	if err != nil {
+		return err
+	}
+	if u.DisabledAt != nil {
+		return user.ErrAccountDisabled
+	}
+	if _, err := storePassword(u.UserID.String(), p.Password); err != nil {
+		return err
+	}
+	logger.Log.Info(fmt.Sprintf("[auth] Password reset completed for %s", u.Email))
+	return nil
+}
+
+// SetRole changes the role stored on an account.
+func SetRole(userID string, payload []byte, actor Principal) (*user.User, error) {
+	var p SetRoleParams
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return nil, fmt.Errorf("invalid payload: %w", err)
+	}
+	if err := validation.Struct(&p); err != nil {
+		return nil, err
+	}
+	if err := checkAssignableRole(p.Role); err != nil {
+		return nil, err
+	}
+	u, err := user.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if p.Role != user.RoleAdmin {
+		if strings.EqualFold(u.UserID.String(), actor.UserID) {
+			return nil, ErrSelfLockout
+		}
+		if err := guardLastAdmin(u); err != nil {
+			return nil, err
+		}
+	}
+	if u, err = user.SetRole(userID, p.Role); err != nil {
+		return nil, err
+	}
+	logger.Log.Info(fmt.Sprintf("[auth] %s changed the role of %s to %s", actor.Email, u.Email, u.Role))
+	return u, nil
+}
+
+// SetDisabled disables or re-enables an account. Disabled accounts can no
+// longer log in with a password or an email code, and their refresh tokens
+// are revoked.
+func SetDisabled(userID string, disabled bool, actor Principal) (*user.User, error) {
+	u, err := user.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if disabled {
+		if strings.EqualFold(u.UserID.String(), actor.UserID) {
+			return nil, ErrSelfLockout
+		}
+		if err := guardLastAdmin(u); err != nil {
+			return nil, err
+		}
+	}
+	if u, err = user.SetDisabled(userID, disabled); err != nil {
+		return nil, err
+	}
+	if disabled {
+		if _, err := RevokeAllSessions(userID); err != nil {
+			logger.Log.Error(fmt.Sprintf("[auth] Failed to revoke sessions of disabled user %s: %v", u.Email, err))
+		}
+	}
+	logger.Log.Info(fmt.Sprintf("[auth] %s set disabled=%t on %s", actor.Email, disabled, u.Email))
+	return u, nil
+}
+
+func storePassword(userID, password string) (*user.User, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+	return user.SetPasswordHash(userID, hash)
+}
+
+// issuePasswordReset stores the hash of a fresh reset token on the account
+// and returns the link carrying the token itself.
+func issuePasswordReset(u *user.User) (string, error) {
+	token, err := newOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+	if err := user.SetPasswordResetToken(u.UserID.String(), hashToken(token), time.Now().Add(PasswordResetTTL())); err != nil {
+		return "", err
+	}
+	return passwordResetURL(token), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -5,85 +5,106 @@ import (
 	"net/http"
 	"strings"
 
+	"supra/applications/user"
 	"supra/logger" // ⬅️ Assuming this import path
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/labstack/echo/v4"
 )
 
-// NOTE: UserClaims struct and jwtSecret variable are assumed to be
-// defined in another file within this 'auth' package (e.g., auth.go).
+// NOTE: UserClaims and the signing keys are defined in auth.go.
 
 func JWTAuthMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
-		authHeader := c.Request().Header.Get("Authorization")
-		tokenString := ""
-
-		// 1️⃣ Prefer Authorization header
-		if authHeader != "" {
-			parts := strings.Split(authHeader, " ")
-			if len(parts) == 2 && parts[0] == "Bearer" {
-				tokenString = parts[1]
-			}
-		}
-
-		// 2️⃣ Fallback: Check ?token= in query (useful for email verification links)
-		if tokenString == "" {
-			tokenString = c.QueryParam("token")
-			if tokenString != "" {
-				logger.Log.Info(fmt.Sprintf("[auth] Using token from query parameter for path: %s", c.Path()))
-			}
-		}
-
+		tokenString := tokenFromRequest(c)
 		if tokenString == "" {
 			logger.Log.Warn("[auth] JWT check failed: No token in header or query.")
 			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Authorization token missing"})
 		}
 
-		// 3️⃣ Validate the token
-		token, err := jwt.ParseWithClaims(tokenString, &UserClaims{}, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-			}
-			return jwtSecret, nil
-		})
-
-		if err != nil || !token.Valid {
+		claims, err := parseToken(tokenString)
+		if err != nil {
 			logger.Log.Warn(fmt.Sprintf("[auth] Invalid or expired JWT: %v", err))
 			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Invalid or expired token"})
 		}
 
-		claims, ok := token.Claims.(*UserClaims)
-		if !ok {
-			logger.Log.Error("[auth] JWT claims extraction failed")
-			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Invalid token claims"})
-		}
-
-		c.Set("userID", claims.UserID)
-		c.Set("userRole", claims.Role)
-		c.Set("userEmail", claims.Email)
-
+		setClaims(c, claims)
 		logger.Log.Info(fmt.Sprintf("[auth] ✅ JWT validated. UserID: %s, Role: %s", claims.UserID, claims.Role))
 		return next(c)
 	}
 }
 
-// AdminOnlyMiddleware checks the role set by JWTAuthMiddleware.
-func AdminOnlyMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+// OptionalJWTMiddleware sets the caller's claims when a valid token is sent
+// and otherwise lets the request through anonymously. It is meant for public
+// endpoints that show more to signed-in users.
+func OptionalJWTMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
-		role := c.Get("userRole")
+		if tokenString := tokenFromRequest(c); tokenString != "" {
+			if claims, err := parseToken(tokenString); err == nil {
+				setClaims(c, claims)
+			} else {
+				logger.Log.Warn(fmt.Sprintf("[auth] Ignoring invalid JWT on public path %s: %v", c.Path(), err))
+			}
+		}
+		return next(c)
+	}
+}
+
+// tokenFromRequest prefers the Authorization header and falls back to
+// ?token= (useful for email verification links).
+func tokenFromRequest(c echo.Context) string {
+	authHeader := c.Request().Header.Get("Authorization")
+	if authHeader != "" {
+		parts := strings.Split(authHeader, " ")
+		if len(parts) == 2 && parts[0] == "Bearer" {
+			return parts[1]
+		}
+	}
+
+	tokenString := c.QueryParam("token")
+	if tokenString != "" {
+		logger.Log.Info(fmt.Sprintf("[auth] Using token from query parameter for path: %s", c.Path()))
+	}
+	return tokenString
+}
 
-		// Log the role being checked
-		logger.Log.Info(fmt.Sprintf("[auth] RBAC check for path %s. Required role: Admin. User role: %v", c.Path(), role))
+func parseToken(tokenString string) (*UserClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &UserClaims{}, verificationKey, jwt.WithValidMethods([]string{"HS256"}))
+	if err != nil {
+		return nil, err
+	}
+	claims, ok := token.Claims.(*UserClaims)
+	if !ok || !token.Valid {
+		return nil, fmt.Errorf("invalid token claims")
+	}
+	return claims, nil
+}
+
+func setClaims(c echo.Context, claims *UserClaims) {
+	c.Set("userID", claims.UserID)
+	c.Set("userRole", claims.Role)
+	c.Set("userEmail", claims.Email)
+}
 
-		// Ensure the token validation ran and set the role
-		if role == nil || role != "admin" {
-			logger.Log.Warn(fmt.Sprintf("[auth] RBAC FAILED for UserID %v: Access Forbidden.", c.Get("userID")))
-			// Block access if role is missing or not "admin"
-			return c.JSON(http.StatusForbidden, map[string]string{"error": "Access Forbidden: Admin privileges required"})
+// StaffOnlyMiddleware guards the admin group: only admins and users holding
+// at least one staff role get past it. Each route then checks its own
+// permission with RequirePermission.
+func StaffOnlyMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		p := PrincipalFrom(c)
+		if p.IsAdmin() || user.IsStaffRole(p.Role) {
+			return next(c)
 		}
 
-		logger.Log.Info(fmt.Sprintf("[auth] RBAC PASSED for Admin UserID: %v", c.Get("userID")))
+		grants, err := user.GetUserStaffRoles(p.UserID)
+		if err != nil {
+			logger.Log.Error(fmt.Sprintf("[auth] Staff role lookup failed for %s: %v", p.Email, err))
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to authorize request"})
+		}
+		if len(grants) == 0 {
+			logger.Log.Warn(fmt.Sprintf("[auth] RBAC FAILED for UserID %s on %s: no staff role.", p.UserID, c.Path()))
+			return c.JSON(http.StatusForbidden, map[string]string{"error": "Access Forbidden: staff privileges required"})
+		}
 		return next(c)
 	}
 }
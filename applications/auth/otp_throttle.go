@@ -0,0 +1,133 @@
+package auth
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"supra/db"
+	"supra/logger"
+)
+
+// OTP abuse limits. Each can be overridden with the environment variable
+// named next to it (integers, or Go durations for the time limits).
+var (
+	otpMaxAttempts     = envInt("OTP_MAX_ATTEMPTS", 5)                   // wrong guesses before a code is discarded
+	otpResendCooldown  = envDuration("OTP_RESEND_COOLDOWN", time.Minute) // between two codes for one email
+	otpWindow          = envDuration("OTP_RATE_WINDOW", time.Hour)       // window for the counters below
+	otpMaxSendsEmail   = envInt("OTP_MAX_SENDS_PER_EMAIL", 5)            // codes mailed to one address per window
+	otpMaxSendsIP      = envInt("OTP_MAX_SENDS_PER_IP", 20)              // codes requested from one IP per window
+	otpMaxFailureEmail = envInt("OTP_MAX_FAILURES_PER_EMAIL", 10)        // wrong codes for one address per window
+	otpMaxFailureIP    = envInt("OTP_MAX_FAILURES_PER_IP", 30)           // wrong codes from one IP per window
+	otpLockout         = envDuration("OTP_LOCKOUT_DURATION", 30*time.Minute)
+)
+
+func envInt(name string, def int) int {
+	if n, err := strconv.Atoi(os.Getenv(name)); err == nil && n > 0 {
+		return n
+	}
+	return def
+}
+
+func envDuration(name string, def time.Duration) time.Duration {
+	if d, err := time.ParseDuration(os.Getenv(name)); err == nil && d > 0 {
+		return d
+	}
+	return def
+}
+
+// OTPThrottleError is returned when an email or IP is locked out or has
+// requested too many codes. RetryAfter tells the client when to try again.
+type OTPThrottleError struct {
+	Reason     string
+	RetryAfter time.Duration
+}
+
+func (e *OTPThrottleError) Error() string {
+	return fmt.Sprintf("%s, retry in %s", e.Reason, e.RetryAfter.Round(time.Second))
+}
+
+func emailKey(email string) string { return "email:" + email }
+func ipKey(ip string) string       { return "ip:" + ip }
+
+// checkOTPLockout fails when any of the keys is locked out.
+func checkOTPLockout(keys ...string) error {
+	for _, key := range keys {
+		var lockedUntil *time.Time
+		err := db.DB.QueryRow(`SELECT locked_until FROM otp_throttle WHERE throttle_key = $1`, key).Scan(&lockedUntil)
+		if err != nil || lockedUntil == nil {
+			continue // no row yet, or a lookup error: the counters below still apply
+		}
+		if wait := time.Until(*lockedUntil); wait > 0 {
+			return &OTPThrottleError{Reason: "too many failed attempts", RetryAfter: wait}
+		}
+	}
+	return nil
+}
+
+// bumpOTPCounter adds one to the sends or failures counter of key, starting
+// a new window when the current one is over, and returns the new count.
+func bumpOTPCounter(key, counter string) (int, error) {
+	_, err := db.DB.Exec(`
+		INSERT INTO otp_throttle (throttle_key, sends, failures, window_started_at)
+		VALUES ($1, 0, 0, now())
+		ON CONFLICT (throttle_key) DO UPDATE SET
+			sends = CASE WHEN otp_throttle.window_started_at < now() - make_interval(secs => $2) THEN 0 ELSE otp_throttle.sends END,
+			failures = CASE WHEN otp_throttle.window_started_at < now() - make_interval(secs => $2) THEN 0 ELSE otp_throttle.failures END,
+			window_started_at = CASE WHEN otp_throttle.window_started_at < now() - make_interval(secs => $2) THEN now() ELSE otp_throttle.window_started_at END`,
+		key, otpWindow.Seconds())
+	if err != nil {
+		return 0, fmt.Errorf("failed to update OTP counters: %w", err)
+	}
+	var n int
+	err = db.DB.QueryRow(`
+		UPDATE otp_throttle SET `+counter+` = `+counter+` + 1
+		WHERE throttle_key = $1 RETURNING `+counter, key).Scan(&n)
+	if err != nil {
+		return 0, fmt.Errorf("failed to update OTP counters: %w", err)
+	}
+	return n, nil
+}
+
+// recordOTPSend counts a code requested for key and fails once more than
+// limit were requested in the current window.
+func recordOTPSend(key string, limit int) error {
+	n, err := bumpOTPCounter(key, "sends")
+	if err != nil {
+		return err
+	}
+	if n > limit {
+		logger.Log.Warn(fmt.Sprintf("[auth] OTP send limit reached for %s (%d in window).", key, n))
+		return &OTPThrottleError{Reason: "too many codes requested", RetryAfter: otpWindow}
+	}
+	return nil
+}
+
+// recordOTPFailure counts a wrong code for key and locks key out once limit
+// is reached.
+func recordOTPFailure(key string, limit int) {
+	n, err := bumpOTPCounter(key, "failures")
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("[auth] %v", err))
+		return
+	}
+	if n < limit {
+		return
+	}
+	_, err = db.DB.Exec(`
+		UPDATE otp_throttle SET locked_until = now() + make_interval(secs => $2), failures = 0
+		WHERE throttle_key = $1`, key, otpLockout.Seconds())
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("[auth] Failed to lock out %s: %v", key, err))
+		return
+	}
+	logger.Log.Warn(fmt.Sprintf("[auth] OTP lockout: %s locked for %s after %d failures.", key, otpLockout, n))
+}
+
+// clearOTPFailures resets the failure counter of key after a good code.
+func clearOTPFailures(key string) {
+	if _, err := db.DB.Exec(`UPDATE otp_throttle SET failures = 0 WHERE throttle_key = $1`, key); err != nil {
+		logger.Log.Error(fmt.Sprintf("[auth] Failed to reset OTP failures for %s: %v", key, err))
+	}
+}
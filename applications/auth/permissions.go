@@ -0,0 +1,134 @@
+package auth
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"supra/applications/user"
+	"supra/logger"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Permission names one thing staff may do.
+type Permission string
+
+const (
+	PermManageConcerts   Permission = "concerts:manage"
+	PermManageSeats      Permission = "seats:manage"
+	PermManagePayments   Permission = "payments:manage"
+	PermManagePromotions Permission = "promotions:manage"
+	PermViewBookings     Permission = "bookings:view"
+	PermManageBookings   Permission = "bookings:manage"
+	PermVerifyBookings   Permission = "bookings:verify"
+	PermManageRefunds    Permission = "refunds:manage"
+	PermCheckIn          Permission = "tickets:checkin"
+	PermViewReports      Permission = "reports:view"
+	PermManageStaff      Permission = "staff:manage"
+	PermManageUsers      Permission = "users:manage"
+)
+
+// rolePermissions maps staff roles to what they may do. Admins may do
+// everything and are not listed.
+var rolePermissions = map[string][]Permission{
+	user.RoleOrganizer: {
+		PermManageConcerts, PermManageSeats, PermManagePayments, PermManagePromotions,
+		PermViewBookings, PermManageBookings, PermVerifyBookings, PermManageRefunds,
+		PermCheckIn, PermViewReports,
+	},
+	user.RoleVerifier:  {PermViewBookings, PermVerifyBookings},
+	user.RoleDoorStaff: {PermCheckIn},
+	user.RoleViewer:    {PermViewReports},
+}
+
+// RolePermissions returns the permissions of every assignable role.
+func RolePermissions() map[string][]Permission {
+	return rolePermissions
+}
+
+func roleHas(role string, perm Permission) bool {
+	if role == user.RoleAdmin {
+		return true
+	}
+	for _, p := range rolePermissions[role] {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// HasPermission reports whether p may use perm for concertID. Roles granted
+// for one concert only count when concertID is that concert; an empty
+// concertID therefore needs a global grant. A staff role stored on the
+// account itself (users.role) counts as a global grant.
+func HasPermission(p Principal, perm Permission, concertID string) (bool, error) {
+	if roleHas(p.Role, perm) {
+		return true, nil
+	}
+	grants, err := user.GetUserStaffRoles(p.UserID)
+	if err != nil {
+		return false, err
+	}
+	for _, g := range grants {
+		if roleHas(g.Role, perm) && (g.ConcertID == "" || strings.EqualFold(g.ConcertID, concertID)) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// RequirePermission only lets callers holding perm through. The concert scope
+// is read from the :concertID route parameter; routes without one need a
+// global grant. The query string is never used, as handlers may ignore it.
+func RequirePermission(perm Permission) echo.MiddlewareFunc {
+	return requirePermission(perm, func(c echo.Context) (string, error) {
+		return c.Param("concertID"), nil
+	})
+}
+
+// RequirePermissionFor is RequirePermission for routes keyed by another
+// resource: concertOf resolves the route parameter param to its concert.
+func RequirePermissionFor(perm Permission, param string, concertOf func(id string) (string, error)) echo.MiddlewareFunc {
+	return requirePermission(perm, func(c echo.Context) (string, error) {
+		return concertOf(c.Param(param))
+	})
+}
+
+func requirePermission(perm Permission, scope func(c echo.Context) (string, error)) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			p := PrincipalFrom(c)
+			if p.UserID == "" {
+				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Invalid or missing token claims"})
+			}
+
+			concertID := ""
+			if !roleHas(p.Role, perm) {
+				var err error
+				if concertID, err = scope(c); err != nil {
+					if strings.Contains(err.Error(), "not found") {
+						return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+					}
+					if strings.Contains(err.Error(), "invalid") {
+						return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+					}
+					logger.Log.Error(fmt.Sprintf("[auth] Permission scope lookup failed on %s: %v", c.Path(), err))
+					return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to authorize request"})
+				}
+			}
+
+			ok, err := HasPermission(p, perm, concertID)
+			if err != nil {
+				logger.Log.Error(fmt.Sprintf("[auth] Permission check failed for %s: %v", p.Email, err))
+				return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to authorize request"})
+			}
+			if !ok {
+				logger.Log.Warn(fmt.Sprintf("[auth] RBAC FAILED for %s on %s: missing %s (concert %q)", p.Email, c.Path(), perm, concertID))
+				return c.JSON(http.StatusForbidden, map[string]string{"error": fmt.Sprintf("Access Forbidden: %s permission required", perm)})
+			}
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"supra/applications/user"
+	"supra/logger"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ErrNotOwner is returned by ownership checks when the caller may not access
+// a resource that belongs to another user.
+var ErrNotOwner = errors.New("resource belongs to another user")
+
+// Principal is the authenticated caller, as set by JWTAuthMiddleware.
+type Principal struct {
+	UserID string
+	Email  string
+	Role   string
+}
+
+// IsAdmin reports whether the caller has the admin role.
+func (p Principal) IsAdmin() bool { return p.Role == user.RoleAdmin }
+
+// PrincipalFrom reads the caller from the request context.
+func PrincipalFrom(c echo.Context) Principal {
+	p := Principal{}
+	p.UserID, _ = c.Get("userID").(string)
+	p.Email, _ = c.Get("userEmail").(string)
+	p.Role, _ = c.Get("userRole").(string)
+	return p
+}
+
+// OwnerCheck returns nil when p may access the resource with the given ID,
+// ErrNotOwner when it belongs to someone else, or a lookup error.
+type OwnerCheck func(resourceID string, p Principal) error
+
+// OwnerOrAdmin only lets the request through when the caller owns the
+// resource named by the route parameter param. Admins bypass the check.
+func OwnerOrAdmin(param string, check OwnerCheck) echo.MiddlewareFunc {
+	return OwnerOrPermitted(param, check, "", nil)
+}
+
+// OwnerOrPermitted is OwnerOrAdmin that also lets through staff holding perm
+// for the resource's concert, as resolved by concertOf.
+func OwnerOrPermitted(param string, check OwnerCheck, perm Permission, concertOf func(id string) (string, error)) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			p := PrincipalFrom(c)
+			if p.IsAdmin() {
+				return next(c)
+			}
+			if p.Email == "" {
+				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Invalid or missing token claims"})
+			}
+
+			id := c.Param(param)
+			if perm != "" {
+				if concertID, err := concertOf(id); err == nil {
+					if ok, _ := HasPermission(p, perm, concertID); ok {
+						return next(c)
+					}
+				}
+			}
+
+			err := check(id, p)
+			switch {
+			case err == nil:
+				return next(c)
+			case errors.Is(err, ErrNotOwner):
+				logger.Log.Warn(fmt.Sprintf("[auth] Ownership check FAILED for %s on %s %s=%s", p.Email, c.Path(), param, id))
+				return c.JSON(http.StatusForbidden, map[string]string{"error": "Access Forbidden: you do not own this resource"})
+			case strings.Contains(err.Error(), "not found"):
+				return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+			case strings.Contains(err.Error(), "invalid"):
+				return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+			default:
+				logger.Log.Error(fmt.Sprintf("[auth] Ownership check error on %s: %v", c.Path(), err))
+				return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to authorize request"})
+			}
+		}
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"math/big"
 	"time"
 
+	"golang.org/x/crypto/bcrypt"
+
 	"supra/applications/user"
 	"supra/db"
 	"supra/logger" // ⬅️ Assuming this import path
@@ -36,9 +38,16 @@ func generateOTP() (string, error) {
 
 // RequestUserOTP finds the user, creates them if necessary, generates an OTP, and sends it.
 // Note: This returns an empty token because the user must verify the OTP first.
-func RequestUserOTP(email string) (token string, role string, err error) {
+// Requests are throttled per email and per client IP (see otp_throttle.go).
+func RequestUserOTP(email, ip string) (token string, role string, err error) {
 	logger.Log.Info(fmt.Sprintf("[auth] Starting OTP process for email: %s", email))
 
+	// 0. Refuse locked-out addresses and IPs, and IPs requesting too many codes
+	if err := checkOTPLockout(emailKey(email), ipKey(ip)); err != nil {
+		logger.Log.Warn(fmt.Sprintf("[auth] OTP request blocked for %s from %s: %v", email, ip, err))
+		return "", "", err
+	}
+
 	// 1. Find or Create User
 	u, err := user.GetUserByEmail(email)
 	if err != nil {
@@ -48,27 +57,48 @@ func RequestUserOTP(email string) (token string, role string, err error) {
 			return "", "", fmt.Errorf("failed to create user: %w", err)
 		}
 		logger.Log.Info(fmt.Sprintf("[auth] User %s created successfully with ID: %s", email, u.UserID))
+	} else if u.DisabledAt != nil {
+		logger.Log.Warn(fmt.Sprintf("[auth] OTP request blocked for %s: account disabled.", email))
+		return "", "", user.ErrAccountDisabled
 	} else {
 		logger.Log.Info(fmt.Sprintf("[auth] User %s found. Role: %s. Reissuing OTP.", email, u.Role))
 	}
 
-	// 2. Generate and Store OTP
+	// 1.5 Resend cooldown, then the per-IP and per-address limits. Requests
+	// refused by the cooldown send nothing, so they don't use up the IP's quota.
+	var sentAt time.Time
+	if err := db.DB.QueryRow(`SELECT sent_at FROM otp_codes WHERE user_email = $1`, email).Scan(&sentAt); err == nil {
+		if wait := otpResendCooldown - time.Since(sentAt); wait > 0 {
+			return "", "", &OTPThrottleError{Reason: "a code was sent recently", RetryAfter: wait}
+		}
+	}
+	if err := recordOTPSend(ipKey(ip), otpMaxSendsIP); err != nil {
+		return "", "", err
+	}
+	if err := recordOTPSend(emailKey(email), otpMaxSendsEmail); err != nil {
+		return "", "", err
+	}
+
+	// 2. Generate and Store OTP (only its bcrypt hash is kept)
 	code, err := generateOTP()
 	if err != nil {
 		return "", "", fmt.Errorf("failed to generate OTP: %w", err)
 	}
-	logger.Log.Info(fmt.Sprintf("[auth] Generated OTP code: %s", code)) // WARNING: Do not log OTPs in production!
+	codeHash, err := bcrypt.GenerateFromPassword([]byte(code), bcrypt.DefaultCost)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to hash OTP: %w", err)
+	}
 
 	expiresAt := time.Now().Add(otpExpiry)
 
 	// Use ON CONFLICT DO UPDATE to handle resend requests gracefully
 	const insertOTP = `
-		INSERT INTO otp_codes (code, user_email, expires_at)
-		VALUES ($1, $2, $3)
-		ON CONFLICT (user_email) DO UPDATE 
-		SET code = EXCLUDED.code, expires_at = EXCLUDED.expires_at;`
+		INSERT INTO otp_codes (code_hash, user_email, expires_at, attempts, sent_at)
+		VALUES ($1, $2, $3, 0, now())
+		ON CONFLICT (user_email) DO UPDATE
+		SET code_hash = EXCLUDED.code_hash, expires_at = EXCLUDED.expires_at, attempts = 0, sent_at = EXCLUDED.sent_at;`
 
-	_, err = db.DB.ExecContext(context.Background(), insertOTP, code, email, expiresAt)
+	_, err = db.DB.ExecContext(context.Background(), insertOTP, string(codeHash), email, expiresAt)
 	if err != nil {
 		logger.Log.Error(fmt.Sprintf("[auth] Failed to save/update OTP for %s: %v", email, err))
 		return "", "", fmt.Errorf("failed to save OTP: %w", err)
@@ -0,0 +1,198 @@
+package auth
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"supra/applications/user"
+	"supra/db"
+	"supra/logger"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token already used: session revoked")
+)
+
+// TokenPair is what a successful login or refresh returns. Token is the
+// short-lived access JWT; RefreshToken is single use and is exchanged for a
+// new pair at POST /refresh-token.
+type TokenPair struct {
+	Token        string    `json:"token"`
+	RefreshToken string    `json:"refreshToken"`
+	ExpiresAt    time.Time `json:"expiresAt"` // when Token expires
+	Role         string    `json:"role"`
+}
+
+// RefreshTokenTTL reads JWT_REFRESH_TTL (a Go duration, default 30 days).
+// Every refresh restarts it, so only sessions idle for that long expire.
+func RefreshTokenTTL() time.Duration {
+	if d, err := time.ParseDuration(os.Getenv("JWT_REFRESH_TTL")); err == nil && d > 0 {
+		return d
+	}
+	return 30 * 24 * time.Hour
+}
+
+// startSession opens a new refresh token family for the user and returns
+// its first token pair.
+func startSession(u *user.User) (*TokenPair, error) {
+	if _, err := db.DB.Exec(`DELETE FROM refresh_token WHERE user_id = $1 AND expires_at < now()`, u.UserID); err != nil {
+		logger.Log.Warn(fmt.Sprintf("[auth] Failed to prune expired refresh tokens for %s: %v", u.Email, err))
+	}
+
+	sessionID := uuid.New()
+	refreshToken, err := storeRefreshToken(db.DB, sessionID, u.UserID)
+	if err != nil {
+		return nil, err
+	}
+	return tokenPair(u, sessionID, refreshToken)
+}
+
+// RefreshSession exchanges a refresh token for a new token pair. Each token
+// works once: presenting a used token again means it leaked, so the whole
+// session is revoked.
+func RefreshSession(refreshToken string) (*TokenPair, error) {
+	tx, err := db.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var (
+		sessionID, userID uuid.UUID
+		expiresAt         time.Time
+		usedAt, revokedAt *time.Time
+		tokenHash         = hashToken(refreshToken)
+	)
+	err = tx.QueryRow(`
+		SELECT session_id, user_id, expires_at, used_at, revoked_at
+		FROM refresh_token WHERE token_hash = $1 FOR UPDATE`, tokenHash).
+		Scan(&sessionID, &userID, &expiresAt, &usedAt, &revokedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up refresh token: %w", err)
+	}
+
+	switch {
+	case revokedAt != nil, time.Now().After(expiresAt):
+		return nil, ErrInvalidRefreshToken
+	case usedAt != nil:
+		if _, err := tx.Exec(`UPDATE refresh_token SET revoked_at = now() WHERE session_id = $1 AND revoked_at IS NULL`, sessionID); err != nil {
+			return nil, fmt.Errorf("failed to revoke session: %w", err)
+		}
+		if err := tx.Commit(); err != nil {
+			return nil, fmt.Errorf("commit failed: %w", err)
+		}
+		logger.Log.Warn(fmt.Sprintf("[auth] Refresh token reuse detected for user %s. Session %s revoked.", userID, sessionID))
+		return nil, ErrRefreshTokenReused
+	}
+
+	u, err := user.GetUserByID(userID.String())
+	if err != nil {
+		return nil, err
+	}
+	if u.DisabledAt != nil {
+		if _, err := tx.Exec(`UPDATE refresh_token SET revoked_at = now() WHERE user_id = $1 AND revoked_at IS NULL`, userID); err != nil {
+			return nil, fmt.Errorf("failed to revoke sessions: %w", err)
+		}
+		if err := tx.Commit(); err != nil {
+			return nil, fmt.Errorf("commit failed: %w", err)
+		}
+		return nil, user.ErrAccountDisabled
+	}
+
+	if _, err := tx.Exec(`UPDATE refresh_token SET used_at = now() WHERE token_hash = $1`, tokenHash); err != nil {
+		return nil, fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+	next, err := storeRefreshToken(tx, sessionID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit failed: %w", err)
+	}
+
+	logger.Log.Info(fmt.Sprintf("[auth] Session %s refreshed for %s.", sessionID, u.Email))
+	return tokenPair(u, sessionID, next)
+}
+
+// Logout revokes the session the refresh token belongs to. Unknown tokens
+// are ignored so logging out twice is harmless.
+func Logout(refreshToken string) error {
+	res, err := db.DB.Exec(`
+		UPDATE refresh_token SET revoked_at = now()
+		WHERE revoked_at IS NULL
+		  AND session_id = (SELECT session_id FROM refresh_token WHERE token_hash = $1)`, hashToken(refreshToken))
+	if err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n > 0 {
+		logger.Log.Info("[auth] Session revoked on logout.")
+	}
+	return nil
+}
+
+// RevokeAllSessions revokes every refresh token of the user, signing them
+// out everywhere once their current access tokens expire.
+func RevokeAllSessions(userID string) (int64, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return 0, fmt.Errorf("invalid user ID: %w", err)
+	}
+	res, err := db.DB.Exec(`
+		UPDATE refresh_token SET revoked_at = now()
+		WHERE user_id = $1 AND revoked_at IS NULL`, id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	logger.Log.Info(fmt.Sprintf("[auth] Revoked %d refresh tokens of user %s.", n, userID))
+	return n, nil
+}
+
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// storeRefreshToken saves the hash of a new refresh token and returns the
+// token itself.
+func storeRefreshToken(ex execer, sessionID, userID uuid.UUID) (string, error) {
+	token, err := newOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+	_, err = ex.Exec(`
+		INSERT INTO refresh_token (token_hash, session_id, user_id, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, now())`, hashToken(token), sessionID, userID, time.Now().Add(RefreshTokenTTL()))
+	if err != nil {
+		return "", fmt.Errorf("failed to store refresh token: %w", err)
+	}
+	return token, nil
+}
+
+func tokenPair(u *user.User, sessionID uuid.UUID, refreshToken string) (*TokenPair, error) {
+	token, expiresAt, err := GenerateJWT(u.UserID.String(), u.Email, u.Role, sessionID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate JWT: %w", err)
+	}
+	return &TokenPair{Token: token, RefreshToken: refreshToken, ExpiresAt: expiresAt, Role: u.Role}, nil
+}
+
+// newOpaqueToken returns 32 random bytes, hex encoded. Only its hash is
+// stored (see hashToken).
+func newOpaqueToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -8,73 +8,104 @@ import (
 	"os"
 	"time"
 
+	"golang.org/x/crypto/bcrypt"
+
 	"supra/applications/user"
 	"supra/db"
 	"supra/logger" // ⬅️ Assuming this import path
 )
 
 // VerifyOTP checks the submitted code, logs the user in, and deletes the code.
-func VerifyOTP(email, code string) (token string, role string, err error) {
+// Each code allows otpMaxAttempts guesses, and repeated failures lock out the
+// email and the client IP (see otp_throttle.go).
+func VerifyOTP(email, code, ip string) (*TokenPair, error) {
 	logger.Log.Info(fmt.Sprintf("[auth] Verification attempt for email: %s", email))
 
+	// 0. Refuse locked-out addresses and IPs before looking at the code
+	if err := checkOTPLockout(emailKey(email), ipKey(ip)); err != nil {
+		logger.Log.Warn(fmt.Sprintf("[auth] Verification blocked for %s from %s: %v", email, ip, err))
+		return nil, err
+	}
+
 	// 1. Retrieve the user record first
 	u, err := user.GetUserByEmail(email)
 	if err != nil {
 		logger.Log.Warn(fmt.Sprintf("[auth] Verification failed for %s: User not found.", email))
-		return "", "", errors.New("invalid code or user not found")
+		recordOTPFailure(ipKey(ip), otpMaxFailureIP)
+		return nil, errors.New("invalid code or user not found")
+	}
+	if u.DisabledAt != nil {
+		logger.Log.Warn(fmt.Sprintf("[auth] Verification blocked for %s: account disabled.", email))
+		return nil, user.ErrAccountDisabled
 	}
 
 	if os.Getenv("OTP_ENABLED") == "false" {
 
-		if u.Role == user.RoleAdmin {
-			return "", "", errors.New("Fuck off! You idiot...")
+		if usesPassword(u.Role) {
+			return nil, errors.New("Fuck off! You idiot...")
 		}
 
-		// 5. Generate and return the JWT
-		token, err = GenerateJWT(u.UserID.String(), u.Email, u.Role)
+		claimBookings(u)
+		user.RecordLogin(u.UserID)
+
+		// 5. Start a session (access JWT + refresh token)
+		pair, err := startSession(u)
 		if err != nil {
-			logger.Log.Error(fmt.Sprintf("[auth] Failed to generate final JWT for %s: %v", email, err))
-			return "", "", fmt.Errorf("failed to generate JWT: %w", err)
+			logger.Log.Error(fmt.Sprintf("[auth] Failed to start session for %s: %v", email, err))
+			return nil, err
 		}
 
 		logger.Log.Info(fmt.Sprintf("[auth] Verification successful for %s. JWT issued. Role: %s.", email, u.Role))
-		return token, u.Role, nil
+		return pair, nil
 	}
 
 	logger.Log.Info(fmt.Sprintf("[auth] User %s found. Proceeding to OTP validation.", email))
 
-	// 2. Check OTP in the database
-	const selectOTP = `
-		SELECT expires_at, code 
-		FROM otp_codes 
-		WHERE user_email = $1 AND code = $2`
-
-	var expiresAt time.Time
-	var storedCode string
-
-	row := db.DB.QueryRow(selectOTP, email, code)
-	err = row.Scan(&expiresAt, &storedCode)
+	// 2. Check OTP in the database. The guess is counted before comparing, so
+	// concurrent guesses cannot exceed the per-code limit.
+	const countAttempt = `
+		UPDATE otp_codes SET attempts = attempts + 1
+		WHERE user_email = $1
+		RETURNING code_hash, expires_at, attempts`
+	const deleteOTP = `DELETE FROM otp_codes WHERE user_email = $1`
 
+	var (
+		codeHash  string
+		expiresAt time.Time
+		attempts  int
+	)
+	err = db.DB.QueryRow(countAttempt, email).Scan(&codeHash, &expiresAt, &attempts)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			logger.Log.Warn(fmt.Sprintf("[auth] Verification failed for %s: OTP not found in DB or code mismatch.", email))
-			return "", "", errors.New("invalid OTP code")
+			logger.Log.Warn(fmt.Sprintf("[auth] Verification failed for %s: no OTP outstanding.", email))
+			recordOTPFailure(ipKey(ip), otpMaxFailureIP)
+			return nil, errors.New("invalid OTP code")
 		}
 		logger.Log.Error(fmt.Sprintf("[auth] DB error retrieving OTP for %s: %v", email, err))
-		return "", "", errors.New("database error during verification")
+		return nil, errors.New("database error during verification")
 	}
 
-	// Log successful code match before checking expiry
-	logger.Log.Info(fmt.Sprintf("[auth] OTP code matched for %s. Checking expiry...", email))
-
-	// 3. Check for Expiration
+	// 3. Check for Expiration and the per-code attempt limit
 	if time.Now().After(expiresAt) {
 		logger.Log.Warn(fmt.Sprintf("[auth] Verification failed for %s: OTP expired at %s.", email, expiresAt.Format(time.RFC3339)))
-		return "", "", errors.New("OTP expired. Please request a new one")
+		_, _ = db.DB.Exec(deleteOTP, email)
+		return nil, errors.New("OTP expired. Please request a new one")
 	}
+	if attempts > otpMaxAttempts {
+		logger.Log.Warn(fmt.Sprintf("[auth] Verification failed for %s: attempt limit reached, code discarded.", email))
+		_, _ = db.DB.Exec(deleteOTP, email)
+		return nil, errors.New("too many attempts. Please request a new OTP")
+	}
+	if bcrypt.CompareHashAndPassword([]byte(codeHash), []byte(code)) != nil {
+		logger.Log.Warn(fmt.Sprintf("[auth] Verification failed for %s from %s: code mismatch (attempt %d/%d).", email, ip, attempts, otpMaxAttempts))
+		recordOTPFailure(emailKey(email), otpMaxFailureEmail)
+		recordOTPFailure(ipKey(ip), otpMaxFailureIP)
+		return nil, errors.New("invalid OTP code")
+	}
+	logger.Log.Info(fmt.Sprintf("[auth] OTP code matched for %s.", email))
+	clearOTPFailures(emailKey(email))
 
 	// 4. Clean up the OTP code (prevent reuse)
-	const deleteOTP = `DELETE FROM otp_codes WHERE user_email = $1`
 	if _, err := db.DB.Exec(deleteOTP, email); err != nil {
 		// Log the failure to cleanup, but do not stop the login process
 		logger.Log.Error(fmt.Sprintf("[auth] Cleanup failed: Failed to delete used OTP for %s: %v", email, err))
@@ -82,13 +113,32 @@ func VerifyOTP(email, code string) (token string, role string, err error) {
 		logger.Log.Info(fmt.Sprintf("[auth] OTP cleaned up successfully for %s.", email))
 	}
 
-	// 5. Generate and return the JWT
-	token, err = GenerateJWT(u.UserID.String(), u.Email, u.Role)
+	// 4.5 The address is now proven: link bookings made under it to the account
+	claimBookings(u)
+	user.RecordLogin(u.UserID)
+
+	// 5. Start a session (access JWT + refresh token)
+	pair, err := startSession(u)
 	if err != nil {
-		logger.Log.Error(fmt.Sprintf("[auth] Failed to generate final JWT for %s: %v", email, err))
-		return "", "", fmt.Errorf("failed to generate JWT: %w", err)
+		logger.Log.Error(fmt.Sprintf("[auth] Failed to start session for %s: %v", email, err))
+		return nil, err
 	}
 
 	logger.Log.Info(fmt.Sprintf("[auth] Verification successful for %s. JWT issued. Role: %s.", email, u.Role))
-	return token, u.Role, nil
+	return pair, nil
+}
+
+// claimBookings links bookings made under the user's email before the
+// account existed (or before bookings carried an owner) to the account.
+func claimBookings(u *user.User) {
+	res, err := db.DB.Exec(`
+		UPDATE booking SET user_id = $1
+		WHERE user_id IS NULL AND LOWER(booking_email) = LOWER($2)`, u.UserID, u.Email)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("[auth] Failed to link bookings to %s: %v", u.Email, err))
+		return
+	}
+	if n, _ := res.RowsAffected(); n > 0 {
+		logger.Log.Info(fmt.Sprintf("[auth] Linked %d existing bookings to user %s.", n, u.UserID))
+	}
 }
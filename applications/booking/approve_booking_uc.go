@@ -2,6 +2,8 @@ package booking
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
@@ -12,6 +14,45 @@ import (
 	"github.com/google/uuid"
 )
 
+// ErrNotApprovable is returned when a booking can no longer be approved or
+// confirmed, e.g. because it was cancelled or refunded.
+var ErrNotApprovable = errors.New("booking cannot be approved in its current status")
+
+// reclaimSeatsTx locks a booking about to become APPROVED or CONFIRMED and
+// makes sure it holds its seats. Cancelled bookings are refused. A finally
+// rejected booking gave its seats back, so they are taken again; this fails
+// with ErrNotEnoughSeats when they are gone.
+func reclaimSeatsTx(tx *sql.Tx, id uuid.UUID) error {
+	var (
+		status, code, seatID string
+		quantity             int
+	)
+	err := tx.QueryRow(`
+		SELECT booking_status, COALESCE(rejection_code, ''), seat_id, seat_quantity
+		FROM booking WHERE booking_id = $1 FOR UPDATE`, id).Scan(&status, &code, &seatID, &quantity)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("%w: %s", ErrBookingNotFound, id)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock booking: %w", err)
+	}
+	if status == CANCELLED {
+		return fmt.Errorf("%w: booking %s is cancelled", ErrNotApprovable, id)
+	}
+	if holdsSeats(status, code) {
+		return nil
+	}
+
+	if _, err := validateAndUpdateSeatTx(tx, seatID, quantity); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`UPDATE booking SET seats_reserved = TRUE WHERE booking_id = $1`, id); err != nil {
+		return fmt.Errorf("failed to mark seats reserved: %w", err)
+	}
+	logger.Log.Info(fmt.Sprintf("[approve-booking-uc] Retook %d seats of %s for rejected booking %s", quantity, seatID, id))
+	return nil
+}
+
 // ApproveBookingUC approves a booking, updates DB, and emails a ticket PDF.
 func ApproveBookingUC(bookingID string) (*Booking, error) {
 	logger.Log.Info(fmt.Sprintf("[approve-booking-uc] Processing booking approval for: %s", bookingID))
@@ -27,6 +68,10 @@ func ApproveBookingUC(bookingID string) (*Booking, error) {
 	}
 	defer func() { _ = tx.Rollback() }()
 
+	if err := reclaimSeatsTx(tx, id); err != nil {
+		return nil, err
+	}
+
 	// --- Update booking status ---
 	if _, err = tx.Exec(`UPDATE booking SET booking_status='APPROVED', updated_at=$2 WHERE booking_id=$1`,
 		id, time.Now()); err != nil {
@@ -37,6 +82,7 @@ func ApproveBookingUC(bookingID string) (*Booking, error) {
 		return nil, fmt.Errorf("commit failed: %w", err)
 	}
 	logger.Log.Info(fmt.Sprintf("[approve-booking-uc] ✅ Booking %s marked APPROVED.", bookingID))
+	issueBookingInvoice(bookingID)
 
 	// --- Generate eTicket PDF ---
 	bk, pdfBytes, err := GenerateTicketPDF(bookingID)
@@ -46,16 +92,18 @@ func ApproveBookingUC(bookingID string) (*Booking, error) {
 
 	// --- Send Email with PDF ---
 	if emailErr := auth.SendBookingApprovalMail(
-		bk.BookingEmail,
+		bk.NotifyEmail(),
 		bookingID,
 		bk.SeatType,
 		bk.SeatQuantity,
 		bk.TotalAmount,
+		bk.Currency,
+		bk.Price.Lines(),
 		pdfBytes,
 	); emailErr != nil {
 		logger.Log.Warn(fmt.Sprintf("[approve-booking-uc] ⚠️ Email sending failed: %v", emailErr))
 	} else {
-		logger.Log.Info(fmt.Sprintf("[approve-booking-uc] ✉️ Approval email sent to %s", bk.BookingEmail))
+		logger.Log.Info(fmt.Sprintf("[approve-booking-uc] ✉️ Approval email sent to %s", bk.NotifyEmail()))
 	}
 
 	return bk, nil
@@ -1,29 +1,85 @@
 package booking
 
 import (
+	"strings"
 	"time"
 
+	"supra/applications/auth"
+	"supra/applications/paymentdetails"
+
 	"github.com/google/uuid"
 )
 
 type Booking struct {
 	BookingID        uuid.UUID `json:"bookingID"`
-	BookingEmail     string    `json:"bookingEmail"`
+	BookingEmail     string    `json:"bookingEmail"`           // owner's account email
+	UserID           string    `json:"userID,omitempty"`       // owner's account; empty for unclaimed guest bookings
+	ContactEmail     string    `json:"contactEmail,omitempty"` // booking mails go here when set
 	BookingStatus    string    `json:"bookingStatus"`
-	PaymentDetailsID string    `json:"paymentDetailsID"` // Should be UUID in production
-	ReceiptImage     []byte    `json:"receiptImage"`     // Stored as BYTEA/BLOB
+	PaymentDetailsID string    `json:"paymentDetailsID"`       // Should be UUID in production
+	ReceiptImage     []byte    `json:"receiptImage,omitempty"` // Only loaded by the receipt endpoint
+	ReceiptHash      string    `json:"receiptHash,omitempty"`  // SHA-256 of the stored blob
+	ReceiptURL       string    `json:"receiptURL,omitempty"`   // API path serving the receipt
+	ReceiptType      string    `json:"receiptContentType,omitempty"`
+	ThumbnailURL     string    `json:"receiptThumbnailURL,omitempty"`
 	SeatQuantity     int       `json:"seatQuantity"`
 	ConcertID        string    `json:"concertId"`
 	SeatID           string    `json:"seatID"`
 	SeatType         string    `json:"seatType"`
 	TotalAmount      float64   `json:"totalAmount"`
+	Currency         string    `json:"currency"`       // of TotalAmount; the payment method's currency when booked
 	ParticipantIDs   []string  `json:"participantIDs"` // Stored as JSONB
 	CreatedAt        time.Time `json:"createdAt"`
 	UserNotes        string    `json:"userNotes"`
+	RejectionCode    string    `json:"rejectionCode,omitempty"`
+	RejectionReason  string    `json:"rejectionReason,omitempty"`
+	PaymentReference string    `json:"paymentReference,omitempty"` // UPI transaction reference / bank narration
+	TransferredFrom  string    `json:"transferredFrom,omitempty"`  // booking this one was split from by a ticket transfer
+	TicketCode       string    `json:"-"`                          // set once tickets are reissued; older QR codes stop working
+	SeatsReserved    bool      `json:"-"`                          // seats were taken from the category's availability on create
+
+	// Price shows how TotalAmount was computed (unit price, promo discount).
+	Price *PriceBreakdown `json:"priceBreakdown,omitempty"`
+
+	// Billing holds optional buyer details for the invoice.
+	Billing *BillingDetails `json:"billing,omitempty"`
+
+	// DuplicateReceipt is set when the receipt matches another booking's receipt.
+	DuplicateReceipt *ReceiptMatch `json:"duplicateReceipt,omitempty"`
+
+	// OCR holds details read from the receipt once the OCR worker has processed it.
+	OCR *ReceiptOCR `json:"ocr,omitempty"`
+
+	// UPI is set for unpaid bookings whose payment method is UPI.
+	UPI *paymentdetails.UPIIntent `json:"upi,omitempty"`
+
+	// Reupload is only set for rejected bookings and guides the user through re-uploading.
+	Reupload *RejectionReason `json:"reupload,omitempty"`
 }
 
 const (
 	VERIFYING = "VERIFYING"
 	CONFIRMED = "CONFIRMED"
 	CANCELLED = "CANCELLED"
+	APPROVED  = "APPROVED"
+	REJECTED  = "REJECTED"
+
+	PENDING_VERIFICATION = "PENDING_VERIFICATION"
 )
+
+// NotifyEmail is where customer mails for the booking are sent.
+func (b *Booking) NotifyEmail() string {
+	if b.ContactEmail != "" {
+		return b.ContactEmail
+	}
+	return b.BookingEmail
+}
+
+// OwnedBy reports whether p owns the booking. Bookings made before accounts
+// were linked, and not yet claimed, fall back to the booking email.
+func (b *Booking) OwnedBy(p auth.Principal) bool {
+	if b.UserID != "" && p.UserID != "" {
+		return b.UserID == p.UserID
+	}
+	return strings.EqualFold(b.BookingEmail, p.Email)
+}
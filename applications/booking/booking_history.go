@@ -0,0 +1,72 @@
+package booking
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"supra/db"
+	"supra/logger"
+
+	"github.com/google/uuid"
+)
+
+// BookingEvent is one entry in a booking's change history.
+type BookingEvent struct {
+	EventID   string          `json:"eventID"`
+	BookingID string          `json:"bookingID"`
+	Actor     string          `json:"actor"` // email of whoever made the change
+	Action    string          `json:"action"`
+	Details   json.RawMessage `json:"details,omitempty"`
+	CreatedAt time.Time       `json:"createdAt"`
+}
+
+// History actions
+const (
+	EventParticipantsUpdated = "PARTICIPANTS_UPDATED"
+	EventTransferredOut      = "TRANSFERRED_OUT"
+	EventTransferredIn       = "TRANSFERRED_IN"
+	EventCheckedIn           = "CHECKED_IN"
+)
+
+// recordEventTx appends an entry to the booking history.
+func recordEventTx(tx *sql.Tx, bookingID uuid.UUID, actor, action string, details interface{}) error {
+	detailsJSON, _ := json.Marshal(details)
+	_, err := tx.Exec(`
+		INSERT INTO booking_event (event_id, booking_id, actor, action, details, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		uuid.New(), bookingID, actor, action, detailsJSON, time.Now())
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("[booking-history] Failed to record %s for %s: %v", action, bookingID, err))
+		return fmt.Errorf("failed to record booking history: %w", err)
+	}
+	return nil
+}
+
+// GetBookingHistoryUC lists a booking's history, oldest first.
+func GetBookingHistoryUC(bookingID string) ([]*BookingEvent, error) {
+	rows, err := db.DB.Query(`
+		SELECT event_id, booking_id, actor, action, details, created_at
+		FROM booking_event
+		WHERE booking_id = $1
+		ORDER BY created_at`, bookingID)
+	if err != nil {
+		return nil, fmt.Errorf("database query error: %w", err)
+	}
+	defer rows.Close()
+
+	events := make([]*BookingEvent, 0)
+	for rows.Next() {
+		e := &BookingEvent{}
+		var details []byte
+		if err := rows.Scan(&e.EventID, &e.BookingID, &e.Actor, &e.Action, &details, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("error scanning booking history row: %w", err)
+		}
+		if len(details) > 0 && string(details) != "null" {
+			e.Details = details
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
@@ -0,0 +1,94 @@
+package booking
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"supra/applications/auth"
+	"supra/db"
+
+	"github.com/google/uuid"
+)
+
+// CheckBookingOwner is the auth.OwnerCheck for routes keyed by :bookingID.
+func CheckBookingOwner(bookingID string, p auth.Principal) error {
+	id, err := uuid.Parse(bookingID)
+	if err != nil {
+		return fmt.Errorf("invalid booking ID format: %w", err)
+	}
+
+	bk := &Booking{}
+	err = db.DB.QueryRow(`
+		SELECT booking_email, COALESCE(user_id::text, '') FROM booking WHERE booking_id = $1`,
+		id).Scan(&bk.BookingEmail, &bk.UserID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("booking %s not found", bookingID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to look up booking owner: %w", err)
+	}
+	if !bk.OwnedBy(p) {
+		return auth.ErrNotOwner
+	}
+	return nil
+}
+
+// CheckParticipantOwner is the auth.OwnerCheck for routes keyed by a
+// participant's :userID: the caller must own a booking listing them.
+func CheckParticipantOwner(userID string, p auth.Principal) error {
+	if _, err := uuid.Parse(userID); err != nil {
+		return fmt.Errorf("invalid user ID format: %w", err)
+	}
+
+	var owns bool
+	err := db.DB.QueryRow(`
+		SELECT EXISTS (
+			SELECT 1 FROM booking
+			WHERE participant_ids ? $2
+			  AND (user_id = NULLIF($3, '')::uuid OR (user_id IS NULL AND LOWER(booking_email) = LOWER($1)))
+		)`, p.Email, userID, p.UserID).Scan(&owns)
+	if err != nil {
+		return fmt.Errorf("failed to look up participant owner: %w", err)
+	}
+	if !owns {
+		return auth.ErrNotOwner
+	}
+	return nil
+}
+
+// ParticipantConcertIDOf returns the concert of the booking listing a
+// participant, for scoping staff permissions on routes keyed by :userID.
+// Participants on no booking resolve to no concert and need a global grant.
+func ParticipantConcertIDOf(userID string) (string, error) {
+	if _, err := uuid.Parse(userID); err != nil {
+		return "", fmt.Errorf("invalid user ID format: %w", err)
+	}
+	var concertID string
+	err := db.DB.QueryRow(`SELECT concert_id FROM booking WHERE participant_ids ? $1 LIMIT 1`, userID).Scan(&concertID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to look up participant concert: %w", err)
+	}
+	return concertID, nil
+}
+
+// ConcertIDOf returns the concert a booking belongs to, for scoping staff
+// permissions on routes keyed by :bookingID.
+func ConcertIDOf(bookingID string) (string, error) {
+	id, err := uuid.Parse(bookingID)
+	if err != nil {
+		return "", fmt.Errorf("invalid booking ID format: %w", err)
+	}
+	var concertID string
+	err = db.DB.QueryRow(`SELECT concert_id FROM booking WHERE booking_id = $1`, id).Scan(&concertID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("booking %s not found", bookingID)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to look up booking concert: %w", err)
+	}
+	return concertID, nil
+}
@@ -0,0 +1,169 @@
+package booking_test
+
+import (
+	"database/sql/driver"
+	"net/http"
+	"strings"
+	"testing"
+
+	"supra/applications/auth"
+	"supra/applications/auth/authtest"
+	"supra/applications/booking"
+	"supra/applications/user"
+	"supra/db/dbtest"
+)
+
+const (
+	doorID = "ad506e78-4fa2-407d-9e95-516273849505"
+
+	missingBooking  = "2f304152-6374-4859-aabb-ccddeeff0011"
+	concertBBooking = "74859607-b8c9-4dae-8f00-112233445566"
+
+	aliceParticipant = "30415263-7485-496a-bbcc-ddeeff001122"
+	guestParticipant = "41526374-8596-4a7b-ccdd-eeff00112233"
+
+	concertA = "52637485-96a7-4b8c-ddee-ff0011223344"
+	concertB = "63748596-a7b8-4c9d-eeff-001122334455"
+)
+
+// door holds a door staff grant for concertA only.
+var door = auth.Principal{UserID: doorID, Email: "door@example.com", Role: user.RoleUser}
+
+type fixtureBooking struct {
+	email, userID, concertID string
+	participants             []string
+}
+
+var fixtureBookings = map[string]fixtureBooking{
+	dbtest.AliceBooking: {email: "alice@example.com", userID: dbtest.AliceID, concertID: concertA, participants: []string{aliceParticipant}},
+	dbtest.GuestBooking: {email: "guest@example.com", concertID: concertA, participants: []string{guestParticipant}},
+}
+
+// fakeBookingDB serves the fixture bookings to the ownership queries. The
+// participant query's WHERE clause is mirrored in Go.
+func fakeBookingDB(query string, args []driver.Value) (*dbtest.Rows, error) {
+	switch {
+	case strings.Contains(query, "SELECT booking_email, COALESCE(user_id::text, '') FROM booking"):
+		bk, ok := fixtureBookings[args[0].(string)]
+		if !ok {
+			return dbtest.NoRows("booking_email", "user_id"), nil
+		}
+		return dbtest.Row([]string{"booking_email", "user_id"}, bk.email, bk.userID), nil
+
+	case strings.Contains(query, "SELECT concert_id FROM booking"):
+		bk, ok := fixtureBookings[args[0].(string)]
+		if !ok {
+			return dbtest.NoRows("concert_id"), nil
+		}
+		return dbtest.Row([]string{"concert_id"}, bk.concertID), nil
+
+	case strings.Contains(query, "participant_ids ?"):
+		email, participantID, userID := args[0].(string), args[1].(string), args[2].(string)
+		owns := false
+		for _, bk := range fixtureBookings {
+			listed := false
+			for _, id := range bk.participants {
+				listed = listed || id == participantID
+			}
+			owner := (userID != "" && bk.userID == userID) ||
+				(bk.userID == "" && strings.EqualFold(bk.email, email))
+			owns = owns || (listed && owner)
+		}
+		return dbtest.Row([]string{"exists"}, owns), nil
+
+	case strings.Contains(query, "FROM staff_role"):
+		r := &dbtest.Rows{Columns: []string{"role", "concert_id"}}
+		if args[0].(string) == doorID {
+			r.Values = append(r.Values, []driver.Value{user.RoleDoorStaff, concertA})
+		}
+		return r, nil
+	}
+	return nil, nil
+}
+
+func TestOwnerOrAdminBooking(t *testing.T) {
+	dbtest.Use(t, fakeBookingDB)
+	mw := auth.OwnerOrAdmin("bookingID", booking.CheckBookingOwner)
+
+	tests := []struct {
+		name      string
+		who       auth.Principal
+		bookingID string
+		want      int
+	}{
+		{"owner", authtest.Alice, dbtest.AliceBooking, http.StatusOK},
+		{"other user", authtest.Bob, dbtest.AliceBooking, http.StatusForbidden},
+		{"admin bypasses", authtest.Admin, dbtest.AliceBooking, http.StatusOK},
+		{"owner email on another account", auth.Principal{UserID: dbtest.BobID, Email: "alice@example.com", Role: user.RoleUser}, dbtest.AliceBooking, http.StatusForbidden},
+		{"unclaimed booking by email", authtest.Guest, dbtest.GuestBooking, http.StatusOK},
+		{"unclaimed booking, other user", authtest.Alice, dbtest.GuestBooking, http.StatusForbidden},
+		{"unclaimed booking, admin", authtest.Admin, dbtest.GuestBooking, http.StatusOK},
+		{"missing booking", authtest.Alice, missingBooking, http.StatusNotFound},
+		{"malformed ID", authtest.Alice, "not-a-uuid", http.StatusBadRequest},
+		{"no claims", auth.Principal{}, dbtest.AliceBooking, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := authtest.Serve(t, tt.who, "/bookings/:bookingID", "/bookings/"+tt.bookingID, mw); got != tt.want {
+				t.Errorf("status = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOwnerOrAdminParticipant(t *testing.T) {
+	dbtest.Use(t, fakeBookingDB)
+	mw := auth.OwnerOrAdmin("userID", booking.CheckParticipantOwner)
+
+	tests := []struct {
+		name          string
+		who           auth.Principal
+		participantID string
+		want          int
+	}{
+		{"booking owner", authtest.Alice, aliceParticipant, http.StatusOK},
+		{"other user", authtest.Bob, aliceParticipant, http.StatusForbidden},
+		{"admin bypasses", authtest.Admin, aliceParticipant, http.StatusOK},
+		{"unclaimed booking by email", authtest.Guest, guestParticipant, http.StatusOK},
+		{"unclaimed booking, other user", authtest.Alice, guestParticipant, http.StatusForbidden},
+		{"malformed ID", authtest.Alice, "not-a-uuid", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := authtest.Serve(t, tt.who, "/participants/:userID", "/participants/"+tt.participantID, mw); got != tt.want {
+				t.Errorf("status = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOwnerOrPermittedBooking(t *testing.T) {
+	dbtest.Use(t, fakeBookingDB)
+	mw := auth.OwnerOrPermitted("bookingID", booking.CheckBookingOwner, auth.PermCheckIn, booking.ConcertIDOf)
+
+	// The door staff grant only covers concertA.
+	fixtureBookings[concertBBooking] = fixtureBooking{email: "alice@example.com", userID: dbtest.AliceID, concertID: concertB}
+	t.Cleanup(func() { delete(fixtureBookings, concertBBooking) })
+
+	tests := []struct {
+		name      string
+		who       auth.Principal
+		bookingID string
+		want      int
+	}{
+		{"owner", authtest.Alice, dbtest.AliceBooking, http.StatusOK},
+		{"other user", authtest.Bob, dbtest.AliceBooking, http.StatusForbidden},
+		{"admin bypasses", authtest.Admin, dbtest.AliceBooking, http.StatusOK},
+		{"staff granted for the concert", door, dbtest.AliceBooking, http.StatusOK},
+		{"staff granted for another concert", door, concertBBooking, http.StatusForbidden},
+		{"unclaimed booking by email", authtest.Guest, dbtest.GuestBooking, http.StatusOK},
+		{"unclaimed booking, other user", authtest.Bob, dbtest.GuestBooking, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := authtest.Serve(t, tt.who, "/bookings/:bookingID", "/bookings/"+tt.bookingID, mw); got != tt.want {
+				t.Errorf("status = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
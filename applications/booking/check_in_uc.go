@@ -0,0 +1,82 @@
+package booking
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"supra/applications/participant"
+	"supra/db"
+	"supra/logger"
+	"supra/validation"
+)
+
+var ErrNotCheckInable = errors.New("booking is not approved for entry")
+
+// CheckInParams is the body of POST /admin/bookings/:bookingID/check-in.
+// Code is the ticket code carried by the QR; no ParticipantIDs checks in
+// everyone on the booking.
+type CheckInParams struct {
+	Code           string   `json:"code,omitempty"`
+	ParticipantIDs []string `json:"participantIDs,omitempty" validate:"dive,uuid"`
+}
+
+// CheckInUC marks participants of an approved booking as attended at the door.
+func CheckInUC(bookingID, staffEmail string, payload []byte) ([]*participant.Participant, error) {
+	var p CheckInParams
+	if len(payload) > 0 {
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return nil, fmt.Errorf("invalid payload: %w", err)
+		}
+	}
+	if err := validation.Struct(&p); err != nil {
+		return nil, err
+	}
+	if err := CheckTicketCode(bookingID, p.Code); err != nil {
+		return nil, err
+	}
+
+	tx, err := db.DB.BeginTx(context.Background(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`SELECT 1 FROM booking WHERE booking_id = $1 FOR UPDATE`, bookingID); err != nil {
+		return nil, fmt.Errorf("failed to lock booking: %w", err)
+	}
+	bk, err := GetBookingTx(tx, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if bk.BookingStatus != APPROVED && bk.BookingStatus != CONFIRMED {
+		return nil, fmt.Errorf("%w: status %s", ErrNotCheckInable, bk.BookingStatus)
+	}
+
+	ids, _, err := splitParticipants(bk.ParticipantIDs, p.ParticipantIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	attended := true
+	checkedIn := make([]*participant.Participant, 0, len(ids))
+	for _, id := range ids {
+		pt, err := participant.UpdateParticipantDetailsTx(tx, id, participant.UpdateParticipantParams{Attended: &attended})
+		if err != nil {
+			return nil, err
+		}
+		checkedIn = append(checkedIn, pt)
+	}
+	if err := recordEventTx(tx, bk.BookingID, staffEmail, EventCheckedIn, map[string]interface{}{
+		"participantIDs": ids,
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit failed: %w", err)
+	}
+	logger.Log.Info(fmt.Sprintf("[check-in-uc] %s checked in %d participants of booking %s", staffEmail, len(checkedIn), bookingID))
+	return checkedIn, nil
+}
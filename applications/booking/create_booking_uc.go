@@ -8,38 +8,68 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"supra/applications/auth"
 	"supra/applications/participant"
+	"supra/applications/promotion"
 	"supra/applications/seat"
 	"supra/db"
 	"supra/logger"
+	"supra/validation"
 
 	"github.com/google/uuid"
 )
 
 type CreateBookingParams struct {
-	BookingEmail     string                 `json:"bookingEmail" validate:"required"`
-	PaymentDetailsID string                 `json:"paymentDetailsID" validate:"required"`
-	ReceiptImage     string                 `json:"receiptImage" validate:"required"` // base64 from client
-	SeatQuantity     int                    `json:"seatQuantity" validate:"required"`
-	ConcertID        string                 `json:"concertID" validate:"required"`
-	SeatID           string                 `json:"seatID" validate:"required"`
-	TotalAmount      float64                `json:"totalAmount" validate:"required"`
-	Participants     []*participantsDetails `json:"participants"`
-	UserNotes        string                 `json:"userNotes"`
+	BookingEmail     string                 `json:"bookingEmail" validate:"required,email"`  // owner; taken from the JWT, not the body
+	UserID           string                 `json:"-"`                                       // owner's account, from the JWT
+	ContactEmail     string                 `json:"contactEmail,omitempty" validate:"email"` // where booking mails go, if not the owner
+	PaymentDetailsID string                 `json:"paymentDetailsID" validate:"required,uuid"`
+	ReceiptImage     string                 `json:"receiptImage"` // base64 from client; optional for UPI/gateway payments
+	SeatQuantity     int                    `json:"seatQuantity" validate:"required,gte=1"`
+	ConcertID        string                 `json:"concertID" validate:"required,uuid"`
+	SeatID           string                 `json:"seatID" validate:"required,uuid"`
+	TotalAmount      float64                `json:"totalAmount" validate:"gte=0"` // informational; the server prices the booking
+	Participants     []*participantsDetails `json:"participants" validate:"required"`
+	UserNotes        string                 `json:"userNotes" validate:"max=2000"`
+	PromoCode        string                 `json:"promoCode,omitempty" validate:"max=64"`
+	HoldID           string                 `json:"holdID,omitempty" validate:"uuid"` // seat hold that locked the price
+	Billing          *BillingDetails        `json:"billing,omitempty"`                // invoice buyer details
+
+	currency string // the payment method's currency, from concert_payment
 }
 
 type participantsDetails struct {
-	Name  string `json:"name" validate:"required"`
-	WaNum string `json:"waNum" validate:"required"`
-	Email string `json:"email,omitempty"`
+	Name  string `json:"name" validate:"required,max=100"`
+	WaNum string `json:"waNum" validate:"required,max=20"`
+	Email string `json:"email,omitempty" validate:"email"`
+}
+
+// validate checks the request tags plus the one-participant-per-seat rule.
+func (p *CreateBookingParams) validate() error {
+	errs := validation.Check(p)
+	if p.SeatQuantity > 0 && len(p.Participants) > 0 && len(p.Participants) != p.SeatQuantity {
+		errs.Add("participants", "must list exactly %d participants, one per seat (got %d)", p.SeatQuantity, len(p.Participants))
+	}
+	return errs.Err()
+}
+
+// setOwner makes the caller the booking owner. Older clients send the
+// address they want mails at as bookingEmail; it is kept as the contact email.
+func (p *CreateBookingParams) setOwner(owner auth.Principal) {
+	if p.ContactEmail == "" && p.BookingEmail != "" && !strings.EqualFold(p.BookingEmail, owner.Email) {
+		p.ContactEmail = p.BookingEmail
+	}
+	p.BookingEmail = owner.Email
+	p.UserID = owner.UserID
 }
 
 var ErrNotEnoughSeats = errors.New("not enough seats available")
 
-func BookNow(payload []byte) (*Booking, error) {
+// BookNow creates a booking owned by the authenticated caller.
+func BookNow(owner auth.Principal, payload []byte) (*Booking, error) {
 	logger.Log.Info("[create-booking-uc] 🟢 Starting booking process")
 
 	var p CreateBookingParams
@@ -47,6 +77,40 @@ func BookNow(payload []byte) (*Booking, error) {
 		logger.Log.Error(fmt.Sprintf("[create-booking-uc] ❌ JSON unmarshal failed: %v", err))
 		return nil, fmt.Errorf("%s: unmarshal error: %w", CANCELLED, err)
 	}
+	p.setOwner(owner)
+
+	receiptBytes, err := base64.StdEncoding.DecodeString(p.ReceiptImage)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("[create-booking-uc] ❌ Invalid base64 receipt: %v", err))
+		return nil, fmt.Errorf("%s: invalid base64 receipt image: %w", CANCELLED, err)
+	}
+
+	return bookNow(&p, receiptBytes)
+}
+
+// BookNowWithReceipt creates a booking from a multipart upload: the booking
+// fields arrive as JSON and the receipt as raw file bytes.
+func BookNowWithReceipt(owner auth.Principal, payload, receipt []byte) (*Booking, error) {
+	logger.Log.Info("[create-booking-uc] 🟢 Starting booking process (multipart)")
+
+	var p CreateBookingParams
+	if err := json.Unmarshal(payload, &p); err != nil {
+		logger.Log.Error(fmt.Sprintf("[create-booking-uc] ❌ JSON unmarshal failed: %v", err))
+		return nil, fmt.Errorf("%s: unmarshal error: %w", CANCELLED, err)
+	}
+	p.setOwner(owner)
+	if len(receipt) == 0 {
+		return nil, fmt.Errorf("%s: %w", CANCELLED, ErrEmptyReceipt)
+	}
+
+	return bookNow(&p, receipt)
+}
+
+func bookNow(p *CreateBookingParams, receiptBytes []byte) (*Booking, error) {
+	if err := p.validate(); err != nil {
+		logger.Log.Warn(fmt.Sprintf("[create-booking-uc] ⚠️ Invalid booking request: %v", err))
+		return nil, fmt.Errorf("%s: %w", CANCELLED, err)
+	}
 
 	v, err := GetConcertBooking(p.ConcertID)
 	if err != nil {
@@ -57,7 +121,38 @@ func BookNow(payload []byte) (*Booking, error) {
 		return nil, fmt.Errorf("Booking has been closed already!")
 	}
 
-	tx, err := db.DB.BeginTx(context.Background(), nil)
+	if p.currency, err = checkConcertPayment(p.ConcertID, p.PaymentDetailsID); err != nil {
+		return nil, fmt.Errorf("%s: %w", CANCELLED, err)
+	}
+
+	var rc *processedReceipt
+	if len(receiptBytes) > 0 {
+		rc, err = processReceipt(receiptBytes)
+		if err != nil {
+			logger.Log.Warn(fmt.Sprintf("[create-booking-uc] ⚠️ Receipt rejected: %v", err))
+			return nil, fmt.Errorf("%s: %w", CANCELLED, err)
+		}
+	}
+
+	// Store the receipt blob first; the booking row only keeps its key and hash.
+	ctx := context.Background()
+	bkID := uuid.New()
+	var sr *storedReceipt
+	if rc != nil {
+		sr, err = storeReceipt(ctx, bkID, rc)
+		if err != nil {
+			logger.Log.Error(fmt.Sprintf("[create-booking-uc] ❌ Receipt upload failed: %v", err))
+			return nil, err
+		}
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			sr.discard(ctx)
+		}
+	}()
+
+	tx, err := db.DB.BeginTx(ctx, nil)
 	if err != nil {
 		logger.Log.Error(fmt.Sprintf("[create-booking-uc] ❌ Failed to start DB transaction: %v", err))
 		return nil, fmt.Errorf("%s: failed to start transaction: %w", CANCELLED, err)
@@ -69,27 +164,70 @@ func BookNow(payload []byte) (*Booking, error) {
 		return nil, err
 	}
 
-	// updatedSeat, err := validateAndUpdateSeatTx(tx, p.SeatID, p.SeatQuantity)
-	// if err != nil {
-	// 	return nil, fmt.Errorf("%s: seat update failed: %w", CANCELLED, err)
-	// }
+	// Without a hold the booking competes with everyone else's holds and
+	// waitlist offers, so only the unreserved seats are up for grabs.
+	if p.HoldID == "" {
+		free, err := seat.FreeSeatsTx(tx, currentSeat)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", CANCELLED, err)
+		}
+		if free < p.SeatQuantity {
+			return nil, fmt.Errorf("%s: %w: requested %d, free %d", CANCELLED, ErrNotEnoughSeats, p.SeatQuantity, free)
+		}
+	}
+
+	if _, err := validateAndUpdateSeatTx(tx, p.SeatID, p.SeatQuantity); err != nil {
+		return nil, fmt.Errorf("%s: seat update failed: %w", CANCELLED, err)
+	}
 
 	participantIDs, err := addParticipantsTx(tx, p.Participants)
 	if err != nil {
 		return nil, fmt.Errorf("%s: adding participants failed: %w", CANCELLED, err)
 	}
 
-	bk, err := newBookingTx(tx, &p, currentSeat.SeatType, participantIDs)
+	price, err := priceBookingTx(tx, p, currentSeat)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", CANCELLED, err)
+	}
+
+	bk, err := newBookingTx(tx, bkID, p, currentSeat.SeatType, participantIDs, sr, price)
 	if err != nil {
 		return nil, fmt.Errorf("%s: booking insertion failed: %w", CANCELLED, err)
 	}
 
+	if price.PromoCode != "" {
+		if err := promotion.RecordRedemptionTx(tx, price.PromoCode, bkID, bk.BookingEmail, price.Discount); err != nil {
+			return nil, fmt.Errorf("%s: %w", CANCELLED, err)
+		}
+	}
+
+	bk.DuplicateReceipt, err = flagDuplicateReceiptTx(tx, bkID, sr)
+	if err != nil {
+		return nil, fmt.Errorf("%s: duplicate receipt check failed: %w", CANCELLED, err)
+	}
+
 	if err := tx.Commit(); err != nil {
 		logger.Log.Error(fmt.Sprintf("[create-booking-uc] ❌ Commit failed: %v", err))
 		return nil, fmt.Errorf("%s: failed to commit transaction: %w", CANCELLED, err)
 	}
+	committed = true
 	logger.Log.Info(fmt.Sprintf("[create-booking-uc] ✅ Booking committed successfully: %v", bk.BookingID))
 
+	if rc != nil {
+		queueReceiptOCR(bk.BookingID, sr, rc.Data)
+	}
+
+	// ---- UPI payment instructions for the customer (non-blocking) ----
+	bk.attachUPIIntent()
+	if bk.UPI != nil {
+		upi := *bk.UPI
+		go func() {
+			if err := auth.SendBookingPaymentMail(bk.NotifyEmail(), bk.BookingID.String(), upi.Reference, upi.Amount, upi.Link, upi.QRCode); err != nil {
+				logger.Log.Error(fmt.Sprintf("[create-booking-uc] ❌ Failed to send UPI payment mail: %v", err))
+			}
+		}()
+	}
+
 	// ---- Admin notification (non-blocking) ----
 	adminEmail := os.Getenv("ADMIN_EMAIL")
 	if adminEmail == "" {
@@ -97,8 +235,12 @@ func BookNow(payload []byte) (*Booking, error) {
 		return bk, nil
 	}
 
-	// Use the ORIGINAL base64 from the request to avoid any re-encoding surprises.
-	receiptBase64 := p.ReceiptImage
+	// Send the sanitized receipt that was actually stored.
+	var receiptBase64, receiptType string
+	if rc != nil {
+		receiptBase64 = base64.StdEncoding.EncodeToString(rc.Data)
+		receiptType = rc.ContentType
+	}
 
 	go func() {
 		logger.Log.Info(fmt.Sprintf("[create-booking-uc] ✉️ Admin email: %s", adminEmail))
@@ -108,8 +250,11 @@ func BookNow(payload []byte) (*Booking, error) {
 			bk.BookingEmail,
 			bk.SeatType,
 			bk.TotalAmount,
+			bk.Currency,
 			receiptBase64,
+			receiptType,
 			bk.UserNotes,
+			bk.DuplicateReceipt.String(),
 		)
 		if err != nil {
 			logger.Log.Error(fmt.Sprintf("[create-booking-uc] ❌ Failed to send admin notification: %v", err))
@@ -155,37 +300,58 @@ func addParticipantsTx(tx *sql.Tx, details []*participantsDetails) ([]string, er
 	return pts, nil
 }
 
-func newBookingTx(tx *sql.Tx, p *CreateBookingParams, seatType string, participantIDs []string) (*Booking, error) {
-	bkID := uuid.New()
-
+func newBookingTx(tx *sql.Tx, bkID uuid.UUID, p *CreateBookingParams, seatType string, participantIDs []string, sr *storedReceipt, price *PriceBreakdown) (*Booking, error) {
 	if p.UserNotes == "" {
 		p.UserNotes = "Not provided"
 	}
 	participantIDsJSON, _ := json.Marshal(participantIDs)
-	receiptBytes, _ := base64.StdEncoding.DecodeString(p.ReceiptImage)
+	priceJSON, _ := json.Marshal(price)
+	var billingJSON []byte
+	if p.Billing != nil {
+		billingJSON, _ = json.Marshal(p.Billing)
+	}
 
 	bk := &Booking{
 		BookingID:        bkID,
 		BookingEmail:     p.BookingEmail,
+		UserID:           p.UserID,
+		ContactEmail:     p.ContactEmail,
 		BookingStatus:    VERIFYING,
 		PaymentDetailsID: p.PaymentDetailsID,
-		ReceiptImage:     receiptBytes,
 		SeatQuantity:     p.SeatQuantity,
 		SeatID:           p.SeatID,
-		TotalAmount:      p.TotalAmount,
+		TotalAmount:      price.Total,
+		Currency:         price.Currency,
 		SeatType:         seatType,
 		ParticipantIDs:   participantIDs,
 		CreatedAt:        time.Now(),
 		UserNotes:        p.UserNotes,
+		PaymentReference: paymentReference(bkID),
+		SeatsReserved:    true,
+		Price:            price,
+		Billing:          p.Billing,
+	}
+	if sr == nil {
+		sr = &storedReceipt{}
+	} else {
+		bk.ReceiptHash = sr.Hash
+		bk.ReceiptType = sr.ContentType
+		bk.ReceiptURL = receiptURL(bkID)
+		if sr.ThumbKey != "" {
+			bk.ThumbnailURL = receiptThumbnailURL(bkID)
+		}
 	}
 
 	const insertSQL = `
 	INSERT INTO booking (
 		booking_id, booking_email, booking_status, payment_details_id,
-		receipt_image, seat_quantity, seat_id, concert_id, total_amount,
-		seat_type, participant_ids, created_at, user_notes
+		receipt_key, receipt_hash, receipt_content_type, receipt_thumb_key, receipt_phash,
+		seat_quantity, seat_id, concert_id, total_amount,
+		seat_type, participant_ids, created_at, user_notes, payment_reference, price_breakdown, billing,
+		user_id, contact_email, seats_reserved, currency
 	)
-	VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13)
+	VALUES ($1,$2,$3,$4,NULLIF($5, ''),NULLIF($6, ''),NULLIF($7, ''),NULLIF($8, ''),$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20,
+		NULLIF($21, '')::uuid, NULLIF($22, ''), TRUE, $23)
 `
 
 	_, err := tx.Exec(
@@ -194,7 +360,11 @@ func newBookingTx(tx *sql.Tx, p *CreateBookingParams, seatType string, participa
 		bk.BookingEmail,
 		bk.BookingStatus,
 		bk.PaymentDetailsID,
-		bk.ReceiptImage,
+		sr.Key,
+		sr.Hash,
+		sr.ContentType,
+		sr.ThumbKey,
+		sr.PHash,
 		bk.SeatQuantity,
 		bk.SeatID,
 		p.ConcertID,
@@ -203,6 +373,12 @@ func newBookingTx(tx *sql.Tx, p *CreateBookingParams, seatType string, participa
 		participantIDsJSON,
 		bk.CreatedAt,
 		bk.UserNotes,
+		bk.PaymentReference,
+		priceJSON,
+		billingJSON,
+		bk.UserID,
+		bk.ContactEmail,
+		bk.Currency,
 	)
 
 	if err != nil {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"supra/applications/seat"
@@ -14,6 +15,9 @@ import (
 	// NOTE: Booking struct, constants, and GetBookingTx assumed here
 )
 
+// ErrAlreadyCancelled is returned when cancelling a booking that is already cancelled.
+var ErrAlreadyCancelled = errors.New("booking is already cancelled")
+
 // DeleteBooking handles the cancellation logic: changing status and refunding seats.
 // We change the status to CANCELLED instead of deleting the row for audit purposes.
 func DeleteBooking(bookingID string) (*Booking, error) {
@@ -28,7 +32,34 @@ func DeleteBooking(bookingID string) (*Booking, error) {
 	defer tx.Rollback()
 	logger.Log.Info(fmt.Sprintf("[delete-booking-uc] Transaction started for %s.", bookingID))
 
-	// 1. Fetch the existing booking details (and lock the row for update)
+	updatedBk, err := CancelBookingTx(tx, bookingID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. Commit the transaction
+	if err := tx.Commit(); err != nil {
+		logger.Log.Error(fmt.Sprintf("[delete-booking-uc] Failed to commit transaction for %s: %v", bookingID, err))
+		return nil, fmt.Errorf("failed to commit cancellation: %w", err)
+	}
+	logger.Log.Info(fmt.Sprintf("[delete-booking-uc] Booking %s successfully committed as CANCELLED.", bookingID))
+	go seat.ProcessWaitlist(updatedBk.SeatID)
+
+	return updatedBk, nil
+}
+
+// CancelBookingTx marks the booking CANCELLED and gives its seats back inside
+// the caller's transaction. The caller commits and then runs
+// seat.ProcessWaitlist for the booking's seat.
+func CancelBookingTx(tx *sql.Tx, bookingID string) (*Booking, error) {
+	// 1. Lock the row so concurrent cancellations (user, refund approval,
+	// concert refund) serialize and only the first one returns the seats.
+	if _, err := tx.Exec(`SELECT 1 FROM booking WHERE booking_id = $1 FOR UPDATE`, bookingID); err != nil {
+		logger.Log.Error(fmt.Sprintf("[delete-booking-uc] Failed to lock booking %s: %v", bookingID, err))
+		return nil, fmt.Errorf("failed to lock booking: %w", err)
+	}
+
+	// 2. Fetch the existing booking details
 	currentBooking, err := GetBookingTx(tx, bookingID)
 	if err != nil {
 		logger.Log.Error(fmt.Sprintf("[delete-booking-uc] Booking retrieval failed for %s: %v", bookingID, err))
@@ -37,33 +68,38 @@ func DeleteBooking(bookingID string) (*Booking, error) {
 
 	// Prevent cancellation if already cancelled or confirmed (business logic decision)
 	if currentBooking.BookingStatus == CANCELLED {
-		tx.Rollback()
 		logger.Log.Warn(fmt.Sprintf("[delete-booking-uc] Cancellation skipped for %s: Already CANCELLED.", bookingID))
-		return nil, fmt.Errorf("booking ID %s is already cancelled", bookingID)
+		return nil, fmt.Errorf("%w: booking ID %s is already cancelled", ErrAlreadyCancelled, bookingID)
 	}
 	logger.Log.Info(fmt.Sprintf("[delete-booking-uc] Booking %s found (Status: %s). Proceeding to refund seats.", bookingID, currentBooking.BookingStatus))
 
-	// 2. "Refund" the seats (Increase the available count)
+	// 3. "Refund" the seats (Increase the available count)
 	// We call the helper which uses the seat package functions within the transaction.
-	_, err = increaseSeatAvailabilityTx(tx, currentBooking.SeatID, currentBooking.SeatQuantity)
-	if err != nil {
-		logger.Log.Error(fmt.Sprintf("[delete-booking-uc] Seat refund failed for %s (Rollback): %v", bookingID, err))
-		return nil, fmt.Errorf("failed to refund seats: %w", err)
+	// A finally rejected booking already gave its seats back, and bookings
+	// created before seats were reserved never took any.
+	if currentBooking.SeatsReserved && holdsSeats(currentBooking.BookingStatus, currentBooking.RejectionCode) {
+		_, err := increaseSeatAvailabilityTx(tx, currentBooking.SeatID, currentBooking.SeatQuantity)
+		if err != nil {
+			logger.Log.Error(fmt.Sprintf("[delete-booking-uc] Seat refund failed for %s (Rollback): %v", bookingID, err))
+			return nil, fmt.Errorf("failed to refund seats: %w", err)
+		}
+		logger.Log.Info(fmt.Sprintf("[delete-booking-uc] Successfully refunded %d seats to SeatID %s.", currentBooking.SeatQuantity, currentBooking.SeatID))
 	}
-	logger.Log.Info(fmt.Sprintf("[delete-booking-uc] Successfully refunded %d seats to SeatID %s.", currentBooking.SeatQuantity, currentBooking.SeatID))
 
-	// 3. Update Booking Status to CANCELLED
+	// 4. Update Booking Status to CANCELLED
 	const updateSQL = `
 		UPDATE booking
 		SET booking_status = $2
-		WHERE booking_id = $1
+		WHERE booking_id = $1 AND booking_status <> $2
 		RETURNING booking_id, booking_email, booking_status, payment_details_id, 
-				  receipt_image, seat_quantity, seat_id, total_amount, seat_type, 
+				  COALESCE(receipt_hash, ''), (receipt_key IS NOT NULL OR receipt_image IS NOT NULL),
+				  COALESCE(receipt_content_type, ''), (receipt_thumb_key IS NOT NULL),
+				  seat_quantity, seat_id, total_amount, currency, seat_type, 
 				  participant_ids, created_at, user_notes`
 
 	// Note: We reuse the RETURNING statement logic from UpdateBooking for convenience
 	updatedBk := &Booking{}
-	var receiptImage []byte
+	var hasReceipt, hasThumb bool
 	var participantIDsJSON []byte
 	var bookingIDUUID uuid.UUID // Helper for scanning UUID
 
@@ -71,24 +107,16 @@ func DeleteBooking(bookingID string) (*Booking, error) {
 
 	if err := row.Scan(
 		&bookingIDUUID, &updatedBk.BookingEmail, &updatedBk.BookingStatus, &updatedBk.PaymentDetailsID,
-		&receiptImage, &updatedBk.SeatQuantity, &updatedBk.SeatID, &updatedBk.TotalAmount, &updatedBk.SeatType,
+		&updatedBk.ReceiptHash, &hasReceipt, &updatedBk.ReceiptType, &hasThumb, &updatedBk.SeatQuantity, &updatedBk.SeatID, &updatedBk.TotalAmount, &updatedBk.Currency, &updatedBk.SeatType,
 		&participantIDsJSON, &updatedBk.CreatedAt, &updatedBk.UserNotes,
 	); err != nil {
-		tx.Rollback()
 		logger.Log.Error(fmt.Sprintf("[delete-booking-uc] Failed to scan RETURNING row after status update (Rollback): %v", err))
 		return nil, fmt.Errorf("failed to scan cancelled booking: %w", err)
 	}
 	updatedBk.BookingID = bookingIDUUID // Map UUID
 
-	// 4. Commit the transaction
-	if err := tx.Commit(); err != nil {
-		logger.Log.Error(fmt.Sprintf("[delete-booking-uc] Failed to commit transaction for %s: %v", bookingID, err))
-		return nil, fmt.Errorf("failed to commit cancellation: %w", err)
-	}
-	logger.Log.Info(fmt.Sprintf("[delete-booking-uc] Booking %s successfully committed as CANCELLED.", bookingID))
-
 	// Finalize mapping for the return struct
-	updatedBk.ReceiptImage = receiptImage
+	updatedBk.setReceiptLinks(hasReceipt, hasThumb)
 	if len(participantIDsJSON) > 0 && string(participantIDsJSON) != "null" {
 		json.Unmarshal(participantIDsJSON, &updatedBk.ParticipantIDs)
 	}
@@ -0,0 +1,171 @@
+package booking
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"supra/applications/participant"
+	"supra/concert/application"
+	"supra/db"
+	"supra/logger"
+	"supra/validation"
+)
+
+var (
+	ErrNotBookingOwner     = errors.New("only the booking owner can change its participants")
+	ErrParticipantAttended = errors.New("participant has already checked in")
+	ErrParticipantCount    = errors.New("a booking needs exactly one participant per seat")
+)
+
+// EditParticipantsParams lists the changes to a booking's participants.
+// Replacing someone is a remove plus an add.
+type EditParticipantsParams struct {
+	Add    []*participantsDetails `json:"add,omitempty"`
+	Update []struct {
+		UserID string `json:"userID" validate:"required,uuid"`
+		participant.UpdateParticipantParams
+	} `json:"update,omitempty"`
+	Remove []string `json:"remove,omitempty" validate:"dive,uuid"`
+}
+
+// EditParticipantsUC lets the booking owner add, rename or remove
+// participants until the concert's cutoff. Issued tickets are regenerated so
+// the QR code reflects the new names, and the change is kept in the history.
+func EditParticipantsUC(bookingID, ownerEmail string, payload []byte) (*Booking, error) {
+	logger.Log.Info(fmt.Sprintf("[edit-participants-uc] %s editing participants of booking %s", ownerEmail, bookingID))
+
+	var p EditParticipantsParams
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return nil, fmt.Errorf("invalid payload: %w", err)
+	}
+	if len(p.Add) == 0 && len(p.Update) == 0 && len(p.Remove) == 0 {
+		return nil, fmt.Errorf("invalid payload: no participant changes")
+	}
+	if err := validation.Struct(&p); err != nil {
+		return nil, err
+	}
+
+	tx, err := db.DB.BeginTx(context.Background(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`SELECT 1 FROM booking WHERE booking_id = $1 FOR UPDATE`, bookingID); err != nil {
+		return nil, fmt.Errorf("invalid booking ID: %w", err)
+	}
+	bk, err := GetBookingTx(tx, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(bk.BookingEmail, ownerEmail) {
+		return nil, ErrNotBookingOwner
+	}
+	if !holdsSeats(bk.BookingStatus, bk.RejectionCode) {
+		return nil, fmt.Errorf("invalid booking status for participant changes: %s", bk.BookingStatus)
+	}
+
+	concert, err := application.NewGetConcertByIDUC(logger.Log).Invoke(bk.ConcertID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load concert: %w", err)
+	}
+	if err := concert.CheckParticipantEdit(time.Now()); err != nil {
+		return nil, err
+	}
+
+	current := make(map[string]bool, len(bk.ParticipantIDs))
+	for _, id := range bk.ParticipantIDs {
+		current[id] = true
+	}
+	type change struct {
+		UserID string `json:"userID"`
+		Name   string `json:"name"`
+	}
+	var removed, renamed, added []change
+
+	// Removals and renames only apply to this booking's participants, and
+	// never to someone who has already been checked in.
+	editable := func(id string) (*participant.Participant, error) {
+		if !current[id] {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownParticipant, id)
+		}
+		pt, err := participant.GetParticipantTx(tx, id)
+		if err != nil {
+			return nil, err
+		}
+		if pt.Attended {
+			return nil, fmt.Errorf("%w: %s", ErrParticipantAttended, pt.Name)
+		}
+		return pt, nil
+	}
+
+	for _, id := range p.Remove {
+		pt, err := editable(id)
+		if err != nil {
+			return nil, err
+		}
+		delete(current, id)
+		removed = append(removed, change{UserID: id, Name: pt.Name})
+	}
+	for _, u := range p.Update {
+		if _, err := editable(u.UserID); err != nil {
+			return nil, err
+		}
+		pt, err := participant.UpdateParticipantDetailsTx(tx, u.UserID, u.UpdateParticipantParams)
+		if err != nil {
+			return nil, err
+		}
+		renamed = append(renamed, change{UserID: pt.UserID, Name: pt.Name})
+	}
+
+	ids := make([]string, 0, len(current)+len(p.Add))
+	for _, id := range bk.ParticipantIDs {
+		if current[id] {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids)+len(p.Add) != bk.SeatQuantity {
+		return nil, fmt.Errorf("%w: %d participants for %d seats", ErrParticipantCount, len(ids)+len(p.Add), bk.SeatQuantity)
+	}
+	newIDs, err := addParticipantsTx(tx, p.Add)
+	if err != nil {
+		return nil, err
+	}
+	for i, id := range newIDs {
+		added = append(added, change{UserID: id, Name: p.Add[i].Name})
+	}
+	ids = append(ids, newIDs...)
+
+	// Issued tickets carry the old names; a new code voids their QR.
+	reissue := bk.BookingStatus == APPROVED || bk.BookingStatus == CONFIRMED
+	code := bk.TicketCode
+	if reissue {
+		if code, err = newTicketCode(); err != nil {
+			return nil, err
+		}
+	}
+	idsJSON, _ := json.Marshal(ids)
+	if _, err := tx.Exec(`UPDATE booking SET participant_ids = $2, ticket_code = NULLIF($3, '') WHERE booking_id = $1`,
+		bk.BookingID, idsJSON, code); err != nil {
+		return nil, fmt.Errorf("failed to update participants: %w", err)
+	}
+	if err := recordEventTx(tx, bk.BookingID, ownerEmail, EventParticipantsUpdated, map[string]interface{}{
+		"added": added, "renamed": renamed, "removed": removed,
+	}); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit failed: %w", err)
+	}
+	bk.ParticipantIDs, bk.TicketCode = ids, code
+
+	logger.Log.Info(fmt.Sprintf("[edit-participants-uc] ✅ Booking %s participants updated (+%d ~%d -%d)", bookingID, len(added), len(renamed), len(removed)))
+	if reissue {
+		go SendReissuedTicket(bk.BookingID.String(), "The participants on your booking were updated.")
+	}
+	return bk, nil
+}
@@ -16,8 +16,13 @@ func GetAllBookingsAdminUC() ([]*Booking, error) {
 	query := `
 		SELECT 
 			booking_id, booking_email, booking_status, payment_details_id,
-			receipt_image, seat_quantity, seat_id, concert_id, total_amount, seat_type,
-			participant_ids, created_at, user_notes
+			COALESCE(receipt_hash, ''), (receipt_key IS NOT NULL OR receipt_image IS NOT NULL),
+			COALESCE(receipt_content_type, ''), (receipt_thumb_key IS NOT NULL),
+			seat_quantity, seat_id, concert_id, total_amount, currency, seat_type,
+			participant_ids, created_at, user_notes,
+			COALESCE(rejection_code, ''), COALESCE(rejection_reason, ''),
+			COALESCE(duplicate_of::text, ''), COALESCE(duplicate_match, ''),
+			price_breakdown
 		FROM booking
 		ORDER BY created_at DESC
 	`
@@ -35,13 +40,19 @@ func GetAllBookingsAdminUC() ([]*Booking, error) {
 		var (
 			bk                Booking
 			participantIDsRaw []byte
-			receiptBytes      []byte
+			hasReceipt        bool
+			hasThumb          bool
+			dupOf, dupMatch   string
+			priceRaw          []byte
 		)
 
 		if err := rows.Scan(
 			&bk.BookingID, &bk.BookingEmail, &bk.BookingStatus, &bk.PaymentDetailsID,
-			&receiptBytes, &bk.SeatQuantity, &bk.SeatID, &bk.ConcertID, &bk.TotalAmount,
+			&bk.ReceiptHash, &hasReceipt, &bk.ReceiptType, &hasThumb, &bk.SeatQuantity, &bk.SeatID, &bk.ConcertID, &bk.TotalAmount, &bk.Currency,
 			&bk.SeatType, &participantIDsRaw, &bk.CreatedAt, &bk.UserNotes,
+			&bk.RejectionCode, &bk.RejectionReason,
+			&dupOf, &dupMatch,
+			&priceRaw,
 		); err != nil {
 			logger.Log.Warn(fmt.Sprintf("[get-all-bookings-admin-uc] Row scan failed: %v", err))
 			continue
@@ -51,7 +62,9 @@ func GetAllBookingsAdminUC() ([]*Booking, error) {
 			_ = json.Unmarshal(participantIDsRaw, &bk.ParticipantIDs)
 		}
 
-		bk.ReceiptImage = receiptBytes
+		bk.setReceiptLinks(hasReceipt, hasThumb)
+		bk.DuplicateReceipt = receiptMatchFrom(dupOf, dupMatch)
+		bk.Price = priceBreakdownFrom(priceRaw)
 		bookings = append(bookings, &bk)
 	}
 
@@ -19,7 +19,9 @@ func GetAllBookingsByConcertID(concertID string) ([]*Booking, error) {
 
 	selectAllSQL := `
 		SELECT booking_id, booking_email, booking_status, payment_details_id,
-				receipt_image, seat_quantity, seat_id, concert_id, total_amount, seat_type,
+				COALESCE(receipt_hash, ''), (receipt_key IS NOT NULL OR receipt_image IS NOT NULL),
+				COALESCE(receipt_content_type, ''), (receipt_thumb_key IS NOT NULL),
+				seat_quantity, seat_id, concert_id, total_amount, currency, seat_type,
 				participant_ids, created_at, user_notes
 		FROM booking
 		WHERE concert_id = $1
@@ -42,12 +44,12 @@ func GetAllBookingsByConcertID(concertID string) ([]*Booking, error) {
 		bk := &Booking{}
 		var participantIDsJSON []byte
 		var bookingIDUUID uuid.UUID
-		var receiptImage []byte
+		var hasReceipt, hasThumb bool
 
 		err := rows.Scan(
 			&bookingIDUUID, &bk.BookingEmail, &bk.BookingStatus, &bk.PaymentDetailsID,
-			&receiptImage, &bk.SeatQuantity, &bk.SeatID, &bk.ConcertID,
-			&bk.TotalAmount, &bk.SeatType, &participantIDsJSON, &bk.CreatedAt, &bk.UserNotes,
+			&bk.ReceiptHash, &hasReceipt, &bk.ReceiptType, &hasThumb, &bk.SeatQuantity, &bk.SeatID, &bk.ConcertID,
+			&bk.TotalAmount, &bk.Currency, &bk.SeatType, &participantIDsJSON, &bk.CreatedAt, &bk.UserNotes,
 		)
 		if err != nil {
 			logger.Log.Error(fmt.Sprintf("[get-all-booking-concertID-uc] Error scanning booking row for %s: %v", concertID, err))
@@ -55,7 +57,7 @@ func GetAllBookingsByConcertID(concertID string) ([]*Booking, error) {
 		}
 
 		bk.BookingID = bookingIDUUID
-		bk.ReceiptImage = receiptImage
+		bk.setReceiptLinks(hasReceipt, hasThumb)
 
 		// Unmarshal participant IDs
 		if len(participantIDsJSON) > 0 && string(participantIDsJSON) != "null" {
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"supra/applications/auth"
 	"supra/db"     // Assumes global DB instance
 	"supra/logger" // ⬅️ Assuming this import path
 
@@ -12,23 +13,28 @@ import (
 
 // NOTE: Booking struct definition is assumed here
 
-// GetAllBookings retrieves all bookings associated with a specific user email.
-func GetAllBookings(userEmail string) ([]*Booking, error) {
+// GetAllBookings retrieves all bookings owned by the given user.
+func GetAllBookings(owner auth.Principal) ([]*Booking, error) {
+	userEmail := owner.Email
 	logger.Log.Info(fmt.Sprintf("[get-all-booking-uc] Retrieving bookings for user: %s", userEmail))
 
-	// 1. SQL query filters by booking_email
+	// 1. SQL query filters by the owner's account; unclaimed bookings still match by email
 	const selectAllSQL = `
 		SELECT booking_id, booking_email, booking_status, payment_details_id, 
-		       receipt_image, seat_quantity, seat_id, concert_id, total_amount, seat_type, 
-		       participant_ids, created_at, user_notes
+		       COALESCE(receipt_hash, ''), (receipt_key IS NOT NULL OR receipt_image IS NOT NULL),
+		       COALESCE(receipt_content_type, ''), (receipt_thumb_key IS NOT NULL),
+		       seat_quantity, seat_id, concert_id, total_amount, seat_type, 
+		       participant_ids, created_at, user_notes,
+		       COALESCE(rejection_code, ''), COALESCE(rejection_reason, ''),
+		       COALESCE(user_id::text, ''), COALESCE(contact_email, ''), currency
 		FROM booking
-		WHERE booking_email = $1  -- Filter added
+		WHERE user_id = NULLIF($2, '')::uuid
+		   OR (user_id IS NULL AND LOWER(booking_email) = LOWER($1))
 		ORDER BY created_at DESC`
 
 	// 2. Execute the query
 	logger.Log.Info("[get-all-booking-uc] Executing SELECT all query (filtered).")
-	// Pass userEmail as the argument
-	rows, err := db.DB.Query(selectAllSQL, userEmail)
+	rows, err := db.DB.Query(selectAllSQL, userEmail, owner.UserID)
 	if err != nil {
 		logger.Log.Error(fmt.Sprintf("[get-all-booking-uc] Database query failed for %s: %v", userEmail, err))
 		return nil, fmt.Errorf("database query error: %w", err)
@@ -43,12 +49,14 @@ func GetAllBookings(userEmail string) ([]*Booking, error) {
 		bk := &Booking{}
 		var participantIDsJSON []byte
 		var bookingIDUUID uuid.UUID
-		var receiptImage []byte // Use sql.NullBytes for potentially null images
+		var hasReceipt, hasThumb bool
 
 		err := rows.Scan(
 			&bookingIDUUID, &bk.BookingEmail, &bk.BookingStatus, &bk.PaymentDetailsID,
-			&receiptImage, &bk.SeatQuantity, &bk.SeatID, &bk.ConcertID, &bk.TotalAmount, &bk.SeatType,
+			&bk.ReceiptHash, &hasReceipt, &bk.ReceiptType, &hasThumb, &bk.SeatQuantity, &bk.SeatID, &bk.ConcertID, &bk.TotalAmount, &bk.SeatType,
 			&participantIDsJSON, &bk.CreatedAt, &bk.UserNotes,
+			&bk.RejectionCode, &bk.RejectionReason,
+			&bk.UserID, &bk.ContactEmail, &bk.Currency,
 		)
 		if err != nil {
 			logger.Log.Error(fmt.Sprintf("[get-all-booking-uc] Error scanning booking row for %s: %v", userEmail, err))
@@ -56,7 +64,7 @@ func GetAllBookings(userEmail string) ([]*Booking, error) {
 		}
 
 		bk.BookingID = bookingIDUUID
-		bk.ReceiptImage = receiptImage
+		bk.setReceiptLinks(hasReceipt, hasThumb)
 
 		// 4. JSON unmarshal for Participant IDs
 		if len(participantIDsJSON) > 0 && string(participantIDsJSON) != "null" {
@@ -67,6 +75,8 @@ func GetAllBookings(userEmail string) ([]*Booking, error) {
 			}
 		}
 
+		bk.applyRejectionGuide()
+
 		bookings = append(bookings, bk)
 		recordCount++
 	}
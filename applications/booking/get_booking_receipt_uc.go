@@ -4,10 +4,13 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 
 	"supra/db"
 	"supra/logger"
+	"supra/storage"
 
 	"github.com/google/uuid"
 )
@@ -27,7 +30,9 @@ func GetBookingReceiptUC(bookingID string) (*Booking, error) {
 	query := `
 		SELECT 
 			booking_id, booking_email, booking_status, payment_details_id,
-			receipt_image, seat_quantity, seat_id, concert_id, seat_type, total_amount,
+			COALESCE(receipt_key, ''), COALESCE(receipt_hash, ''), receipt_image,
+			COALESCE(receipt_content_type, ''), (receipt_thumb_key IS NOT NULL),
+			seat_quantity, seat_id, concert_id, seat_type, total_amount, currency,
 			participant_ids, created_at, user_notes
 		FROM booking
 		WHERE booking_id = $1
@@ -36,15 +41,17 @@ func GetBookingReceiptUC(bookingID string) (*Booking, error) {
 
 	bk := &Booking{}
 	var (
-		receiptBytes      []byte
+		receiptKey        string
+		legacyReceipt     []byte
+		hasThumb          bool
 		participantIDsRaw []byte
 		idUUID            uuid.UUID
 	)
 
 	if err := row.Scan(
 		&idUUID, &bk.BookingEmail, &bk.BookingStatus, &bk.PaymentDetailsID,
-		&receiptBytes, &bk.SeatQuantity, &bk.SeatID, &bk.ConcertID, &bk.SeatType,
-		&bk.TotalAmount, &participantIDsRaw, &bk.CreatedAt, &bk.UserNotes,
+		&receiptKey, &bk.ReceiptHash, &legacyReceipt, &bk.ReceiptType, &hasThumb, &bk.SeatQuantity, &bk.SeatID, &bk.ConcertID, &bk.SeatType,
+		&bk.TotalAmount, &bk.Currency, &participantIDsRaw, &bk.CreatedAt, &bk.UserNotes,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			logger.Log.Warn(fmt.Sprintf("[get-booking-receipt-uc] Booking %s not found.", bookingID))
@@ -61,8 +68,80 @@ func GetBookingReceiptUC(bookingID string) (*Booking, error) {
 	}
 
 	bk.BookingID = idUUID
-	bk.ReceiptImage = receiptBytes
+
+	// Step 4: Load the receipt blob (rows not yet migrated still carry the bytes inline)
+	if receiptKey != "" {
+		data, err := storage.Receipts.Get(context.Background(), receiptKey)
+		if err != nil {
+			logger.Log.Error(fmt.Sprintf("[get-booking-receipt-uc] Failed to load receipt %s for %s: %v", receiptKey, bookingID, err))
+			return nil, fmt.Errorf("failed to load receipt: %w", err)
+		}
+		bk.ReceiptImage = data
+	} else {
+		bk.ReceiptImage = legacyReceipt
+	}
+	if len(bk.ReceiptImage) > 0 && bk.ReceiptType == "" {
+		bk.ReceiptType = http.DetectContentType(bk.ReceiptImage)
+	}
+	bk.setReceiptLinks(len(bk.ReceiptImage) > 0, hasThumb)
 
 	logger.Log.Info(fmt.Sprintf("[get-booking-receipt-uc] Booking receipt successfully retrieved for %s", bookingID))
 	return bk, nil
 }
+
+// ErrReceiptNotFound is returned when a booking has no receipt (or thumbnail) to serve.
+var ErrReceiptNotFound = errors.New("receipt not found")
+
+// GetBookingReceiptFileUC returns the raw receipt (or its thumbnail) together
+// with the content type it should be served with.
+func GetBookingReceiptFileUC(bookingID string, thumbnail bool) ([]byte, string, error) {
+	logger.Log.Info(fmt.Sprintf("[get-booking-receipt-uc] Fetching receipt file for BookingID: %s (thumbnail=%t)", bookingID, thumbnail))
+
+	id, err := uuid.Parse(bookingID)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid booking ID format: %w", err)
+	}
+
+	var (
+		receiptKey, thumbKey, contentType string
+		legacyReceipt                     []byte
+	)
+	err = db.DB.QueryRowContext(context.Background(), `
+		SELECT COALESCE(receipt_key, ''), COALESCE(receipt_thumb_key, ''),
+		       COALESCE(receipt_content_type, ''), receipt_image
+		FROM booking
+		WHERE booking_id = $1`, id).Scan(&receiptKey, &thumbKey, &contentType, &legacyReceipt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, "", fmt.Errorf("booking with ID %s not found", bookingID)
+		}
+		return nil, "", fmt.Errorf("database error: %w", err)
+	}
+
+	key := receiptKey
+	if thumbnail {
+		key, contentType = thumbKey, "image/jpeg"
+	}
+
+	var data []byte
+	switch {
+	case key != "":
+		data, err = storage.Receipts.Get(context.Background(), key)
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, "", ErrReceiptNotFound
+		}
+		if err != nil {
+			logger.Log.Error(fmt.Sprintf("[get-booking-receipt-uc] Failed to load receipt %s for %s: %v", key, bookingID, err))
+			return nil, "", fmt.Errorf("failed to load receipt: %w", err)
+		}
+	case !thumbnail && len(legacyReceipt) > 0:
+		data = legacyReceipt
+	default:
+		return nil, "", ErrReceiptNotFound
+	}
+
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
+	return data, contentType, nil
+}
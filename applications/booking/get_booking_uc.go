@@ -3,6 +3,7 @@ package booking
 import (
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"supra/db"     // Assumes global DB instance
@@ -11,14 +12,25 @@ import (
 
 // NOTE: Booking struct definition is assumed here
 
+var (
+	ErrBookingNotFound  = errors.New("booking not found")
+	ErrInvalidBookingID = errors.New("invalid booking ID")
+)
+
 // GetBooking retrieves a single booking record for general API reading (non-transactional).
 func GetBooking(bookingID string) (*Booking, error) {
 	logger.Log.Info(fmt.Sprintf("[get-booking-uc] Starting standard read for BookingID: %s", bookingID))
 
 	const selectSQL = `
 		SELECT booking_id, booking_email, booking_status, payment_details_id, 
-		       receipt_image, seat_quantity, seat_id, concert_id, total_amount, seat_type, 
-		       participant_ids, created_at, user_notes
+		       COALESCE(receipt_hash, ''), (receipt_key IS NOT NULL OR receipt_image IS NOT NULL),
+		       COALESCE(receipt_content_type, ''), (receipt_thumb_key IS NOT NULL),
+		       seat_quantity, seat_id, concert_id, total_amount, seat_type, 
+		       participant_ids, created_at, user_notes,
+		       COALESCE(rejection_code, ''), COALESCE(rejection_reason, ''),
+		       COALESCE(payment_reference, ''), price_breakdown, billing,
+		       COALESCE(transferred_from::text, ''), COALESCE(ticket_code, ''),
+		       COALESCE(user_id::text, ''), COALESCE(contact_email, ''), currency
 		FROM booking
 		WHERE booking_id = $1`
 
@@ -26,31 +38,38 @@ func GetBooking(bookingID string) (*Booking, error) {
 	row := db.DB.QueryRow(selectSQL, bookingID)
 
 	bk := &Booking{}
-	var receiptImage []byte
-	var participantIDsJSON []byte
+	var hasReceipt, hasThumb bool
+	var participantIDsJSON, priceJSON, billingJSON []byte
 
 	// Use db.DB.QueryRow() for non-transactional read
 	err := row.Scan(
 		&bk.BookingID, &bk.BookingEmail, &bk.BookingStatus, &bk.PaymentDetailsID,
-		&receiptImage, &bk.SeatQuantity, &bk.SeatID, &bk.ConcertID, &bk.TotalAmount, &bk.SeatType,
+		&bk.ReceiptHash, &hasReceipt, &bk.ReceiptType, &hasThumb, &bk.SeatQuantity, &bk.SeatID, &bk.ConcertID, &bk.TotalAmount, &bk.SeatType,
 		&participantIDsJSON, &bk.CreatedAt, &bk.UserNotes,
+		&bk.RejectionCode, &bk.RejectionReason,
+		&bk.PaymentReference, &priceJSON, &billingJSON,
+		&bk.TransferredFrom, &bk.TicketCode,
+		&bk.UserID, &bk.ContactEmail, &bk.Currency,
 	)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
 			logger.Log.Warn(fmt.Sprintf("[get-booking-uc] Read failed for %s: Booking not found.", bookingID))
-			return nil, fmt.Errorf("booking with ID %s not found", bookingID)
+			return nil, fmt.Errorf("%w: %s", ErrBookingNotFound, bookingID)
 		}
 		logger.Log.Error(fmt.Sprintf("[get-booking-uc] Database query error for %s: %v", bookingID, err))
 		return nil, fmt.Errorf("database query error: %w", err)
 	}
 
-	bk.ReceiptImage = receiptImage
+	bk.setReceiptLinks(hasReceipt, hasThumb)
+	bk.Price = priceBreakdownFrom(priceJSON)
+	bk.Billing = billingFrom(billingJSON)
 
 	if err := json.Unmarshal(participantIDsJSON, &bk.ParticipantIDs); err != nil {
 		logger.Log.Error(fmt.Sprintf("[get-booking-uc] Failed to unmarshal participant IDs for %s: %v", bookingID, err))
 		return nil, fmt.Errorf("failed to unmarshal participant IDs from database: %w", err)
 	}
+	bk.applyRejectionGuide()
 
 	logger.Log.Info(fmt.Sprintf("[get-booking-uc] Booking %s retrieved successfully. Status: %s.", bookingID, bk.BookingStatus))
 	return bk, nil
@@ -62,34 +81,46 @@ func GetBookingTx(tx *sql.Tx, bookingID string) (*Booking, error) {
 
 	const selectSQL = `
 		SELECT booking_id, booking_email, booking_status, payment_details_id, 
-		       receipt_image, seat_quantity, seat_id, concert_id, total_amount, seat_type, 
-		       participant_ids, created_at, user_notes
+		       COALESCE(receipt_hash, ''), (receipt_key IS NOT NULL OR receipt_image IS NOT NULL),
+		       COALESCE(receipt_content_type, ''), (receipt_thumb_key IS NOT NULL),
+		       seat_quantity, seat_id, concert_id, total_amount, seat_type, 
+		       participant_ids, created_at, user_notes,
+		       COALESCE(rejection_code, ''), COALESCE(rejection_reason, ''),
+		       COALESCE(payment_reference, ''), price_breakdown, billing,
+		       COALESCE(transferred_from::text, ''), COALESCE(ticket_code, ''),
+		       COALESCE(user_id::text, ''), COALESCE(contact_email, ''), seats_reserved, currency
 		FROM booking
 		WHERE booking_id = $1`
 
 	bk := &Booking{}
-	var receiptImage []byte
-	var participantIDsJSON []byte
+	var hasReceipt, hasThumb bool
+	var participantIDsJSON, priceJSON, billingJSON []byte
 
 	// Use tx.QueryRow()
 	row := tx.QueryRow(selectSQL, bookingID)
 
 	err := row.Scan(
 		&bk.BookingID, &bk.BookingEmail, &bk.BookingStatus, &bk.PaymentDetailsID,
-		&receiptImage, &bk.SeatQuantity, &bk.SeatID, &bk.ConcertID, &bk.TotalAmount, &bk.SeatType,
+		&bk.ReceiptHash, &hasReceipt, &bk.ReceiptType, &hasThumb, &bk.SeatQuantity, &bk.SeatID, &bk.ConcertID, &bk.TotalAmount, &bk.SeatType,
 		&participantIDsJSON, &bk.CreatedAt, &bk.UserNotes,
+		&bk.RejectionCode, &bk.RejectionReason,
+		&bk.PaymentReference, &priceJSON, &billingJSON,
+		&bk.TransferredFrom, &bk.TicketCode,
+		&bk.UserID, &bk.ContactEmail, &bk.SeatsReserved, &bk.Currency,
 	)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
 			logger.Log.Warn(fmt.Sprintf("[get-booking-uc] Transactional read failed for %s: Booking not found.", bookingID))
-			return nil, fmt.Errorf("booking with ID %s not found", bookingID)
+			return nil, fmt.Errorf("%w: %s", ErrBookingNotFound, bookingID)
 		}
 		logger.Log.Error(fmt.Sprintf("[get-booking-uc] Transactional query error for %s: %v", bookingID, err))
 		return nil, fmt.Errorf("transactional query error: %w", err)
 	}
 
-	bk.ReceiptImage = receiptImage
+	bk.setReceiptLinks(hasReceipt, hasThumb)
+	bk.Price = priceBreakdownFrom(priceJSON)
+	bk.Billing = billingFrom(billingJSON)
 
 	if err := json.Unmarshal(participantIDsJSON, &bk.ParticipantIDs); err != nil {
 		logger.Log.Error(fmt.Sprintf("[get-booking-uc] Transactional unmarshal failed for %s: %v", bookingID, err))
@@ -0,0 +1,38 @@
+package booking
+
+import (
+	"database/sql"
+	"fmt"
+
+	"supra/concert/domain"
+	"supra/db"
+	"supra/logger"
+
+	"github.com/google/uuid"
+)
+
+// checkConcertPayment ensures the chosen payment method is attached to the
+// concert and enabled for new bookings, and returns the currency it charges in.
+func checkConcertPayment(concertID, paymentID string) (string, error) {
+	cID, err := uuid.Parse(concertID)
+	if err != nil {
+		return "", fmt.Errorf("invalid concert ID format: %w", err)
+	}
+	pID, err := uuid.Parse(paymentID)
+	if err != nil {
+		return "", fmt.Errorf("invalid payment ID format: %w", err)
+	}
+
+	var enabled bool
+	var currency string
+	err = db.DB.QueryRow(`SELECT enabled, currency FROM concert_payment WHERE concert_id = $1 AND payment_id = $2`, cID, pID).Scan(&enabled, &currency)
+	if err == sql.ErrNoRows || (err == nil && !enabled) {
+		logger.Log.Warn(fmt.Sprintf("[get-concert-payment-uc] Payment %s is not enabled for concert %s", paymentID, concertID))
+		return "", domain.ErrPaymentNotAllowed
+	}
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("[get-concert-payment-uc] Database query error for %s/%s: %v", concertID, paymentID, err))
+		return "", fmt.Errorf("database query error: %w", err)
+	}
+	return currency, nil
+}
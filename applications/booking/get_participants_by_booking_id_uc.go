@@ -2,6 +2,8 @@ package booking
 
 import (
 	"fmt"
+	"strings"
+	"unicode/utf8"
 
 	"supra/applications/participant"
 	"supra/logger"
@@ -37,3 +39,47 @@ func GetAllParticipantByBookingID(bookingID string) ([]*participant.Participant,
 	logger.Log.Info(fmt.Sprintf("[get-all-participants-uc] Successfully retrieved %d participants for bookingID: %s", len(participants), bookingID))
 	return participants, nil
 }
+
+// MaskedParticipant is what the public ticket QR shows to anyone who is not
+// the booking owner or door staff.
+type MaskedParticipant struct {
+	Name          string `json:"name"` // first name and last initial
+	Attended      bool   `json:"attended"`
+	BookingStatus string `json:"bookingStatus"`
+}
+
+// GetMaskedParticipantsByBookingID returns the participants of a booking with
+// contact details removed and names shortened.
+func GetMaskedParticipantsByBookingID(bookingID string) ([]*MaskedParticipant, error) {
+	bk, err := GetBooking(bookingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch booking: %w", err)
+	}
+
+	masked := make([]*MaskedParticipant, 0, len(bk.ParticipantIDs))
+	for _, pid := range bk.ParticipantIDs {
+		pt, err := participant.GetParticipant(pid)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch participantID %s: %w", pid, err)
+		}
+		masked = append(masked, &MaskedParticipant{
+			Name:          maskName(pt.Name),
+			Attended:      pt.Attended,
+			BookingStatus: bk.BookingStatus,
+		})
+	}
+	return masked, nil
+}
+
+// maskName turns "Priya Ramesh Kumar" into "Priya K.".
+func maskName(name string) string {
+	parts := strings.Fields(name)
+	if len(parts) == 0 {
+		return ""
+	}
+	if len(parts) == 1 {
+		return parts[0]
+	}
+	initial, _ := utf8.DecodeRuneInString(parts[len(parts)-1])
+	return fmt.Sprintf("%s %c.", parts[0], initial)
+}
@@ -2,6 +2,7 @@ package booking
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"fmt"
 
@@ -19,8 +20,12 @@ func GetPendingBookingsByConcertID(concertID string) ([]*Booking, error) {
 
 	selectAllSQL := `
 			SELECT booking_id, booking_email, booking_status, payment_details_id,
-			       receipt_image, seat_quantity, seat_id, concert_id, total_amount, seat_type,
-			       participant_ids, created_at, user_notes
+			       COALESCE(receipt_hash, ''), (receipt_key IS NOT NULL OR receipt_image IS NOT NULL),
+			       COALESCE(receipt_content_type, ''), (receipt_thumb_key IS NOT NULL),
+			       seat_quantity, seat_id, concert_id, total_amount, currency, seat_type,
+			       participant_ids, created_at, user_notes,
+			       COALESCE(duplicate_of::text, ''), COALESCE(duplicate_match, ''),
+			       COALESCE(ocr_status, ''), ocr_amount, COALESCE(ocr_date, ''), COALESCE(ocr_reference, '')
 			FROM booking
 			WHERE concert_id = $1
 			  AND booking_status IN ('VERIFYING', 'PENDING_VERIFICATION')
@@ -43,12 +48,17 @@ func GetPendingBookingsByConcertID(concertID string) ([]*Booking, error) {
 		bk := &Booking{}
 		var participantIDsJSON []byte
 		var bookingIDUUID uuid.UUID
-		var receiptImage []byte
+		var hasReceipt, hasThumb bool
+		var dupOf, dupMatch string
+		var ocrStatus, ocrDate, ocrRef string
+		var ocrAmount sql.NullFloat64
 
 		err := rows.Scan(
 			&bookingIDUUID, &bk.BookingEmail, &bk.BookingStatus, &bk.PaymentDetailsID,
-			&receiptImage, &bk.SeatQuantity, &bk.SeatID, &bk.ConcertID,
-			&bk.TotalAmount, &bk.SeatType, &participantIDsJSON, &bk.CreatedAt, &bk.UserNotes,
+			&bk.ReceiptHash, &hasReceipt, &bk.ReceiptType, &hasThumb, &bk.SeatQuantity, &bk.SeatID, &bk.ConcertID,
+			&bk.TotalAmount, &bk.Currency, &bk.SeatType, &participantIDsJSON, &bk.CreatedAt, &bk.UserNotes,
+			&dupOf, &dupMatch,
+			&ocrStatus, &ocrAmount, &ocrDate, &ocrRef,
 		)
 		if err != nil {
 			logger.Log.Error(fmt.Sprintf("[get-all-booking-concertID-uc] Error scanning booking row for %s: %v", concertID, err))
@@ -56,7 +66,9 @@ func GetPendingBookingsByConcertID(concertID string) ([]*Booking, error) {
 		}
 
 		bk.BookingID = bookingIDUUID
-		bk.ReceiptImage = receiptImage
+		bk.setReceiptLinks(hasReceipt, hasThumb)
+		bk.DuplicateReceipt = receiptMatchFrom(dupOf, dupMatch)
+		bk.OCR = receiptOCRFrom(ocrStatus, ocrAmount, ocrDate, ocrRef, bk.TotalAmount)
 
 		// Unmarshal participant IDs
 		if len(participantIDsJSON) > 0 && string(participantIDsJSON) != "null" {
@@ -2,6 +2,7 @@ package booking
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"fmt"
 
@@ -17,8 +18,13 @@ func GetPendingBookingsUC() ([]*Booking, error) {
 	query := `
 		SELECT 
 			booking_id, booking_email, booking_status, payment_details_id,
-			receipt_image, seat_quantity, seat_id, concert_id, total_amount, seat_type,
-			participant_ids, created_at, user_notes
+			COALESCE(receipt_hash, ''), (receipt_key IS NOT NULL OR receipt_image IS NOT NULL),
+			COALESCE(receipt_content_type, ''), (receipt_thumb_key IS NOT NULL),
+			seat_quantity, seat_id, concert_id, total_amount, currency, seat_type,
+			participant_ids, created_at, user_notes,
+			COALESCE(duplicate_of::text, ''), COALESCE(duplicate_match, ''),
+			COALESCE(ocr_status, ''), ocr_amount, COALESCE(ocr_date, ''), COALESCE(ocr_reference, ''),
+			COALESCE(payment_reference, '')
 		FROM booking
 		WHERE booking_status IN ('VERIFYING', 'PENDING_VERIFICATION')
 		ORDER BY created_at DESC
@@ -37,13 +43,21 @@ func GetPendingBookingsUC() ([]*Booking, error) {
 		var (
 			bk                Booking
 			participantIDsRaw []byte
-			receiptBytes      []byte
+			hasReceipt        bool
+			hasThumb          bool
+			dupOf, dupMatch   string
+			ocrStatus         string
+			ocrAmount         sql.NullFloat64
+			ocrDate, ocrRef   string
 		)
 
 		if err := rows.Scan(
 			&bk.BookingID, &bk.BookingEmail, &bk.BookingStatus, &bk.PaymentDetailsID,
-			&receiptBytes, &bk.SeatQuantity, &bk.SeatID, &bk.ConcertID, &bk.TotalAmount,
+			&bk.ReceiptHash, &hasReceipt, &bk.ReceiptType, &hasThumb, &bk.SeatQuantity, &bk.SeatID, &bk.ConcertID, &bk.TotalAmount, &bk.Currency,
 			&bk.SeatType, &participantIDsRaw, &bk.CreatedAt, &bk.UserNotes,
+			&dupOf, &dupMatch,
+			&ocrStatus, &ocrAmount, &ocrDate, &ocrRef,
+			&bk.PaymentReference,
 		); err != nil {
 			logger.Log.Warn(fmt.Sprintf("[get-pending-bookings-uc] Row scan failed: %v", err))
 			continue
@@ -53,7 +67,9 @@ func GetPendingBookingsUC() ([]*Booking, error) {
 			_ = json.Unmarshal(participantIDsRaw, &bk.ParticipantIDs)
 		}
 
-		bk.ReceiptImage = receiptBytes
+		bk.setReceiptLinks(hasReceipt, hasThumb)
+		bk.DuplicateReceipt = receiptMatchFrom(dupOf, dupMatch)
+		bk.OCR = receiptOCRFrom(ocrStatus, ocrAmount, ocrDate, ocrRef, bk.TotalAmount)
 		bookings = append(bookings, &bk)
 	}
 
@@ -0,0 +1,241 @@
+package booking
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"supra/applications/invoice"
+	"supra/db"
+	"supra/logger"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// BillingDetails are the optional buyer details printed on the invoice.
+type BillingDetails struct {
+	Name    string `json:"name,omitempty"`
+	Address string `json:"address,omitempty"`
+	TaxID   string `json:"taxID,omitempty"` // GSTIN / VAT number of a corporate buyer
+}
+
+var ErrNotInvoiceable = errors.New("invoices are only available for approved or confirmed bookings")
+
+func billingFrom(raw []byte) *BillingDetails {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+	b := &BillingDetails{}
+	if err := json.Unmarshal(raw, b); err != nil {
+		return nil
+	}
+	return b
+}
+
+// invoiceSource turns the booking's price breakdown into invoice lines.
+func (bk *Booking) invoiceSource() invoice.Source {
+	src := invoice.Source{
+		BookingID: bk.BookingID.String(),
+		Buyer:     invoice.Party{Email: bk.BookingEmail},
+		Total:     bk.TotalAmount,
+		Currency:  bk.Currency,
+	}
+	if bk.Billing != nil {
+		src.Buyer.Name, src.Buyer.Address, src.Buyer.TaxID = bk.Billing.Name, bk.Billing.Address, bk.Billing.TaxID
+	}
+
+	pb := bk.Price
+	if pb == nil {
+		// Bookings made before price breakdowns were recorded.
+		unit := 0.0
+		if bk.SeatQuantity > 0 {
+			unit = roundMoney(bk.TotalAmount / float64(bk.SeatQuantity))
+		}
+		src.Lines = []invoice.Line{{Description: bk.SeatType + " ticket", Quantity: bk.SeatQuantity, UnitPrice: unit, Amount: bk.TotalAmount}}
+		return src
+	}
+
+	src.Lines = append(src.Lines, invoice.Line{
+		Description: fmt.Sprintf("%s ticket (%s)", bk.SeatType, pb.tierLabel()),
+		Quantity:    pb.Quantity, UnitPrice: pb.UnitPrice, Amount: pb.Subtotal,
+	})
+	if pb.Discount > 0 {
+		src.Lines = append(src.Lines, invoice.Line{Description: "Discount " + pb.PromoCode, Amount: -pb.Discount})
+	}
+	for _, f := range pb.Fees {
+		src.Lines = append(src.Lines, invoice.Line{Description: feeLabel(f.Name), Amount: f.Amount})
+	}
+	for _, t := range pb.Taxes {
+		src.Lines = append(src.Lines, invoice.Line{Description: fmt.Sprintf("%s %.4g%% on %.2f", t.Name, t.Rate, t.Base), Amount: t.Amount})
+	}
+	return src
+}
+
+// issueBookingInvoice issues the invoice of a freshly approved or confirmed
+// booking. Failures are logged; the invoice is issued on first download instead.
+func issueBookingInvoice(bookingID string) {
+	if _, err := getOrIssueInvoice(bookingID); err != nil {
+		logger.Log.Warn(fmt.Sprintf("[invoice] ⚠️ Could not issue invoice for %s: %v", bookingID, err))
+	}
+}
+
+func getOrIssueInvoice(bookingID string) (*invoice.Invoice, error) {
+	if inv, err := invoice.GetInvoiceUC(bookingID); err != invoice.ErrInvoiceNotFound {
+		return inv, err
+	}
+
+	bk, err := GetBooking(bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if bk.BookingStatus != APPROVED && bk.BookingStatus != CONFIRMED {
+		return nil, ErrNotInvoiceable
+	}
+	// Transferred tickets were paid for, and invoiced, on the original booking.
+	if bk.TransferredFrom != "" {
+		return nil, fmt.Errorf("%w: tickets received by transfer are invoiced to the original buyer", ErrNotInvoiceable)
+	}
+
+	tx, err := db.DB.BeginTx(context.Background(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	inv, err := invoice.IssueInvoiceTx(tx, bk.invoiceSource())
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit failed: %w", err)
+	}
+	return inv, nil
+}
+
+// GetBookingInvoiceUC returns the booking's invoice as a PDF, issuing it on
+// first request.
+func GetBookingInvoiceUC(bookingID string) ([]byte, *invoice.Invoice, error) {
+	logger.Log.Info(fmt.Sprintf("[invoice] Invoice requested for booking %s", bookingID))
+
+	inv, err := getOrIssueInvoice(bookingID)
+	if err != nil {
+		return nil, nil, err
+	}
+	pdfBytes, err := InvoicePDF(inv)
+	return pdfBytes, inv, err
+}
+
+// InvoicePDF renders an invoice or credit note with the eTicket's branding.
+func InvoicePDF(inv *invoice.Invoice) ([]byte, error) {
+	title := "TAX INVOICE"
+	if inv.Kind == invoice.KindCreditNote {
+		title = "CREDIT NOTE"
+	}
+
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(20, 20, 20)
+	pdf.AddPage()
+
+	SafeAddImage(pdf, "resources/whitelogo.png", 160, 12, 30, false)
+
+	pdf.SetFont("Helvetica", "B", 20)
+	pdf.SetTextColor(216, 27, 96)
+	pdf.Cell(0, 10, title)
+	pdf.Ln(12)
+
+	pdf.SetDrawColor(216, 27, 96)
+	pdf.SetLineWidth(0.8)
+	pdf.Line(20, 33, 190, 33)
+
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.SetTextColor(40, 40, 40)
+	meta := [][2]string{
+		{"Number", inv.Number},
+		{"Date", inv.IssuedAt.Format("02 Jan 2006")},
+		{"Booking ID", inv.BookingID},
+	}
+	if inv.RelatedNumber != "" {
+		meta = append(meta, [2]string{"Against invoice", inv.RelatedNumber})
+	}
+	for _, m := range meta {
+		pdf.CellFormat(40, 6, m[0], "", 0, "L", false, 0, "")
+		pdf.CellFormat(0, 6, ": "+m[1], "", 1, "L", false, 0, "")
+	}
+	pdf.Ln(4)
+
+	// --- Seller / Buyer ---
+	y := pdf.GetY()
+	partyBlock(pdf, 20, y, "SELLER", inv.Seller)
+	partyBlock(pdf, 110, y, "BILL TO", inv.Buyer)
+	pdf.SetY(y + 38)
+
+	// --- Line items ---
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.SetFillColor(15, 15, 15)
+	pdf.SetTextColor(255, 255, 255)
+	pdf.CellFormat(90, 8, "Description", "", 0, "L", true, 0, "")
+	pdf.CellFormat(20, 8, "Qty", "", 0, "R", true, 0, "")
+	pdf.CellFormat(30, 8, "Unit price", "", 0, "R", true, 0, "")
+	pdf.CellFormat(30, 8, "Amount", "", 1, "R", true, 0, "")
+
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.SetTextColor(40, 40, 40)
+	for _, l := range inv.Lines {
+		qty, unit := "", ""
+		if l.Quantity > 0 {
+			qty, unit = fmt.Sprintf("%d", l.Quantity), fmt.Sprintf("%.2f", l.UnitPrice)
+		}
+		pdf.CellFormat(90, 7, l.Description, "B", 0, "L", false, 0, "")
+		pdf.CellFormat(20, 7, qty, "B", 0, "R", false, 0, "")
+		pdf.CellFormat(30, 7, unit, "B", 0, "R", false, 0, "")
+		pdf.CellFormat(30, 7, fmt.Sprintf("%.2f", l.Amount), "B", 1, "R", false, 0, "")
+	}
+
+	pdf.SetFont("Helvetica", "B", 11)
+	pdf.CellFormat(140, 9, "Total ("+inv.Currency+")", "", 0, "R", false, 0, "")
+	pdf.CellFormat(30, 9, fmt.Sprintf("%.2f", inv.Total), "", 1, "R", false, 0, "")
+
+	if inv.Note != "" {
+		pdf.Ln(4)
+		pdf.SetFont("Helvetica", "I", 9)
+		pdf.MultiCell(0, 5, "Note: "+inv.Note, "", "L", false)
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("failed to render %s PDF: %w", kindLabel(inv.Kind), err)
+	}
+	return buf.Bytes(), nil
+}
+
+func partyBlock(pdf *gofpdf.Fpdf, x, y float64, heading string, p invoice.Party) {
+	pdf.SetXY(x, y)
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.SetTextColor(216, 27, 96)
+	pdf.CellFormat(80, 6, heading, "", 2, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.SetTextColor(40, 40, 40)
+	for _, v := range []string{p.Name, p.Address, taxIDLine(p.TaxID), p.Email} {
+		if v == "" {
+			continue
+		}
+		pdf.SetX(x)
+		pdf.MultiCell(80, 5, v, "", "L", false)
+	}
+}
+
+func taxIDLine(id string) string {
+	if id == "" {
+		return ""
+	}
+	return "Tax ID: " + id
+}
+
+func kindLabel(kind string) string {
+	if kind == invoice.KindCreditNote {
+		return "credit note"
+	}
+	return "invoice"
+}
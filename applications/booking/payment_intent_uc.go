@@ -0,0 +1,217 @@
+package booking
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"supra/applications/auth"
+	"supra/db"
+	"supra/logger"
+	"supra/payments"
+
+	"github.com/google/uuid"
+)
+
+// Payment intent statuses.
+const (
+	IntentCreated        = "CREATED"
+	IntentSucceeded      = "SUCCEEDED"
+	IntentFailed         = "FAILED"
+	IntentAmountMismatch = "AMOUNT_MISMATCH"
+)
+
+var (
+	ErrBookingNotPayable = errors.New("booking cannot be paid online in its current status")
+	ErrUnknownIntent     = errors.New("payment intent not found")
+)
+
+// CreatePaymentIntentUC opens a payment with the chosen gateway for the booking total.
+func CreatePaymentIntentUC(bookingID string, payload []byte) (*payments.Intent, error) {
+	logger.Log.Info(fmt.Sprintf("[payment-intent-uc] Creating payment intent for booking %s", bookingID))
+
+	var p struct {
+		Provider string `json:"provider"`
+	}
+	if len(payload) > 0 {
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return nil, fmt.Errorf("invalid payload: %w", err)
+		}
+	}
+
+	provider, err := payments.Get(p.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	bk, err := GetBooking(bookingID)
+	if err != nil {
+		return nil, err
+	}
+	switch bk.BookingStatus {
+	case VERIFYING, PENDING_VERIFICATION, REJECTED:
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrBookingNotPayable, bk.BookingStatus)
+	}
+
+	ctx := context.Background()
+	intent, err := provider.CreateIntent(ctx, payments.IntentParams{
+		BookingID: bk.BookingID.String(),
+		Amount:    bk.TotalAmount,
+		Currency:  bk.Currency,
+		Email:     bk.BookingEmail,
+	})
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("[payment-intent-uc] ❌ %s intent creation failed for %s: %v", provider.Name(), bookingID, err))
+		return nil, err
+	}
+
+	if _, err := db.DB.ExecContext(ctx, `
+		INSERT INTO payment_intent (intent_id, booking_id, provider, amount, currency, status, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		intent.ID, bk.BookingID, provider.Name(), intent.Amount, intent.Currency, IntentCreated, time.Now()); err != nil {
+		logger.Log.Error(fmt.Sprintf("[payment-intent-uc] ❌ Failed to record intent %s: %v", intent.ID, err))
+		return nil, fmt.Errorf("failed to record payment intent: %w", err)
+	}
+
+	logger.Log.Info(fmt.Sprintf("[payment-intent-uc] ✅ Intent %s (%s) created for booking %s", intent.ID, provider.Name(), bookingID))
+	return intent, nil
+}
+
+// HandlePaymentWebhookUC verifies a gateway webhook and confirms the booking once
+// the payment has succeeded. Replayed events are ignored.
+func HandlePaymentWebhookUC(providerName string, header http.Header, body []byte) error {
+	provider, err := payments.Get(providerName)
+	if err != nil {
+		return err
+	}
+
+	ev, err := provider.ParseWebhook(header, body)
+	if err != nil {
+		return err
+	}
+	logger.Log.Info(fmt.Sprintf("[payment-webhook-uc] %s event %s for intent %s", provider.Name(), ev.Type, ev.IntentID))
+
+	ctx := context.Background()
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var (
+		bookingID     uuid.UUID
+		amount        int64
+		currency      string
+		currentStatus string
+	)
+	err = tx.QueryRow(`
+		SELECT booking_id, amount, currency, status
+		FROM payment_intent
+		WHERE intent_id = $1 AND provider = $2
+		FOR UPDATE`, ev.IntentID, provider.Name()).Scan(&bookingID, &amount, &currency, &currentStatus)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("%w: %s", ErrUnknownIntent, ev.IntentID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load payment intent: %w", err)
+	}
+	if currentStatus == IntentSucceeded {
+		logger.Log.Info(fmt.Sprintf("[payment-webhook-uc] Intent %s already settled; ignoring replay.", ev.IntentID))
+		return nil
+	}
+
+	status := IntentFailed
+	if ev.Type == payments.EventSucceeded {
+		status = IntentSucceeded
+		if ev.Amount != amount || !strings.EqualFold(ev.Currency, currency) {
+			status = IntentAmountMismatch
+			logger.Log.Warn(fmt.Sprintf("[payment-webhook-uc] ⚠️ Intent %s paid %d %s, expected %d %s", ev.IntentID, ev.Amount, ev.Currency, amount, currency))
+		}
+	}
+
+	if _, err := tx.Exec(`
+		UPDATE payment_intent
+		SET status = $2, provider_payment_id = NULLIF($3, ''), updated_at = $4
+		WHERE intent_id = $1`, ev.IntentID, status, ev.PaymentID, time.Now()); err != nil {
+		return fmt.Errorf("failed to update payment intent: %w", err)
+	}
+
+	confirmed := false
+	if status == IntentSucceeded {
+		err := reclaimSeatsTx(tx, bookingID)
+		switch {
+		case errors.Is(err, ErrNotApprovable), errors.Is(err, ErrNotEnoughSeats):
+			// The payment is recorded on the intent; staff refund it by hand.
+			logger.Log.Error(fmt.Sprintf("[payment-webhook-uc] ❌ Booking %s paid via intent %s but cannot be confirmed: %v", bookingID, ev.IntentID, err))
+		case err != nil:
+			return err
+		default:
+			res, err := tx.Exec(`
+				UPDATE booking
+				SET booking_status = $2, updated_at = $3
+				WHERE booking_id = $1 AND booking_status NOT IN ($2, $4, $5)`,
+				bookingID, CONFIRMED, time.Now(), APPROVED, CANCELLED)
+			if err != nil {
+				return fmt.Errorf("failed to confirm booking: %w", err)
+			}
+			n, _ := res.RowsAffected()
+			confirmed = n > 0
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit failed: %w", err)
+	}
+
+	if confirmed {
+		logger.Log.Info(fmt.Sprintf("[payment-webhook-uc] ✅ Booking %s CONFIRMED via %s", bookingID, provider.Name()))
+		go sendConfirmationTicket(bookingID.String())
+	}
+	return nil
+}
+
+// sendConfirmationTicket emails the eTicket for a booking confirmed by the gateway.
+func sendConfirmationTicket(bookingID string) {
+	issueBookingInvoice(bookingID)
+	bk, pdfBytes, err := GenerateTicketPDF(bookingID)
+	if err != nil {
+		logger.Log.Warn(fmt.Sprintf("[payment-webhook-uc] ⚠️ PDF generation failed for %s: %v", bookingID, err))
+		return
+	}
+	if err := auth.SendBookingApprovalMail(bk.NotifyEmail(), bookingID, bk.SeatType, bk.SeatQuantity, bk.TotalAmount, bk.Currency, bk.Price.Lines(), pdfBytes); err != nil {
+		logger.Log.Warn(fmt.Sprintf("[payment-webhook-uc] ⚠️ Email sending failed for %s: %v", bookingID, err))
+	}
+}
+
+// SimulateFakePaymentUC plays the fake gateway: it signs a webhook for the intent
+// and feeds it through the regular webhook handler.
+func SimulateFakePaymentUC(intentID string, succeed bool) error {
+	provider, err := payments.Get("fake")
+	if err != nil {
+		return err
+	}
+	fake := provider.(*payments.Fake)
+
+	var amount int64
+	var currency string
+	err = db.DB.QueryRow(`SELECT amount, currency FROM payment_intent WHERE intent_id = $1 AND provider = 'fake'`, intentID).Scan(&amount, &currency)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("%w: %s", ErrUnknownIntent, intentID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load payment intent: %w", err)
+	}
+
+	ev := payments.Event{Type: payments.EventSucceeded, IntentID: intentID, Amount: amount, Currency: currency}
+	if !succeed {
+		ev.Type = payments.EventFailed
+	}
+	header, body := fake.Simulate(ev)
+	return HandlePaymentWebhookUC(fake.Name(), header, body)
+}
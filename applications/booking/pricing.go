@@ -0,0 +1,212 @@
+package booking
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"supra/applications/auth"
+	"supra/applications/promotion"
+	"supra/applications/seat"
+	"supra/concert/domain"
+	"supra/logger"
+)
+
+// PriceBreakdown records how a booking's total was computed.
+type PriceBreakdown struct {
+	Tier      string  `json:"tier,omitempty"`
+	UnitPrice float64 `json:"unitPrice"`
+	Quantity  int     `json:"quantity"`
+	Subtotal  float64 `json:"subtotal"`
+	PromoCode string  `json:"promoCode,omitempty"`
+	Discount  float64 `json:"discount,omitempty"`
+
+	Fees     []domain.ChargeLine `json:"fees,omitempty"`
+	FeeTotal float64             `json:"feeTotal,omitempty"`
+	Taxes    []domain.TaxLine    `json:"taxes,omitempty"`
+	TaxTotal float64             `json:"taxTotal,omitempty"`
+
+	Total    float64 `json:"total"`
+	Currency string  `json:"currency,omitempty"`
+}
+
+func roundMoney(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// ErrNoPrice is returned for seats that have no price configured in the
+// booking's currency; the client-submitted amount is never used in its place.
+var ErrNoPrice = errors.New("no price configured for this seat")
+
+// unitPrice picks the price in the booking's currency.
+func unitPrice(currency string, inr, gel float64) float64 {
+	if currency == domain.CurrencyGEL {
+		return gel
+	}
+	return inr
+}
+
+// priceBookingTx prices a booking at the seat's active tier, or at the price
+// locked by the customer's seat hold, in the currency of the chosen payment
+// method. It applies the promo code, if any, then adds the concert's fees
+// and taxes.
+func priceBookingTx(tx *sql.Tx, p *CreateBookingParams, st *seat.Seat) (*PriceBreakdown, error) {
+	pb := &PriceBreakdown{Quantity: p.SeatQuantity, Currency: p.currency}
+	if pb.Currency == "" {
+		pb.Currency = domain.CurrencyINR
+	}
+	if p.HoldID != "" {
+		hold, err := seat.ConsumeHoldTx(tx, p.HoldID, st.SeatID, p.BookingEmail, p.SeatQuantity)
+		if err != nil {
+			return nil, err
+		}
+		pb.Tier, pb.UnitPrice = hold.Tier, unitPrice(pb.Currency, hold.PriceInr, hold.PriceGel)
+	} else {
+		tier, _, err := seat.CurrentPrice(tx, st, p.SeatQuantity, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		pb.Tier, pb.UnitPrice = tier.Name, unitPrice(pb.Currency, tier.PriceInr, tier.PriceGel)
+	}
+
+	if pb.UnitPrice <= 0 {
+		return nil, fmt.Errorf("%w: seat %s in %s", ErrNoPrice, st.SeatID, pb.Currency)
+	}
+	pb.Subtotal = roundMoney(pb.UnitPrice * float64(p.SeatQuantity))
+
+	if p.PromoCode != "" {
+		promo, discount, err := promotion.ApplyTx(tx, p.PromoCode, promotion.Cart{
+			ConcertID: p.ConcertID,
+			SeatType:  st.SeatType,
+			Quantity:  p.SeatQuantity,
+			Email:     p.BookingEmail,
+			Subtotal:  pb.Subtotal,
+		})
+		if err != nil {
+			return nil, err
+		}
+		pb.PromoCode = promo.Code
+		pb.Discount = discount
+	}
+
+	charges, err := concertChargesTx(tx, p.ConcertID)
+	if err != nil {
+		return nil, err
+	}
+	net := roundMoney(pb.Subtotal - pb.Discount)
+	pb.Fees, pb.Taxes = charges.Apply(net, p.SeatQuantity)
+	for _, f := range pb.Fees {
+		pb.FeeTotal += f.Amount
+	}
+	for _, t := range pb.Taxes {
+		pb.TaxTotal += t.Amount
+	}
+	pb.FeeTotal, pb.TaxTotal = roundMoney(pb.FeeTotal), roundMoney(pb.TaxTotal)
+
+	pb.Total = roundMoney(net + pb.FeeTotal + pb.TaxTotal)
+	if p.TotalAmount > 0 && math.Abs(p.TotalAmount-pb.Total) >= 0.01 {
+		logger.Log.Warn(fmt.Sprintf("[create-booking-uc] ⚠️ Client total %.2f differs from computed %.2f; using computed", p.TotalAmount, pb.Total))
+	}
+	return pb, nil
+}
+
+func concertChargesTx(tx *sql.Tx, concertID string) (*domain.Charges, error) {
+	var raw []byte
+	if err := tx.QueryRow(`SELECT charges FROM concert WHERE concert_id = $1`, concertID).Scan(&raw); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("concert with ID %s not found", concertID)
+		}
+		return nil, fmt.Errorf("failed to load concert charges: %w", err)
+	}
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	c := &domain.Charges{}
+	if err := json.Unmarshal(raw, c); err != nil {
+		return nil, fmt.Errorf("failed to decode concert charges: %w", err)
+	}
+	return c, nil
+}
+
+// Lines itemizes the breakdown for emails and tickets, skipping zero rows.
+func (pb *PriceBreakdown) Lines() []auth.PriceLine {
+	if pb == nil {
+		return nil
+	}
+	lines := []auth.PriceLine{{Label: fmt.Sprintf("%d x %s @ %.2f", pb.Quantity, pb.tierLabel(), pb.UnitPrice), Amount: pb.Subtotal}}
+	if pb.Discount > 0 {
+		lines = append(lines, auth.PriceLine{Label: "Discount (" + pb.PromoCode + ")", Amount: -pb.Discount})
+	}
+	for _, f := range pb.Fees {
+		lines = append(lines, auth.PriceLine{Label: feeLabel(f.Name), Amount: f.Amount})
+	}
+	for _, t := range pb.Taxes {
+		lines = append(lines, auth.PriceLine{Label: fmt.Sprintf("%s %.4g%%", t.Name, t.Rate), Amount: t.Amount})
+	}
+	return lines
+}
+
+func (pb *PriceBreakdown) tierLabel() string {
+	if pb.Tier == "" {
+		return "ticket"
+	}
+	return pb.Tier
+}
+
+func feeLabel(name string) string {
+	switch name {
+	case domain.BookingFee:
+		return "Booking fee"
+	case domain.ServiceFee:
+		return "Service fee"
+	}
+	return name
+}
+
+// priceBreakdownFrom decodes the stored breakdown; bookings made before it
+// was recorded return nil.
+func priceBreakdownFrom(raw []byte) *PriceBreakdown {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+	pb := &PriceBreakdown{}
+	if err := json.Unmarshal(raw, pb); err != nil {
+		return nil
+	}
+	return pb
+}
+
+// split divides the breakdown when moving of its seats leave the booking.
+// The moved part gets each amount pro rata, the kept part the remainder, so
+// both still add up and together equal the original.
+func (pb *PriceBreakdown) split(moving int) (moved, kept *PriceBreakdown) {
+	ratio := float64(moving) / float64(pb.Quantity)
+	part := func(v float64) float64 { return roundMoney(v * ratio) }
+
+	moved = &PriceBreakdown{Tier: pb.Tier, UnitPrice: pb.UnitPrice, Quantity: moving, PromoCode: pb.PromoCode, Currency: pb.Currency}
+	kept = &PriceBreakdown{Tier: pb.Tier, UnitPrice: pb.UnitPrice, Quantity: pb.Quantity - moving, PromoCode: pb.PromoCode, Currency: pb.Currency}
+
+	moved.Subtotal, moved.Discount = part(pb.Subtotal), part(pb.Discount)
+	kept.Subtotal, kept.Discount = roundMoney(pb.Subtotal-moved.Subtotal), roundMoney(pb.Discount-moved.Discount)
+	for _, f := range pb.Fees {
+		m := part(f.Amount)
+		moved.Fees = append(moved.Fees, domain.ChargeLine{Name: f.Name, Amount: m})
+		kept.Fees = append(kept.Fees, domain.ChargeLine{Name: f.Name, Amount: roundMoney(f.Amount - m)})
+		moved.FeeTotal += m
+	}
+	for _, t := range pb.Taxes {
+		mb, ma := part(t.Base), part(t.Amount)
+		moved.Taxes = append(moved.Taxes, domain.TaxLine{Name: t.Name, Rate: t.Rate, Base: mb, Amount: ma})
+		kept.Taxes = append(kept.Taxes, domain.TaxLine{Name: t.Name, Rate: t.Rate, Base: roundMoney(t.Base - mb), Amount: roundMoney(t.Amount - ma)})
+		moved.TaxTotal += ma
+	}
+	moved.FeeTotal, moved.TaxTotal = roundMoney(moved.FeeTotal), roundMoney(moved.TaxTotal)
+	kept.FeeTotal, kept.TaxTotal = roundMoney(pb.FeeTotal-moved.FeeTotal), roundMoney(pb.TaxTotal-moved.TaxTotal)
+
+	moved.Total = roundMoney(moved.Subtotal - moved.Discount + moved.FeeTotal + moved.TaxTotal)
+	kept.Total = roundMoney(pb.Total - moved.Total)
+	return moved, kept
+}
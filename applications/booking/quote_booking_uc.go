@@ -0,0 +1,64 @@
+package booking
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"supra/applications/seat"
+	"supra/db"
+	"supra/logger"
+	"supra/validation"
+
+	"github.com/google/uuid"
+)
+
+// QuoteBookingUC prices a prospective booking (tier or hold price, promo,
+// fees and taxes) without creating it, so the customer knows the exact amount
+// to pay before uploading a receipt.
+func QuoteBookingUC(email string, payload []byte) (*PriceBreakdown, error) {
+	var p CreateBookingParams
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return nil, fmt.Errorf("invalid quote payload: %w", err)
+	}
+	var errs validation.Errors
+	if p.SeatQuantity < 1 {
+		errs.Add("seatQuantity", "must be at least 1")
+	}
+	if _, err := uuid.Parse(p.SeatID); err != nil {
+		errs.Add("seatID", "must be a valid UUID")
+	}
+	if err := errs.Err(); err != nil {
+		return nil, err
+	}
+	p.BookingEmail = email
+	p.TotalAmount = 0
+
+	// Quotes without a payment method are in INR, the default currency.
+	if p.PaymentDetailsID != "" {
+		currency, err := checkConcertPayment(p.ConcertID, p.PaymentDetailsID)
+		if err != nil {
+			return nil, err
+		}
+		p.currency = currency
+	}
+
+	// Everything runs in a transaction that is always rolled back, so holds
+	// are not consumed and promo usage is not recorded.
+	tx, err := db.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	st, err := seat.GetSeatForUpdateTx(tx, p.SeatID)
+	if err != nil {
+		return nil, err
+	}
+	pb, err := priceBookingTx(tx, &p, st)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Log.Info(fmt.Sprintf("[quote-booking-uc] Quote for %s: %d x %s = %.2f", email, p.SeatQuantity, pb.tierLabel(), pb.Total))
+	return pb, nil
+}
@@ -0,0 +1,161 @@
+package booking
+
+import (
+	"database/sql"
+	"fmt"
+	"image"
+	"math/bits"
+	"os"
+	"time"
+
+	"supra/logger"
+
+	"github.com/google/uuid"
+)
+
+const (
+	MatchExact      = "EXACT"
+	MatchPerceptual = "PERCEPTUAL"
+
+	// perceptualMatchDistance is the largest Hamming distance between two dHashes
+	// that is still treated as the same screenshot (re-compressed, resized, cropped edges).
+	perceptualMatchDistance = 6
+)
+
+// DuplicateReceiptWindow reads RECEIPT_DUPLICATE_WINDOW (a Go duration,
+// default 90 days): how far back the perceptual receipt match looks.
+func DuplicateReceiptWindow() time.Duration {
+	if d, err := time.ParseDuration(os.Getenv("RECEIPT_DUPLICATE_WINDOW")); err == nil && d > 0 {
+		return d
+	}
+	return 90 * 24 * time.Hour
+}
+
+// ReceiptMatch points at another booking that was paid with the same receipt.
+type ReceiptMatch struct {
+	BookingID string `json:"bookingID"`
+	Match     string `json:"match"` // EXACT or PERCEPTUAL
+}
+
+// perceptualHash computes a 64-bit difference hash (dHash): the image is reduced
+// to 9x8 grayscale and each bit records whether a pixel is brighter than its
+// right-hand neighbour. Visually identical images hash to nearby values.
+func perceptualHash(img image.Image) uint64 {
+	const w, h = 9, 8
+	b := img.Bounds()
+	var gray [h][w]uint64
+	for y := 0; y < h; y++ {
+		y0, y1 := b.Min.Y+y*b.Dy()/h, b.Min.Y+(y+1)*b.Dy()/h
+		if y1 <= y0 {
+			y1 = y0 + 1
+		}
+		for x := 0; x < w; x++ {
+			x0, x1 := b.Min.X+x*b.Dx()/w, b.Min.X+(x+1)*b.Dx()/w
+			if x1 <= x0 {
+				x1 = x0 + 1
+			}
+			var sum, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					r, g, bl, _ := img.At(sx, sy).RGBA()
+					sum += (299*uint64(r) + 587*uint64(g) + 114*uint64(bl)) / 1000
+					n++
+				}
+			}
+			gray[y][x] = sum / n
+		}
+	}
+
+	var hash uint64
+	for y := 0; y < h; y++ {
+		for x := 0; x < w-1; x++ {
+			hash <<= 1
+			if gray[y][x] > gray[y][x+1] {
+				hash |= 1
+			}
+		}
+	}
+	return hash
+}
+
+// flagDuplicateReceiptTx looks for another live booking with the same receipt and
+// records the match on the booking. It returns nil when the receipt is unique.
+func flagDuplicateReceiptTx(tx *sql.Tx, bookingID uuid.UUID, sr *storedReceipt) (*ReceiptMatch, error) {
+	if sr == nil || sr.Hash == "" {
+		return nil, nil
+	}
+
+	match, err := findDuplicateReceiptTx(tx, bookingID, sr)
+	if err != nil {
+		return nil, err
+	}
+
+	var dupOf, dupMatch interface{}
+	if match != nil {
+		dupOf, dupMatch = match.BookingID, match.Match
+		logger.Log.Warn(fmt.Sprintf("[receipt-fingerprint] ⚠️ Booking %s reuses the receipt of booking %s (%s)", bookingID, match.BookingID, match.Match))
+	}
+	if _, err := tx.Exec(`
+		UPDATE booking SET duplicate_of = $2, duplicate_match = $3
+		WHERE booking_id = $1`, bookingID, dupOf, dupMatch); err != nil {
+		return nil, fmt.Errorf("failed to flag duplicate receipt: %w", err)
+	}
+	return match, nil
+}
+
+func findDuplicateReceiptTx(tx *sql.Tx, bookingID uuid.UUID, sr *storedReceipt) (*ReceiptMatch, error) {
+	var other string
+	err := tx.QueryRow(`
+		SELECT booking_id FROM booking
+		WHERE receipt_hash = $1 AND booking_id <> $2 AND booking_status <> $3
+		ORDER BY created_at
+		LIMIT 1`, sr.Hash, bookingID, CANCELLED).Scan(&other)
+	if err == nil {
+		return &ReceiptMatch{BookingID: other, Match: MatchExact}, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, fmt.Errorf("exact receipt lookup failed: %w", err)
+	}
+
+	if !sr.PHash.Valid {
+		return nil, nil
+	}
+	// Perceptual hashes cannot be indexed for distance, so only recent bookings
+	// are compared; a receipt is rarely reused months after it was paid.
+	rows, err := tx.Query(`
+		SELECT booking_id, receipt_phash FROM booking
+		WHERE receipt_phash IS NOT NULL AND booking_id <> $1 AND booking_status <> $2
+		  AND created_at > $3
+		ORDER BY created_at`, bookingID, CANCELLED, time.Now().Add(-DuplicateReceiptWindow()))
+	if err != nil {
+		return nil, fmt.Errorf("perceptual receipt lookup failed: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var phash int64
+		if err := rows.Scan(&other, &phash); err != nil {
+			return nil, fmt.Errorf("perceptual receipt scan failed: %w", err)
+		}
+		if bits.OnesCount64(uint64(phash)^uint64(sr.PHash.Int64)) <= perceptualMatchDistance {
+			return &ReceiptMatch{BookingID: other, Match: MatchPerceptual}, nil
+		}
+	}
+	return nil, rows.Err()
+}
+
+// String describes the match for admin notifications; empty when there is none.
+func (m *ReceiptMatch) String() string {
+	if m == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s match with booking %s", m.Match, m.BookingID)
+}
+
+// receiptMatchFrom builds a match from the nullable duplicate columns.
+func receiptMatchFrom(dupOf, dupMatch string) *ReceiptMatch {
+	if dupOf == "" {
+		return nil
+	}
+	return &ReceiptMatch{BookingID: dupOf, Match: dupMatch}
+}
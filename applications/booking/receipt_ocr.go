@@ -0,0 +1,121 @@
+package booking
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"supra/db"
+	"supra/logger"
+	"supra/ocr"
+
+	"github.com/google/uuid"
+)
+
+const (
+	OCRDone    = "DONE"
+	OCRFailed  = "FAILED"
+	OCRSkipped = "SKIPPED"
+
+	ocrQueueSize = 100
+	ocrTimeout   = time.Minute
+)
+
+// ReceiptOCR holds the payment details read from a receipt, for admins to compare.
+type ReceiptOCR struct {
+	Status         string   `json:"status"`
+	Amount         *float64 `json:"amount,omitempty"`
+	Date           string   `json:"date,omitempty"`
+	Reference      string   `json:"reference,omitempty"`
+	AmountMismatch bool     `json:"amountMismatch"`
+}
+
+type ocrJob struct {
+	bookingID   uuid.UUID
+	hash        string
+	data        []byte
+	contentType string
+}
+
+var ocrQueue chan ocrJob
+
+// StartReceiptOCRWorker starts the background worker that reads uploaded receipts.
+// It is a no-op when no OCR engine is configured.
+func StartReceiptOCRWorker() {
+	if ocr.Receipts == nil {
+		return
+	}
+	ocrQueue = make(chan ocrJob, ocrQueueSize)
+	go func() {
+		for job := range ocrQueue {
+			runReceiptOCR(job)
+		}
+	}()
+	logger.Log.Info(fmt.Sprintf("[receipt-ocr] Worker started (engine: %s)", ocr.Receipts.Name()))
+}
+
+// queueReceiptOCR schedules OCR for a freshly stored receipt without blocking the request.
+func queueReceiptOCR(bookingID uuid.UUID, sr *storedReceipt, data []byte) {
+	if ocrQueue == nil || sr == nil {
+		return
+	}
+	select {
+	case ocrQueue <- ocrJob{bookingID: bookingID, hash: sr.Hash, data: data, contentType: sr.ContentType}:
+	default:
+		logger.Log.Warn(fmt.Sprintf("[receipt-ocr] ⚠️ Queue full; skipping OCR for booking %s", bookingID))
+	}
+}
+
+func runReceiptOCR(job ocrJob) {
+	ctx, cancel := context.WithTimeout(context.Background(), ocrTimeout)
+	defer cancel()
+
+	status := OCRDone
+	var fields ocr.ReceiptFields
+	text, err := ocr.Receipts.Text(ctx, job.data, job.contentType)
+	switch {
+	case err == nil:
+		fields = ocr.ParseReceipt(text)
+	case errors.Is(err, ocr.ErrUnsupported):
+		status = OCRSkipped
+	default:
+		status = OCRFailed
+		logger.Log.Warn(fmt.Sprintf("[receipt-ocr] OCR failed for booking %s: %v", job.bookingID, err))
+	}
+
+	var amount sql.NullFloat64
+	if fields.HasAmount {
+		amount = sql.NullFloat64{Float64: fields.Amount, Valid: true}
+	}
+
+	// The hash guard keeps a slow job from overwriting results of a newer upload.
+	_, err = db.DB.ExecContext(ctx, `
+		UPDATE booking
+		SET ocr_status = $3, ocr_amount = $4, ocr_date = NULLIF($5, ''), ocr_reference = NULLIF($6, '')
+		WHERE booking_id = $1 AND receipt_hash = $2`,
+		job.bookingID, job.hash, status, amount, fields.Date, fields.Reference)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("[receipt-ocr] Failed to store OCR result for booking %s: %v", job.bookingID, err))
+		return
+	}
+	logger.Log.Info(fmt.Sprintf("[receipt-ocr] Booking %s: status=%s amount=%v date=%q ref=%q",
+		job.bookingID, status, fields.Amount, fields.Date, fields.Reference))
+}
+
+// receiptOCRFrom builds the OCR view from the nullable columns and flags amounts
+// that differ from what the booking claims was paid.
+func receiptOCRFrom(status string, amount sql.NullFloat64, date, reference string, total float64) *ReceiptOCR {
+	if status == "" {
+		return nil
+	}
+	r := &ReceiptOCR{Status: status, Date: date, Reference: reference}
+	if amount.Valid {
+		v := amount.Float64
+		r.Amount = &v
+		r.AmountMismatch = math.Abs(v-total) > 0.01
+	}
+	return r
+}
@@ -0,0 +1,162 @@
+package booking
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+
+	"supra/db"
+	"supra/logger"
+	"supra/storage"
+
+	"github.com/google/uuid"
+)
+
+// receiptKey builds the blob key for a booking receipt. The content hash is part
+// of the key so a re-upload never overwrites the blob of a previous receipt.
+func receiptKey(bookingID uuid.UUID, hash string) string {
+	return fmt.Sprintf("receipts/%s/%s", bookingID, hash)
+}
+
+// receiptURL is the API path that serves a booking's receipt.
+func receiptURL(bookingID uuid.UUID) string {
+	return fmt.Sprintf("/api/v1/bookings/%s/receipt", bookingID)
+}
+
+func hashReceipt(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// receiptThumbnailURL is the API path that serves a booking's receipt thumbnail.
+func receiptThumbnailURL(bookingID uuid.UUID) string {
+	return fmt.Sprintf("/api/v1/bookings/%s/receipt/thumbnail", bookingID)
+}
+
+// storedReceipt describes the blobs written for one receipt upload.
+type storedReceipt struct {
+	Key         string
+	Hash        string
+	ContentType string
+	ThumbKey    string        // empty when no thumbnail could be generated
+	PHash       sql.NullInt64 // perceptual fingerprint used for duplicate detection
+}
+
+// storeReceipt writes the processed receipt (and its thumbnail) to the blob store.
+func storeReceipt(ctx context.Context, bookingID uuid.UUID, rc *processedReceipt) (*storedReceipt, error) {
+	if storage.Receipts == nil {
+		return nil, fmt.Errorf("receipt store is not configured")
+	}
+	sr := &storedReceipt{Hash: hashReceipt(rc.Data), ContentType: rc.ContentType, PHash: rc.PHash}
+	sr.Key = receiptKey(bookingID, sr.Hash)
+	if err := storage.Receipts.Put(ctx, sr.Key, rc.Data, rc.ContentType); err != nil {
+		return nil, fmt.Errorf("failed to store receipt: %w", err)
+	}
+	if len(rc.Thumbnail) > 0 {
+		thumbKey := sr.Key + "-thumb"
+		if err := storage.Receipts.Put(ctx, thumbKey, rc.Thumbnail, "image/jpeg"); err != nil {
+			logger.Log.Warn(fmt.Sprintf("[receipt-store] Failed to store thumbnail for %s: %v", bookingID, err))
+		} else {
+			sr.ThumbKey = thumbKey
+		}
+	}
+	return sr, nil
+}
+
+// discard removes the blobs of a receipt that was never committed.
+func (sr *storedReceipt) discard(ctx context.Context) {
+	if sr == nil {
+		return
+	}
+	discardReceipt(ctx, sr.Key)
+	discardReceipt(ctx, sr.ThumbKey)
+}
+
+// discardReceipt removes a blob that is no longer referenced (best effort).
+func discardReceipt(ctx context.Context, key string) {
+	if key == "" || storage.Receipts == nil {
+		return
+	}
+	if err := storage.Receipts.Delete(ctx, key); err != nil {
+		logger.Log.Warn(fmt.Sprintf("[receipt-store] Failed to delete orphaned receipt %s: %v", key, err))
+	}
+}
+
+// MigrateReceiptsToStore moves receipt blobs still held in booking.receipt_image
+// into the blob store, leaving a key and content hash on the booking row.
+func MigrateReceiptsToStore() (int, error) {
+	logger.Log.Info("[receipt-store] Checking for receipts stored in the booking table...")
+
+	const batchSize = 50
+	ctx := context.Background()
+	moved := 0
+
+	for {
+		rows, err := db.DB.QueryContext(ctx, `
+			SELECT booking_id, receipt_image
+			FROM booking
+			WHERE receipt_image IS NOT NULL AND receipt_key IS NULL
+			LIMIT $1`, batchSize)
+		if err != nil {
+			return moved, fmt.Errorf("failed to select legacy receipts: %w", err)
+		}
+
+		type legacyReceipt struct {
+			id   uuid.UUID
+			data []byte
+		}
+		var batch []legacyReceipt
+		for rows.Next() {
+			var lr legacyReceipt
+			if err := rows.Scan(&lr.id, &lr.data); err != nil {
+				rows.Close()
+				return moved, fmt.Errorf("failed to scan legacy receipt: %w", err)
+			}
+			batch = append(batch, lr)
+		}
+		rows.Close()
+
+		if len(batch) == 0 {
+			break
+		}
+
+		for _, lr := range batch {
+			// Legacy receipts predate validation; keep them as-is when they cannot be processed.
+			rc, err := processReceipt(lr.data)
+			if err != nil {
+				rc = &processedReceipt{Data: lr.data, ContentType: http.DetectContentType(lr.data)}
+			}
+			sr, err := storeReceipt(ctx, lr.id, rc)
+			if err != nil {
+				return moved, fmt.Errorf("failed to move receipt for booking %s: %w", lr.id, err)
+			}
+			if _, err := db.DB.ExecContext(ctx, `
+				UPDATE booking
+				SET receipt_key = $2, receipt_hash = $3, receipt_content_type = $4,
+				    receipt_thumb_key = NULLIF($5, ''), receipt_phash = $6, receipt_image = NULL
+				WHERE booking_id = $1 AND receipt_key IS NULL`, lr.id, sr.Key, sr.Hash, sr.ContentType, sr.ThumbKey, sr.PHash); err != nil {
+				sr.discard(ctx)
+				return moved, fmt.Errorf("failed to update booking %s after moving receipt: %w", lr.id, err)
+			}
+			moved++
+		}
+		logger.Log.Info(fmt.Sprintf("[receipt-store] Moved %d receipts so far.", moved))
+	}
+
+	logger.Log.Info(fmt.Sprintf("[receipt-store] Receipt migration finished. %d receipts moved.", moved))
+	return moved, nil
+}
+
+// setReceiptLinks fills the receipt and thumbnail URLs from the flags selected
+// alongside the booking row.
+func (bk *Booking) setReceiptLinks(hasReceipt, hasThumb bool) {
+	if hasReceipt {
+		bk.ReceiptURL = receiptURL(bk.BookingID)
+	}
+	if hasThumb {
+		bk.ThumbnailURL = receiptThumbnailURL(bk.BookingID)
+	}
+}
@@ -0,0 +1,223 @@
+package booking
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"os"
+	"strconv"
+
+	"supra/logger"
+)
+
+const (
+	defaultMaxReceiptBytes = 10 << 20 // 10 MB
+	maxReceiptPixels       = 40_000_000
+	thumbnailMaxSide       = 320
+)
+
+var (
+	ErrReceiptTooLarge        = errors.New("receipt exceeds the maximum upload size")
+	ErrUnsupportedReceiptType = errors.New("unsupported receipt type (allowed: JPEG, PNG, WebP, PDF)")
+	ErrEmptyReceipt           = errors.New("receipt is empty")
+)
+
+// allowedReceiptTypes maps sniffed MIME types to the file extension used in emails.
+var allowedReceiptTypes = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/webp":      ".webp",
+	"application/pdf": ".pdf",
+}
+
+// processedReceipt is a validated receipt ready to be written to the blob store.
+type processedReceipt struct {
+	Data        []byte
+	ContentType string
+	Thumbnail   []byte        // JPEG thumbnail; nil when the format cannot be rendered
+	PHash       sql.NullInt64 // perceptual hash; only set for decodable images
+}
+
+// MaxReceiptBytes returns the upload limit for receipts (MAX_RECEIPT_BYTES, default 10 MB).
+func MaxReceiptBytes() int64 {
+	if v, err := strconv.ParseInt(os.Getenv("MAX_RECEIPT_BYTES"), 10, 64); err == nil && v > 0 {
+		return v
+	}
+	return defaultMaxReceiptBytes
+}
+
+// ReceiptExtension returns the file extension for a receipt content type.
+func ReceiptExtension(contentType string) string {
+	if ext, ok := allowedReceiptTypes[contentType]; ok {
+		return ext
+	}
+	return ".bin"
+}
+
+// processReceipt sniffs the uploaded bytes, enforces the size limit, strips
+// metadata (EXIF, XMP) by re-encoding images and generates a thumbnail.
+func processReceipt(raw []byte) (*processedReceipt, error) {
+	if len(raw) == 0 {
+		return nil, ErrEmptyReceipt
+	}
+	if int64(len(raw)) > MaxReceiptBytes() {
+		return nil, fmt.Errorf("%w (%d bytes, limit %d)", ErrReceiptTooLarge, len(raw), MaxReceiptBytes())
+	}
+
+	contentType := http.DetectContentType(raw)
+	if _, ok := allowedReceiptTypes[contentType]; !ok {
+		return nil, fmt.Errorf("%w: detected %s", ErrUnsupportedReceiptType, contentType)
+	}
+
+	switch contentType {
+	case "image/jpeg", "image/png":
+		img, err := decodeReceiptImage(raw)
+		if err != nil {
+			return nil, err
+		}
+
+		// Re-encoding writes pixel data only, which drops EXIF/GPS and other metadata.
+		var buf bytes.Buffer
+		if contentType == "image/png" {
+			err = png.Encode(&buf, img)
+		} else {
+			err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: 90})
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to re-encode receipt: %w", err)
+		}
+
+		thumb, err := makeThumbnail(img)
+		if err != nil {
+			logger.Log.Warn(fmt.Sprintf("[receipt-upload] Thumbnail generation failed: %v", err))
+		}
+		return &processedReceipt{
+			Data:        buf.Bytes(),
+			ContentType: contentType,
+			Thumbnail:   thumb,
+			PHash:       sql.NullInt64{Int64: int64(perceptualHash(img)), Valid: true},
+		}, nil
+
+	case "image/webp":
+		// The standard library cannot decode WebP, so metadata chunks are removed
+		// from the RIFF container instead and no thumbnail is generated.
+		clean, err := stripWebPMetadata(raw)
+		if err != nil {
+			return nil, err
+		}
+		return &processedReceipt{Data: clean, ContentType: contentType}, nil
+
+	default: // application/pdf
+		return &processedReceipt{Data: raw, ContentType: contentType}, nil
+	}
+}
+
+func decodeReceiptImage(raw []byte) (image.Image, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("%w: corrupt image: %v", ErrUnsupportedReceiptType, err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxReceiptPixels {
+		return nil, fmt.Errorf("%w: image dimensions %dx%d", ErrReceiptTooLarge, cfg.Width, cfg.Height)
+	}
+	img, _, err := image.Decode(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("%w: corrupt image: %v", ErrUnsupportedReceiptType, err)
+	}
+	return img, nil
+}
+
+// makeThumbnail downsizes the image with a box filter and encodes it as JPEG.
+func makeThumbnail(src image.Image) ([]byte, error) {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	tw, th := w, h
+	if w > thumbnailMaxSide || h > thumbnailMaxSide {
+		if w >= h {
+			tw, th = thumbnailMaxSide, h*thumbnailMaxSide/w
+		} else {
+			tw, th = w*thumbnailMaxSide/h, thumbnailMaxSide
+		}
+	}
+	if tw < 1 {
+		tw = 1
+	}
+	if th < 1 {
+		th = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, tw, th))
+	for y := 0; y < th; y++ {
+		y0, y1 := b.Min.Y+y*h/th, b.Min.Y+(y+1)*h/th
+		if y1 <= y0 {
+			y1 = y0 + 1
+		}
+		for x := 0; x < tw; x++ {
+			x0, x1 := b.Min.X+x*w/tw, b.Min.X+(x+1)*w/tw
+			if x1 <= x0 {
+				x1 = x0 + 1
+			}
+			var r, g, bl, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, _ := src.At(sx, sy).RGBA()
+					r, g, bl, n = r+uint64(cr), g+uint64(cg), bl+uint64(cb), n+1
+				}
+			}
+			dst.Set(x, y, color.RGBA64{R: uint16(r / n), G: uint16(g / n), B: uint16(bl / n), A: 0xffff})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 80}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// stripWebPMetadata drops EXIF and XMP chunks from a WebP file and clears the
+// matching flags in the VP8X header.
+func stripWebPMetadata(raw []byte) ([]byte, error) {
+	if len(raw) < 12 || string(raw[0:4]) != "RIFF" || string(raw[8:12]) != "WEBP" {
+		return nil, fmt.Errorf("%w: malformed WebP", ErrUnsupportedReceiptType)
+	}
+
+	var out bytes.Buffer
+	out.Write(raw[0:12])
+	for pos := 12; pos < len(raw); {
+		if pos+8 > len(raw) {
+			return nil, fmt.Errorf("%w: truncated WebP chunk", ErrUnsupportedReceiptType)
+		}
+		fourCC := string(raw[pos : pos+4])
+		size := int(binary.LittleEndian.Uint32(raw[pos+4 : pos+8]))
+		end := pos + 8 + size + size%2 // chunks are padded to even sizes
+		if size < 0 || end > len(raw) {
+			return nil, fmt.Errorf("%w: truncated WebP chunk", ErrUnsupportedReceiptType)
+		}
+
+		switch fourCC {
+		case "EXIF", "XMP ":
+			// dropped
+		case "VP8X":
+			chunk := append([]byte(nil), raw[pos:end]...)
+			if len(chunk) > 8 {
+				chunk[8] &^= 0x08 | 0x04 // EXIF and XMP present flags
+			}
+			out.Write(chunk)
+		default:
+			out.Write(raw[pos:end])
+		}
+		pos = end
+	}
+
+	clean := out.Bytes()
+	binary.LittleEndian.PutUint32(clean[4:8], uint32(len(clean)-8))
+	return clean, nil
+}
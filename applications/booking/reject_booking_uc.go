@@ -3,29 +3,47 @@ package booking
 import (
 	"context"
 	"database/sql"
-	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
 	"supra/applications/auth"
+	"supra/applications/seat"
 	"supra/db"
 	"supra/logger"
 
 	"github.com/google/uuid"
 )
 
-// RejectBookingUC rejects a pending booking, updates status in DB, and notifies user via email.
-func RejectBookingUC(bookingID string, reason string) (*Booking, error) {
-	logger.Log.Info(fmt.Sprintf("[reject-booking-uc] Starting rejection for booking: %s", bookingID))
+// ErrInvalidRejectionCode is returned when an unknown rejection code is supplied.
+var ErrInvalidRejectionCode = errors.New("invalid rejection code")
+
+// ErrNotRejectable is returned for bookings that are no longer awaiting
+// verification: approved, confirmed, cancelled or finally rejected ones.
+var ErrNotRejectable = errors.New("booking cannot be rejected in its current status")
+
+// RejectBookingUC rejects a pending booking, stores the rejection code and reason,
+// and notifies the user via email with guidance on how to proceed.
+func RejectBookingUC(bookingID string, code string, reason string) (*Booking, error) {
+	logger.Log.Info(fmt.Sprintf("[reject-booking-uc] Starting rejection for booking: %s (code: %s)", bookingID, code))
 
 	// Step 1: Validate input
 	if strings.TrimSpace(bookingID) == "" {
 		return nil, fmt.Errorf("bookingID cannot be empty")
 	}
-	if strings.TrimSpace(reason) == "" {
-		reason = "No reason provided"
+	if strings.TrimSpace(code) == "" {
+		code = ReasonOther
+	}
+	rejection, ok := GetRejectionReason(code)
+	if !ok {
+		logger.Log.Warn(fmt.Sprintf("[reject-booking-uc] Unknown rejection code %q for booking %s", code, bookingID))
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRejectionCode, code)
+	}
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		reason = rejection.Message
 	}
 
 	id, err := uuid.Parse(bookingID)
@@ -45,20 +63,27 @@ func RejectBookingUC(bookingID string, reason string) (*Booking, error) {
 	// Step 3: Fetch booking details
 	var (
 		bk                Booking
-		receiptBytes      []byte
+		hasReceipt        bool
+		hasThumb          bool
 		participantIDsRaw []byte
+		previousCode      string
 	)
 	querySelect := `
 		SELECT booking_id, booking_email, booking_status, payment_details_id,
-		       receipt_image, seat_quantity, seat_id, concert_id, total_amount, seat_type,
-		       participant_ids, created_at, user_notes
+		       COALESCE(receipt_hash, ''), (receipt_key IS NOT NULL OR receipt_image IS NOT NULL),
+		       COALESCE(receipt_content_type, ''), (receipt_thumb_key IS NOT NULL),
+		       seat_quantity, seat_id, concert_id, total_amount, seat_type,
+		       participant_ids, created_at, user_notes, COALESCE(rejection_code, ''),
+		       COALESCE(contact_email, ''), seats_reserved, currency
 		FROM booking
 		WHERE booking_id = $1
+		FOR UPDATE
 	`
 	err = tx.QueryRow(querySelect, id).Scan(
 		&bk.BookingID, &bk.BookingEmail, &bk.BookingStatus, &bk.PaymentDetailsID,
-		&receiptBytes, &bk.SeatQuantity, &bk.SeatID, &bk.ConcertID, &bk.TotalAmount,
-		&bk.SeatType, &participantIDsRaw, &bk.CreatedAt, &bk.UserNotes,
+		&bk.ReceiptHash, &hasReceipt, &bk.ReceiptType, &hasThumb, &bk.SeatQuantity, &bk.SeatID, &bk.ConcertID, &bk.TotalAmount,
+		&bk.SeatType, &participantIDsRaw, &bk.CreatedAt, &bk.UserNotes, &previousCode,
+		&bk.ContactEmail, &bk.SeatsReserved, &bk.Currency,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -69,51 +94,88 @@ func RejectBookingUC(bookingID string, reason string) (*Booking, error) {
 		return nil, err
 	}
 
+	// Only bookings awaiting verification can be rejected. Anything else
+	// has either been paid for or already gave its seats back.
+	pending := bk.BookingStatus == VERIFYING || bk.BookingStatus == PENDING_VERIFICATION ||
+		(bk.BookingStatus == REJECTED && holdsSeats(REJECTED, previousCode))
+	if !pending {
+		logger.Log.Warn(fmt.Sprintf("[reject-booking-uc] Booking %s is %s; not rejecting", bookingID, bk.BookingStatus))
+		return nil, fmt.Errorf("%w: %s", ErrNotRejectable, bk.BookingStatus)
+	}
+
 	// Step 4: Deserialize participants if available
 	if len(participantIDsRaw) > 0 {
 		_ = json.Unmarshal(participantIDsRaw, &bk.ParticipantIDs)
 	}
-	bk.ReceiptImage = receiptBytes
+	bk.setReceiptLinks(hasReceipt, hasThumb)
 
-	// Step 5: Update booking → REJECTED
+	// Step 5: Update booking → REJECTED and persist the reason
 	queryUpdate := `
 		UPDATE booking
 		SET booking_status = 'REJECTED',
+		    rejection_code = $3,
+		    rejection_reason = $4,
 		    updated_at = $2
 		WHERE booking_id = $1
 	`
-	_, err = tx.Exec(queryUpdate, id, time.Now())
+	_, err = tx.Exec(queryUpdate, id, time.Now(), rejection.Code, reason)
 	if err != nil {
 		logger.Log.Error(fmt.Sprintf("[reject-booking-uc] Failed to update booking %s: %v", bookingID, err))
 		return nil, fmt.Errorf("failed to update booking status: %w", err)
 	}
 
+	// Step 5.5: A final rejection (no re-upload) gives the seats back to the
+	// category so waitlisted users can be offered them.
+	releaseSeats := !rejection.AllowReupload && bk.SeatsReserved
+	if releaseSeats {
+		if _, err := increaseSeatAvailabilityTx(tx, bk.SeatID, bk.SeatQuantity); err != nil {
+			logger.Log.Error(fmt.Sprintf("[reject-booking-uc] Seat release failed for %s: %v", bookingID, err))
+			return nil, fmt.Errorf("failed to release seats: %w", err)
+		}
+	}
+
 	// Step 6: Commit transaction
 	if err := tx.Commit(); err != nil {
 		logger.Log.Error(fmt.Sprintf("[reject-booking-uc] Commit failed for %s: %v", bookingID, err))
 		return nil, fmt.Errorf("commit failed: %w", err)
 	}
 
-	logger.Log.Info(fmt.Sprintf("[reject-booking-uc] Booking %s successfully marked as REJECTED.", bookingID))
+	bk.BookingStatus = REJECTED
+	bk.RejectionCode = rejection.Code
+	bk.RejectionReason = reason
+	bk.applyRejectionGuide()
 
-	// Step 7: Send rejection email
-	var base64Receipt string
-	if len(receiptBytes) > 0 {
-		base64Receipt = base64.StdEncoding.EncodeToString(receiptBytes)
+	logger.Log.Info(fmt.Sprintf("[reject-booking-uc] Booking %s successfully marked as REJECTED.", bookingID))
+	if releaseSeats {
+		go seat.ProcessWaitlist(bk.SeatID)
 	}
 
-	emailErr := auth.SendBookingVerificationMail(
-		bk.BookingEmail,
-		"REJECTED",
+	// Step 7: Send rejection email
+	emailErr := auth.SendBookingRejectionMail(
+		bk.NotifyEmail(),
 		bk.BookingID.String(),
-		base64Receipt,
-		reason, // Optional rejection reason
+		reason,
+		rejection.Hint,
+		rejection.AllowReupload,
 	)
 	if emailErr != nil {
 		logger.Log.Warn(fmt.Sprintf("[reject-booking-uc] Booking %s rejected, but email sending failed: %v", bookingID, emailErr))
 	} else {
-		logger.Log.Info(fmt.Sprintf("[reject-booking-uc] Rejection email sent to %s.", bk.BookingEmail))
+		logger.Log.Info(fmt.Sprintf("[reject-booking-uc] Rejection email sent to %s.", bk.NotifyEmail()))
 	}
 
 	return &bk, nil
 }
+
+// holdsSeats reports whether a booking in this state still counts against
+// its seat category. Re-uploadable rejections keep their seats.
+func holdsSeats(status, rejectionCode string) bool {
+	switch status {
+	case CANCELLED:
+		return false
+	case REJECTED:
+		r, ok := GetRejectionReason(rejectionCode)
+		return ok && r.AllowReupload
+	}
+	return true
+}
@@ -0,0 +1,73 @@
+package booking
+
+import "strings"
+
+// Structured rejection codes an admin can attach when rejecting a booking.
+const (
+	ReasonBlurryReceipt  = "BLURRY_RECEIPT"
+	ReasonAmountMismatch = "AMOUNT_MISMATCH"
+	ReasonDuplicate      = "DUPLICATE"
+	ReasonOther          = "OTHER"
+)
+
+// RejectionReason describes a rejection code and the guided re-upload flow
+// offered to the user when their booking is rejected with it.
+type RejectionReason struct {
+	Code          string `json:"code"`
+	Message       string `json:"message"`
+	Hint          string `json:"hint"`
+	AllowReupload bool   `json:"allowReupload"`
+}
+
+var rejectionReasons = map[string]RejectionReason{
+	ReasonBlurryReceipt: {
+		Code:          ReasonBlurryReceipt,
+		Message:       "The payment receipt was not readable.",
+		Hint:          "Upload a clear, uncropped screenshot that shows the amount, date and transaction reference.",
+		AllowReupload: true,
+	},
+	ReasonAmountMismatch: {
+		Code:          ReasonAmountMismatch,
+		Message:       "The amount on the receipt does not match the booking total.",
+		Hint:          "Pay the remaining balance and upload receipts covering the full booking amount.",
+		AllowReupload: true,
+	},
+	ReasonDuplicate: {
+		Code:          ReasonDuplicate,
+		Message:       "This receipt has already been used for another booking.",
+		Hint:          "Upload the receipt of the payment you made for this booking.",
+		AllowReupload: true,
+	},
+	ReasonOther: {
+		Code:          ReasonOther,
+		Message:       "The booking could not be verified.",
+		Hint:          "Please contact support for help with this booking.",
+		AllowReupload: false,
+	},
+}
+
+// GetRejectionReason returns the rejection reason for a code (case-insensitive).
+func GetRejectionReason(code string) (RejectionReason, bool) {
+	r, ok := rejectionReasons[strings.ToUpper(strings.TrimSpace(code))]
+	return r, ok
+}
+
+// GetAllRejectionReasons lists the supported rejection codes in a stable order.
+func GetAllRejectionReasons() []RejectionReason {
+	return []RejectionReason{
+		rejectionReasons[ReasonBlurryReceipt],
+		rejectionReasons[ReasonAmountMismatch],
+		rejectionReasons[ReasonDuplicate],
+		rejectionReasons[ReasonOther],
+	}
+}
+
+// applyRejectionGuide fills in the re-upload guidance for rejected bookings.
+func (bk *Booking) applyRejectionGuide() {
+	if bk.BookingStatus != REJECTED || bk.RejectionCode == "" {
+		return
+	}
+	if r, ok := GetRejectionReason(bk.RejectionCode); ok {
+		bk.Reupload = &r
+	}
+}
@@ -0,0 +1,109 @@
+package booking
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"supra/db"
+	"supra/logger"
+)
+
+// TaxSummary totals one tax (name and rate) across bookings.
+type TaxSummary struct {
+	Name     string  `json:"name"`
+	Rate     float64 `json:"rate"`
+	Base     float64 `json:"taxableBase"`
+	Amount   float64 `json:"amount"`
+	Bookings int     `json:"bookings"`
+}
+
+// TaxReport is the accounting summary of paid bookings in a period.
+type TaxReport struct {
+	From       *time.Time    `json:"from,omitempty"`
+	To         *time.Time    `json:"to,omitempty"`
+	ConcertID  string        `json:"concertID,omitempty"`
+	Currency   string        `json:"currency"`
+	Bookings   int           `json:"bookings"`
+	Unitemized int           `json:"unitemizedBookings"` // made before price breakdowns were recorded
+	Subtotal   float64       `json:"subtotal"`
+	Discounts  float64       `json:"discounts"`
+	Fees       float64       `json:"fees"`
+	Taxes      []*TaxSummary `json:"taxes"`
+	TaxTotal   float64       `json:"taxTotal"`
+	Total      float64       `json:"total"`
+}
+
+// GetTaxReportUC sums fees and taxes of approved/confirmed bookings created in
+// [from, to), optionally for one concert. Only bookings in the given currency
+// are counted, so amounts are never added across currencies.
+func GetTaxReportUC(concertID, currency string, from, to *time.Time) (*TaxReport, error) {
+	logger.Log.Info(fmt.Sprintf("[tax-report-uc] Building tax report (concert=%q currency=%s from=%v to=%v)", concertID, currency, from, to))
+
+	rows, err := db.DB.Query(`
+		SELECT total_amount, price_breakdown
+		FROM booking
+		WHERE booking_status IN ('APPROVED', 'CONFIRMED')
+		  AND ($1 = '' OR concert_id = $1)
+		  AND ($2::timestamptz IS NULL OR created_at >= $2)
+		  AND ($3::timestamptz IS NULL OR created_at < $3)
+		  AND currency = $4`, concertID, from, to, currency)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("[tax-report-uc] Query failed: %v", err))
+		return nil, fmt.Errorf("database query error: %w", err)
+	}
+	defer rows.Close()
+
+	report := &TaxReport{From: from, To: to, ConcertID: concertID, Currency: currency, Taxes: []*TaxSummary{}}
+	byTax := map[string]*TaxSummary{}
+	for rows.Next() {
+		var total float64
+		var raw []byte
+		if err := rows.Scan(&total, &raw); err != nil {
+			return nil, fmt.Errorf("error scanning booking row: %w", err)
+		}
+		report.Bookings++
+		report.Total += total
+
+		pb := priceBreakdownFrom(raw)
+		if pb == nil {
+			report.Unitemized++
+			report.Subtotal += total
+			continue
+		}
+		report.Subtotal += pb.Subtotal
+		report.Discounts += pb.Discount
+		report.Fees += pb.FeeTotal
+		for _, t := range pb.Taxes {
+			key := fmt.Sprintf("%s@%g", t.Name, t.Rate)
+			ts, ok := byTax[key]
+			if !ok {
+				ts = &TaxSummary{Name: t.Name, Rate: t.Rate}
+				byTax[key] = ts
+				report.Taxes = append(report.Taxes, ts)
+			}
+			ts.Base += t.Base
+			ts.Amount += t.Amount
+			ts.Bookings++
+			report.TaxTotal += t.Amount
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during row iteration: %w", err)
+	}
+
+	for _, ts := range report.Taxes {
+		ts.Base, ts.Amount = roundMoney(ts.Base), roundMoney(ts.Amount)
+	}
+	sort.Slice(report.Taxes, func(i, j int) bool {
+		if report.Taxes[i].Name != report.Taxes[j].Name {
+			return report.Taxes[i].Name < report.Taxes[j].Name
+		}
+		return report.Taxes[i].Rate < report.Taxes[j].Rate
+	})
+	report.Subtotal, report.Discounts, report.Fees = roundMoney(report.Subtotal), roundMoney(report.Discounts), roundMoney(report.Fees)
+	report.TaxTotal, report.Total = roundMoney(report.TaxTotal), roundMoney(report.Total)
+
+	logger.Log.Info(fmt.Sprintf("[tax-report-uc] %d bookings, tax total %.2f %s", report.Bookings, report.TaxTotal, currency))
+	return report, nil
+}
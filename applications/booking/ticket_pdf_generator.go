@@ -53,7 +53,7 @@ func GenerateTicketPDF(bookingID string) (*Booking, []byte, error) {
 		return nil, nil, fmt.Errorf("booking not found: %w", err)
 	}
 
-	if bk.BookingStatus != "APPROVED" {
+	if bk.BookingStatus != APPROVED && bk.BookingStatus != CONFIRMED {
 		return nil, nil, fmt.Errorf("booking status is not approved: %s", bk.BookingStatus)
 	}
 
@@ -61,7 +61,6 @@ func GenerateTicketPDF(bookingID string) (*Booking, []byte, error) {
 	if err != nil || st == nil {
 		return nil, nil, fmt.Errorf("seat not found: %w", err)
 	}
-	gelAmount := float64(bk.SeatQuantity) * st.PriceGel
 
 	// --- Fetch Payment Info ---
 	pd, _ := paymentdetails.GetPayment(bk.PaymentDetailsID)
@@ -94,6 +93,9 @@ func GenerateTicketPDF(bookingID string) (*Booking, []byte, error) {
 
 	// --- QR Code ---
 	qrURL := fmt.Sprintf("https://bkentertainments.vercel.app/concerts/participants/?bookingID=%s", bk.BookingID)
+	if bk.TicketCode != "" {
+		qrURL += "&code=" + bk.TicketCode
+	}
 	qrBytes, _ := qrcode.Encode(qrURL, qrcode.Medium, 512)
 	qrX, qrY, qrSize := 170.0, 17.0, 35.0
 	pdf.RegisterImageOptionsReader("qr",
@@ -157,7 +159,29 @@ func GenerateTicketPDF(bookingID string) (*Booking, []byte, error) {
 
 	pdf.SetX(leftX + 5)
 	pdf.Cell(60, 8, "Total Paid")
-	pdf.Cell(0, 8, fmt.Sprintf(": %.2f INR / %.2f GEL", bk.TotalAmount, gelAmount))
+	pdf.Cell(0, 8, fmt.Sprintf(": %.2f %s", bk.TotalAmount, bk.Currency))
+
+	// --- Itemized price (only when there is more than the ticket line) ---
+	if lines := bk.Price.Lines(); len(lines) > 1 {
+		pdf.Ln(14)
+		pdf.SetX(leftX)
+		pdf.SetFont("Helvetica", "B", 14)
+		pdf.SetTextColor(216, 27, 96)
+		pdf.Cell(0, 8, "PRICE BREAKDOWN")
+		pdf.Ln(8)
+
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.SetTextColor(230, 230, 230)
+		for _, l := range lines {
+			pdf.SetX(leftX + 5)
+			pdf.CellFormat(120, 5, l.Label, "", 0, "L", false, 0, "")
+			pdf.CellFormat(40, 5, fmt.Sprintf("%.2f INR", l.Amount), "", 1, "R", false, 0, "")
+		}
+		pdf.SetX(leftX + 5)
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.CellFormat(120, 6, "Total", "T", 0, "L", false, 0, "")
+		pdf.CellFormat(40, 6, fmt.Sprintf("%.2f INR", bk.TotalAmount), "T", 1, "R", false, 0, "")
+	}
 
 	// --- Logo ---
 	// if _, err := os.Stat(logoPath); err == nil {
@@ -0,0 +1,217 @@
+package booking
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"supra/applications/auth"
+	"supra/logger"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrTicketReissued     = errors.New("this ticket has been reissued and is no longer valid")
+	ErrNotTransferable    = errors.New("booking cannot be transferred")
+	ErrUnknownParticipant = errors.New("participant is not part of this booking")
+)
+
+func newTicketCode() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate ticket code: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// CheckTicketCode validates the code carried by a ticket QR. Bookings whose
+// tickets were never reissued have no code, so their original QR stays valid.
+func CheckTicketCode(bookingID, code string) error {
+	if _, err := uuid.Parse(bookingID); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidBookingID, err)
+	}
+	bk, err := GetBooking(bookingID)
+	if err != nil {
+		return err
+	}
+	if bk.TicketCode != "" && bk.TicketCode != code {
+		logger.Log.Warn(fmt.Sprintf("[transfer-tickets] Stale ticket QR scanned for booking %s", bookingID))
+		return ErrTicketReissued
+	}
+	return nil
+}
+
+// TransferTicketsTx moves tickets of a booking to toEmail and returns the
+// booking that now belongs to the recipient. With no participantIDs (or all
+// of them) the whole booking changes owner; otherwise those participants are
+// split into a new booking carrying their share of the amount paid. Every
+// affected booking gets a new ticket code, invalidating previously issued QRs.
+func TransferTicketsTx(tx *sql.Tx, bookingID, toEmail string, participantIDs []string) (*Booking, error) {
+	if _, err := tx.Exec(`SELECT 1 FROM booking WHERE booking_id = $1 FOR UPDATE`, bookingID); err != nil {
+		return nil, fmt.Errorf("failed to lock booking: %w", err)
+	}
+	bk, err := GetBookingTx(tx, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if bk.BookingStatus != APPROVED && bk.BookingStatus != CONFIRMED {
+		return nil, fmt.Errorf("%w: status %s", ErrNotTransferable, bk.BookingStatus)
+	}
+
+	moving, keeping, err := splitParticipants(bk.ParticipantIDs, participantIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	code, err := newTicketCode()
+	if err != nil {
+		return nil, err
+	}
+
+	// Whole booking: the owner changes, the booking stays.
+	if len(keeping) == 0 {
+		if err := tx.QueryRow(`
+			UPDATE booking
+			SET booking_email = $2, ticket_code = $3, contact_email = NULL, user_id = (SELECT user_id FROM users WHERE LOWER(email) = LOWER($2))
+			WHERE booking_id = $1
+			RETURNING COALESCE(user_id::text, '')`,
+			bk.BookingID, toEmail, code).Scan(&bk.UserID); err != nil {
+			return nil, fmt.Errorf("failed to transfer booking: %w", err)
+		}
+		if err := recordEventTx(tx, bk.BookingID, toEmail, EventTransferredOut, map[string]interface{}{
+			"from": bk.BookingEmail, "to": toEmail, "quantity": bk.SeatQuantity,
+		}); err != nil {
+			return nil, err
+		}
+		bk.BookingEmail, bk.TicketCode, bk.ContactEmail = toEmail, code, ""
+		logger.Log.Info(fmt.Sprintf("[transfer-tickets] Booking %s transferred in full to %s", bk.BookingID, toEmail))
+		return bk, nil
+	}
+
+	// Partial: the moving participants become a booking of their own.
+	if len(moving) >= bk.SeatQuantity {
+		return nil, fmt.Errorf("%w: booking has %d seats for %d participants", ErrNotTransferable, bk.SeatQuantity, len(bk.ParticipantIDs))
+	}
+	share := roundMoney(bk.TotalAmount * float64(len(moving)) / float64(bk.SeatQuantity))
+
+	// Itemized bookings split their breakdown so invoices and the tax report
+	// count every ticket once, on the booking that now holds it.
+	var movedPrice, keptPrice *PriceBreakdown
+	var movedPriceJSON, keptPriceJSON []byte
+	if bk.Price != nil && bk.Price.Quantity == bk.SeatQuantity {
+		movedPrice, keptPrice = bk.Price.split(len(moving))
+		share = movedPrice.Total
+		movedPriceJSON, _ = json.Marshal(movedPrice)
+		keptPriceJSON, _ = json.Marshal(keptPrice)
+	}
+
+	keepCode, err := newTicketCode()
+	if err != nil {
+		return nil, err
+	}
+	keepingJSON, _ := json.Marshal(keeping)
+	if _, err := tx.Exec(`
+		UPDATE booking
+		SET seat_quantity = seat_quantity - $2, total_amount = total_amount - $3, participant_ids = $4, ticket_code = $5,
+		    price_breakdown = COALESCE($6::jsonb, price_breakdown)
+		WHERE booking_id = $1`, bk.BookingID, len(moving), share, keepingJSON, keepCode, keptPriceJSON); err != nil {
+		return nil, fmt.Errorf("failed to update original booking: %w", err)
+	}
+
+	childID := uuid.New()
+	child := &Booking{
+		BookingID:        childID,
+		BookingEmail:     toEmail,
+		BookingStatus:    bk.BookingStatus,
+		PaymentDetailsID: bk.PaymentDetailsID,
+		SeatQuantity:     len(moving),
+		ConcertID:        bk.ConcertID,
+		SeatID:           bk.SeatID,
+		SeatType:         bk.SeatType,
+		TotalAmount:      share,
+		Currency:         bk.Currency,
+		ParticipantIDs:   moving,
+		UserNotes:        fmt.Sprintf("Transferred from booking %s", bk.BookingID),
+		TransferredFrom:  bk.BookingID.String(),
+		TicketCode:       code,
+		PaymentReference: paymentReference(childID),
+		Price:            movedPrice,
+	}
+	movingJSON, _ := json.Marshal(moving)
+	err = tx.QueryRow(`
+		INSERT INTO booking (
+			booking_id, booking_email, booking_status, payment_details_id,
+			seat_quantity, seat_id, concert_id, total_amount, seat_type,
+			participant_ids, created_at, user_notes, payment_reference, transferred_from, ticket_code, user_id,
+			seats_reserved, price_breakdown, currency
+		)
+		SELECT $2, $3, booking_status, payment_details_id,
+		       $4, seat_id, concert_id, $5, seat_type,
+		       $6, now(), $7, $9, booking_id, $8,
+		       (SELECT user_id FROM users WHERE LOWER(email) = LOWER($3)),
+		       seats_reserved, $10::jsonb, currency
+		FROM booking WHERE booking_id = $1
+		RETURNING created_at, COALESCE(user_id::text, '')`,
+		bk.BookingID, child.BookingID, toEmail, child.SeatQuantity, share, movingJSON, child.UserNotes, code, child.PaymentReference,
+		movedPriceJSON,
+	).Scan(&child.CreatedAt, &child.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transferred booking: %w", err)
+	}
+
+	if err := recordEventTx(tx, bk.BookingID, toEmail, EventTransferredOut, map[string]interface{}{
+		"from": bk.BookingEmail, "to": toEmail, "quantity": len(moving), "participantIDs": moving, "newBookingID": childID,
+	}); err != nil {
+		return nil, err
+	}
+	if err := recordEventTx(tx, childID, toEmail, EventTransferredIn, map[string]interface{}{
+		"from": bk.BookingEmail, "fromBookingID": bk.BookingID, "quantity": len(moving),
+	}); err != nil {
+		return nil, err
+	}
+
+	logger.Log.Info(fmt.Sprintf("[transfer-tickets] %d ticket(s) of booking %s moved to %s as booking %s", len(moving), bk.BookingID, toEmail, child.BookingID))
+	return child, nil
+}
+
+// splitParticipants partitions a booking's participants into the ones being
+// transferred and the ones staying. An empty selection transfers everyone.
+func splitParticipants(all, selected []string) (moving, keeping []string, err error) {
+	if len(selected) == 0 {
+		return all, nil, nil
+	}
+	pick := make(map[string]bool, len(selected))
+	for _, id := range selected {
+		pick[strings.TrimSpace(id)] = true
+	}
+	for _, id := range all {
+		if pick[id] {
+			moving = append(moving, id)
+			delete(pick, id)
+		} else {
+			keeping = append(keeping, id)
+		}
+	}
+	if len(pick) > 0 {
+		return nil, nil, ErrUnknownParticipant
+	}
+	return moving, keeping, nil
+}
+
+// SendReissuedTicket mails the current eTicket of a booking after a transfer.
+// note explains to the recipient why they are receiving it.
+func SendReissuedTicket(bookingID, note string) {
+	bk, pdfBytes, err := GenerateTicketPDF(bookingID)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("[transfer-tickets] ❌ Failed to regenerate ticket for %s: %v", bookingID, err))
+		return
+	}
+	if err := auth.SendReissuedTicketMail(bk.NotifyEmail(), bk.BookingID.String(), bk.SeatType, bk.SeatQuantity, note, pdfBytes); err != nil {
+		logger.Log.Error(fmt.Sprintf("[transfer-tickets] ❌ Failed to send reissued ticket for %s: %v", bookingID, err))
+	}
+}
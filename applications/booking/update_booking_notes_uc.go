@@ -9,6 +9,7 @@ import (
 
 	"supra/db"
 	"supra/logger"
+	"supra/validation"
 
 	"github.com/google/uuid"
 )
@@ -19,13 +20,16 @@ func UpdateBookingNotesUC(bookingID string, payload []byte) (*Booking, error) {
 
 	// Step 1️⃣ Parse request payload
 	type notesPayload struct {
-		UserNotes string `json:"userNotes" validate:"required"`
+		UserNotes string `json:"userNotes" validate:"required,max=2000"`
 	}
 	var p notesPayload
 	if err := json.Unmarshal(payload, &p); err != nil {
 		logger.Log.Error(fmt.Sprintf("[update-booking-notes-uc] ❌ Failed to unmarshal payload for %s: %v", bookingID, err))
 		return nil, fmt.Errorf("invalid payload: %w", err)
 	}
+	if err := validation.Struct(&p); err != nil {
+		return nil, err
+	}
 
 	// Step 2️⃣ Validate booking ID
 	id, err := uuid.Parse(bookingID)
@@ -36,9 +40,6 @@ func UpdateBookingNotesUC(bookingID string, payload []byte) (*Booking, error) {
 
 	// Step 3️⃣ Sanitize user notes
 	note := strings.TrimSpace(p.UserNotes)
-	if len(note) > 2000 {
-		return nil, fmt.Errorf("user notes too long (max 2000 characters)")
-	}
 
 	// Step 4️⃣ Start DB transaction
 	tx, err := db.DB.BeginTx(context.Background(), nil)
@@ -57,7 +58,7 @@ func UpdateBookingNotesUC(bookingID string, payload []byte) (*Booking, error) {
 			booking_id = $1
 			AND booking_status IS DISTINCT FROM 'APPROVED'
 		RETURNING booking_id, booking_email, booking_status, payment_details_id,
-		          seat_quantity, seat_id, total_amount, seat_type,
+		          seat_quantity, seat_id, total_amount, currency, seat_type,
 		          participant_ids, created_at, user_notes;
 	`
 
@@ -70,7 +71,7 @@ func UpdateBookingNotesUC(bookingID string, payload []byte) (*Booking, error) {
 	row := tx.QueryRow(query, id, note)
 	if err := row.Scan(
 		&idUUID, &bk.BookingEmail, &bk.BookingStatus, &bk.PaymentDetailsID,
-		&bk.SeatQuantity, &bk.SeatID, &bk.TotalAmount, &bk.SeatType,
+		&bk.SeatQuantity, &bk.SeatID, &bk.TotalAmount, &bk.Currency, &bk.SeatType,
 		&participantIDsRaw, &bk.CreatedAt, &bk.UserNotes,
 	); err != nil {
 		if err == sql.ErrNoRows {
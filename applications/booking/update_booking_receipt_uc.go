@@ -5,17 +5,22 @@ import (
 	"database/sql"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
-	"strings"
 
 	"supra/applications/auth"
 	"supra/db"
 	"supra/logger"
+	"supra/validation"
 
 	"github.com/google/uuid"
 )
 
+// ErrReuploadNotAllowed is returned when a booking was rejected with a code that
+// does not offer a receipt re-upload.
+var ErrReuploadNotAllowed = errors.New("receipt re-upload not allowed for this booking")
+
 // UpdateBookingReceiptUC updates only the receipt image (not notes) if booking is not approved.
 func UpdateBookingReceiptUC(bookingID string, payload []byte) (*Booking, error) {
 	logger.Log.Info(fmt.Sprintf("[update-booking-receipt-uc] 🚀 Starting receipt re-upload for booking %s", bookingID))
@@ -29,27 +34,44 @@ func UpdateBookingReceiptUC(bookingID string, payload []byte) (*Booking, error)
 		logger.Log.Error(fmt.Sprintf("[update-booking-receipt-uc] ❌ Failed to unmarshal payload for %s: %v", bookingID, err))
 		return nil, fmt.Errorf("invalid payload: %w", err)
 	}
+	if err := validation.Struct(&p); err != nil {
+		return nil, err
+	}
 
-	if strings.TrimSpace(p.ReceiptImage) == "" {
-		return nil, fmt.Errorf("receiptImage cannot be empty")
+	// Step 2️⃣ Decode Base64 → binary
+	decodedBytes, err := base64.StdEncoding.DecodeString(p.ReceiptImage)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("[update-booking-receipt-uc] ❌ Invalid base64 image for %s: %v", bookingID, err))
+		return nil, fmt.Errorf("invalid base64 image: %w", err)
 	}
 
-	// Step 2️⃣ Validate booking ID
+	return replaceBookingReceipt(bookingID, decodedBytes)
+}
+
+// UploadBookingReceiptUC replaces the receipt with a file sent as multipart/form-data.
+func UploadBookingReceiptUC(bookingID string, receipt []byte) (*Booking, error) {
+	logger.Log.Info(fmt.Sprintf("[update-booking-receipt-uc] 🚀 Starting receipt file upload for booking %s", bookingID))
+	return replaceBookingReceipt(bookingID, receipt)
+}
+
+func replaceBookingReceipt(bookingID string, receipt []byte) (*Booking, error) {
+	// Step 3️⃣ Validate booking ID
 	id, err := uuid.Parse(bookingID)
 	if err != nil {
 		logger.Log.Warn(fmt.Sprintf("[update-booking-receipt-uc] ⚠️ Invalid booking ID format: %s", bookingID))
 		return nil, fmt.Errorf("invalid booking ID: %w", err)
 	}
 
-	// Step 3️⃣ Decode Base64 → binary
-	decodedBytes, err := base64.StdEncoding.DecodeString(p.ReceiptImage)
+	// Step 3.5️⃣ Validate and sanitize the file
+	rc, err := processReceipt(receipt)
 	if err != nil {
-		logger.Log.Error(fmt.Sprintf("[update-booking-receipt-uc] ❌ Invalid base64 image for %s: %v", bookingID, err))
-		return nil, fmt.Errorf("invalid base64 image: %w", err)
+		logger.Log.Warn(fmt.Sprintf("[update-booking-receipt-uc] ⚠️ Receipt rejected for %s: %v", bookingID, err))
+		return nil, err
 	}
+	ctx := context.Background()
 
 	// Step 4️⃣ Start DB transaction
-	tx, err := db.DB.BeginTx(context.Background(), nil)
+	tx, err := db.DB.BeginTx(ctx, nil)
 	if err != nil {
 		logger.Log.Error(fmt.Sprintf("[update-booking-receipt-uc] ❌ Failed to start transaction: %v", err))
 		return nil, fmt.Errorf("transaction start failed: %w", err)
@@ -58,32 +80,96 @@ func UpdateBookingReceiptUC(bookingID string, payload []byte) (*Booking, error)
 
 	logger.Log.Info(fmt.Sprintf("[update-booking-receipt-uc] 🧾 Transaction started for booking %s", bookingID))
 
+	// Step 4.5️⃣ Check the booking before touching the blob store. Approved
+	// bookings keep their receipt, and rejected bookings may only be
+	// re-uploaded when the rejection code allows it.
+	var currentStatus, rejectionCode, oldKey, oldThumbKey string
+	err = tx.QueryRow(`
+		SELECT booking_status, COALESCE(rejection_code, ''), COALESCE(receipt_key, ''), COALESCE(receipt_thumb_key, '')
+		FROM booking
+		WHERE booking_id = $1
+		FOR UPDATE`, id).Scan(&currentStatus, &rejectionCode, &oldKey, &oldThumbKey)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			logger.Log.Warn(fmt.Sprintf("[update-booking-receipt-uc] ⚠️ Booking %s not found", bookingID))
+			return nil, fmt.Errorf("booking not found or already approved")
+		}
+		logger.Log.Error(fmt.Sprintf("[update-booking-receipt-uc] Database error: %v", err))
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	if currentStatus == APPROVED || currentStatus == CONFIRMED {
+		logger.Log.Warn(fmt.Sprintf("[update-booking-receipt-uc] ⚠️ Booking %s already %s", bookingID, currentStatus))
+		return nil, fmt.Errorf("booking not found or already approved")
+	}
+	if currentStatus == CANCELLED {
+		// Cancelled bookings (including refunded ones) already gave their
+		// seats back; a new receipt must not revive them.
+		logger.Log.Warn(fmt.Sprintf("[update-booking-receipt-uc] ⚠️ Re-upload blocked for cancelled booking %s", bookingID))
+		return nil, fmt.Errorf("%w: booking is cancelled", ErrReuploadNotAllowed)
+	}
+	if currentStatus == REJECTED && rejectionCode != "" {
+		if r, ok := GetRejectionReason(rejectionCode); ok && !r.AllowReupload {
+			logger.Log.Warn(fmt.Sprintf("[update-booking-receipt-uc] ⚠️ Re-upload blocked for %s (rejection code %s)", bookingID, rejectionCode))
+			return nil, fmt.Errorf("%w: %s", ErrReuploadNotAllowed, r.Hint)
+		}
+	}
+
+	// Step 4.7️⃣ Store the file (keyed by content hash). A byte-identical
+	// re-upload maps to the live blobs, which must survive a failed update.
+	sr, err := storeReceipt(ctx, id, rc)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("[update-booking-receipt-uc] ❌ Receipt upload failed for %s: %v", bookingID, err))
+		return nil, err
+	}
+	committed := false
+	defer func() {
+		if committed {
+			return
+		}
+		if sr.Key != oldKey {
+			discardReceipt(ctx, sr.Key)
+		}
+		if sr.ThumbKey != oldThumbKey {
+			discardReceipt(ctx, sr.ThumbKey)
+		}
+	}()
+
 	// Step 5️⃣ Update DB record safely (no user_notes touched)
 	query := `
 		UPDATE booking
 		SET
-			receipt_image   = $2,
-			booking_status  = 'PENDING_VERIFICATION'
+			receipt_key      = $2,
+			receipt_hash     = $3,
+			receipt_content_type = $4,
+			receipt_thumb_key    = NULLIF($5, ''),
+			receipt_phash        = $6,
+			ocr_status           = NULL,
+			ocr_amount           = NULL,
+			ocr_date             = NULL,
+			ocr_reference        = NULL,
+			receipt_image    = NULL,
+			booking_status   = 'PENDING_VERIFICATION',
+			rejection_code   = NULL,
+			rejection_reason = NULL
 		WHERE
 			booking_id = $1
-			AND booking_status IS DISTINCT FROM 'APPROVED'
+			AND booking_status NOT IN ('APPROVED', 'CONFIRMED', 'CANCELLED')
 		RETURNING booking_id, booking_email, booking_status, payment_details_id,
-		          receipt_image, seat_quantity, seat_id, total_amount, seat_type,
-		          participant_ids, created_at, user_notes;
+		          seat_quantity, seat_id, total_amount, seat_type,
+		          participant_ids, created_at, user_notes, currency;
 	`
 
 	var (
 		bk                Booking
-		receiptBytes      []byte
 		participantIDsRaw []byte
 		idUUID            uuid.UUID
 	)
 
-	row := tx.QueryRow(query, id, decodedBytes)
+	row := tx.QueryRow(query, id, sr.Key, sr.Hash, sr.ContentType, sr.ThumbKey, sr.PHash)
 	if err := row.Scan(
 		&idUUID, &bk.BookingEmail, &bk.BookingStatus, &bk.PaymentDetailsID,
-		&receiptBytes, &bk.SeatQuantity, &bk.SeatID, &bk.TotalAmount,
-		&bk.SeatType, &participantIDsRaw, &bk.CreatedAt, &bk.UserNotes,
+		&bk.SeatQuantity, &bk.SeatID, &bk.TotalAmount,
+		&bk.SeatType, &participantIDsRaw, &bk.CreatedAt, &bk.UserNotes, &bk.Currency,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			logger.Log.Warn(fmt.Sprintf("[update-booking-receipt-uc] ⚠️ Booking %s not found or already approved", bookingID))
@@ -100,14 +186,34 @@ func UpdateBookingReceiptUC(bookingID string, payload []byte) (*Booking, error)
 		}
 	}
 	bk.BookingID = idUUID
-	bk.ReceiptImage = receiptBytes
+	bk.ReceiptHash = sr.Hash
+	bk.ReceiptType = sr.ContentType
+	bk.ReceiptURL = receiptURL(idUUID)
+	if sr.ThumbKey != "" {
+		bk.ThumbnailURL = receiptThumbnailURL(idUUID)
+	}
+
+	// Step 6.5️⃣ Flag receipts already used by another booking
+	bk.DuplicateReceipt, err = flagDuplicateReceiptTx(tx, idUUID, sr)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("[update-booking-receipt-uc] Duplicate check failed for %s: %v", bookingID, err))
+		return nil, fmt.Errorf("duplicate receipt check failed: %w", err)
+	}
 
 	// Step 7️⃣ Commit transaction
 	if err := tx.Commit(); err != nil {
 		logger.Log.Error(fmt.Sprintf("[update-booking-receipt-uc] ❌ Commit failed: %v", err))
 		return nil, fmt.Errorf("commit failed: %w", err)
 	}
+	committed = true
+	if oldKey != "" && oldKey != sr.Key {
+		discardReceipt(ctx, oldKey)
+	}
+	if oldThumbKey != "" && oldThumbKey != sr.ThumbKey {
+		discardReceipt(ctx, oldThumbKey)
+	}
 	logger.Log.Info(fmt.Sprintf("[update-booking-receipt-uc] ✅ DB updated and committed for booking %s", bookingID))
+	queueReceiptOCR(bk.BookingID, sr, rc.Data)
 
 	// Step 8️⃣ Notify admin
 	adminEmail := os.Getenv("ADMIN_EMAIL")
@@ -116,7 +222,7 @@ func UpdateBookingReceiptUC(bookingID string, payload []byte) (*Booking, error)
 		return &bk, nil
 	}
 
-	encodedReceipt := base64.StdEncoding.EncodeToString(bk.ReceiptImage)
+	encodedReceipt := base64.StdEncoding.EncodeToString(rc.Data)
 
 	go func() {
 		err := auth.SendReceiptReuploadNotification(
@@ -125,8 +231,11 @@ func UpdateBookingReceiptUC(bookingID string, payload []byte) (*Booking, error)
 			bk.BookingEmail,
 			bk.SeatType,
 			bk.TotalAmount,
+			bk.Currency,
 			encodedReceipt,
+			rc.ContentType,
 			bk.UserNotes,
+			bk.DuplicateReceipt.String(),
 		)
 		if err != nil {
 			logger.Log.Error(fmt.Sprintf("[update-booking-receipt-uc] ❌ Failed to send admin notification: %v", err))
@@ -3,12 +3,14 @@ package booking
 import (
 	"context"
 	"database/sql"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"strings"
 
 	"supra/db"     // Using the correct module path
 	"supra/logger" // ⬅️ Assuming this import path
+	"supra/validation"
 
 	"github.com/google/uuid"
 )
@@ -17,11 +19,11 @@ import (
 
 // UpdateBookingParams defines fields that can be optionally updated for a booking record.
 type UpdateBookingParams struct {
-	BookingEmail     string `json:"bookingEmail,omitempty"`
+	BookingEmail     string `json:"bookingEmail,omitempty" validate:"email"`
 	BookingStatus    string `json:"bookingStatus,omitempty"`
-	PaymentDetailsID string `json:"paymentDetailsID,omitempty"`
+	PaymentDetailsID string `json:"paymentDetailsID,omitempty" validate:"uuid"`
 	ReceiptImage     string `json:"receiptImage,omitempty"` // Base64 string
-	UserNotes        string `json:"userNotes"`
+	UserNotes        string `json:"userNotes" validate:"max=2000"`
 	// SeatQuantity, SeatID, TotalAmount, and ParticipantIDs are typically immutable or handled by separate UCs.
 }
 
@@ -34,6 +36,10 @@ func UpdateBooking(bookingID string, payload []byte) (*Booking, error) {
 		logger.Log.Error(fmt.Sprintf("[update-booking-uc] Unmarshal failed for %s: %v", bookingID, err))
 		return nil, fmt.Errorf("failed to unmarshal payload: %w", err)
 	}
+	if err := validation.Struct(&p); err != nil {
+		logger.Log.Warn(fmt.Sprintf("[update-booking-uc] Invalid update request for %s: %v", bookingID, err))
+		return nil, err
+	}
 
 	// Start a transaction
 	tx, err := db.DB.BeginTx(context.Background(), nil)
@@ -57,7 +63,8 @@ func UpdateBooking(bookingID string, payload []byte) (*Booking, error) {
 	argCounter := 2           // SQL placeholders start at $2
 
 	if p.BookingEmail != "" {
-		sets = append(sets, fmt.Sprintf("booking_email = $%d", argCounter))
+		// The owner follows the email: link to that address's account, if any.
+		sets = append(sets, fmt.Sprintf("booking_email = $%d, user_id = (SELECT user_id FROM users WHERE LOWER(email) = LOWER($%d))", argCounter, argCounter))
 		args = append(args, p.BookingEmail)
 		argCounter++
 	}
@@ -76,12 +83,37 @@ func UpdateBooking(bookingID string, payload []byte) (*Booking, error) {
 		args = append(args, p.PaymentDetailsID)
 		argCounter++
 	}
+	var (
+		rc *processedReceipt
+		sr *storedReceipt
+	)
 	if p.ReceiptImage != "" {
-		// Convert ReceiptImage string (Base64) to []byte
-		receiptBytes := []byte(p.ReceiptImage)
-		sets = append(sets, fmt.Sprintf("receipt_image = $%d", argCounter))
-		args = append(args, receiptBytes)
-		argCounter++
+		// Decode the Base64 receipt and move it to the blob store
+		receiptBytes, err := base64.StdEncoding.DecodeString(p.ReceiptImage)
+		if err != nil {
+			logger.Log.Warn(fmt.Sprintf("[update-booking-uc] Update failed for %s: Invalid base64 receipt.", bookingID))
+			return nil, fmt.Errorf("invalid base64 image: %w", err)
+		}
+		rc, err = processReceipt(receiptBytes)
+		if err != nil {
+			logger.Log.Warn(fmt.Sprintf("[update-booking-uc] Update failed for %s: %v", bookingID, err))
+			return nil, err
+		}
+		sr, err = storeReceipt(context.Background(), id, rc)
+		if err != nil {
+			logger.Log.Error(fmt.Sprintf("[update-booking-uc] Receipt upload failed for %s: %v", bookingID, err))
+			return nil, err
+		}
+		sets = append(sets,
+			fmt.Sprintf("receipt_key = $%d", argCounter),
+			fmt.Sprintf("receipt_hash = $%d", argCounter+1),
+			fmt.Sprintf("receipt_content_type = $%d", argCounter+2),
+			fmt.Sprintf("receipt_thumb_key = NULLIF($%d, '')", argCounter+3),
+			fmt.Sprintf("receipt_phash = $%d", argCounter+4),
+			"receipt_image = NULL",
+			"ocr_status = NULL", "ocr_amount = NULL", "ocr_date = NULL", "ocr_reference = NULL")
+		args = append(args, sr.Key, sr.Hash, sr.ContentType, sr.ThumbKey, sr.PHash)
+		argCounter += 5
 	}
 	if p.UserNotes != "" {
 		sets = append(sets, fmt.Sprintf("user_notes = $%d", argCounter))
@@ -101,15 +133,17 @@ func UpdateBooking(bookingID string, payload []byte) (*Booking, error) {
 		SET %s
 		WHERE booking_id = $1
 		RETURNING booking_id, booking_email, booking_status, payment_details_id, 
-		           receipt_image, user_notes, seat_quantity, seat_id, total_amount, seat_type, 
-		           participant_ids, created_at`,
+		           COALESCE(receipt_hash, ''), (receipt_key IS NOT NULL OR receipt_image IS NOT NULL),
+		           COALESCE(receipt_content_type, ''), (receipt_thumb_key IS NOT NULL),
+		           user_notes, seat_quantity, seat_id, total_amount, currency, seat_type,
+		           participant_ids, created_at`,
 		strings.Join(sets, ", "))
 
 	logger.Log.Info(fmt.Sprintf("[update-booking-uc] Executing UPDATE for %s with %d fields modified.", bookingID, len(sets)))
 
 	// 4. Execute and scan the returned row
 	bk := &Booking{}
-	var receiptImage []byte
+	var hasReceipt, hasThumb bool
 	var participantIDsJSON []byte
 	var bookingIDUUID uuid.UUID
 
@@ -118,8 +152,8 @@ func UpdateBooking(bookingID string, payload []byte) (*Booking, error) {
 
 	if err := row.Scan(
 		&bookingIDUUID, &bk.BookingEmail, &bk.BookingStatus, &bk.PaymentDetailsID,
-		&receiptImage, &bk.UserNotes, &bk.SeatQuantity, &bk.SeatID, &bk.TotalAmount,
-		&participantIDsJSON, &bk.CreatedAt, &bk.SeatType,
+		&bk.ReceiptHash, &hasReceipt, &bk.ReceiptType, &hasThumb, &bk.UserNotes, &bk.SeatQuantity, &bk.SeatID, &bk.TotalAmount, &bk.Currency,
+		&bk.SeatType, &participantIDsJSON, &bk.CreatedAt,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			tx.Rollback()
@@ -133,7 +167,7 @@ func UpdateBooking(bookingID string, payload []byte) (*Booking, error) {
 
 	// Assign mapped fields
 	bk.BookingID = bookingIDUUID
-	bk.ReceiptImage = receiptImage
+	bk.setReceiptLinks(hasReceipt, hasThumb)
 
 	// Convert back from DB formats
 	if err := json.Unmarshal(participantIDsJSON, &bk.ParticipantIDs); err != nil {
@@ -142,12 +176,23 @@ func UpdateBooking(bookingID string, payload []byte) (*Booking, error) {
 		return nil, fmt.Errorf("failed to unmarshal participant IDs: %w", err)
 	}
 
+	if sr != nil {
+		if bk.DuplicateReceipt, err = flagDuplicateReceiptTx(tx, bk.BookingID, sr); err != nil {
+			logger.Log.Error(fmt.Sprintf("[update-booking-uc] Duplicate check failed for %s: %v", bookingID, err))
+			return nil, err
+		}
+	}
+
 	// 5. Commit the transaction
 	if err := tx.Commit(); err != nil {
 		logger.Log.Error(fmt.Sprintf("[update-booking-uc] Failed to commit transaction for %s: %v", bookingID, err))
 		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
+	if sr != nil {
+		queueReceiptOCR(bk.BookingID, sr, rc.Data)
+	}
+
 	logger.Log.Info(fmt.Sprintf("[update-booking-uc] Booking %s updated successfully. New Status: %s.", bookingID, bk.BookingStatus))
 	return bk, nil
 }
@@ -0,0 +1,58 @@
+package booking
+
+import (
+	"fmt"
+	"strings"
+
+	"supra/applications/paymentdetails"
+	"supra/concert/domain"
+	"supra/logger"
+
+	"github.com/google/uuid"
+)
+
+// paymentReference derives the short reference sent as the UPI transaction
+// reference and quoted in bank transfers; it is stored on the booking so
+// payments can be matched to it. The UPI note carries the booking ID.
+func paymentReference(bookingID uuid.UUID) string {
+	return "BK" + strings.ToUpper(strings.ReplaceAll(bookingID.String(), "-", "")[:12])
+}
+
+// attachUPIIntent adds a UPI link and QR for the booking amount when the booking
+// is still awaiting payment and its payment method is UPI. UPI only settles
+// in INR, so bookings priced in another currency get none.
+func (bk *Booking) attachUPIIntent() {
+	switch bk.BookingStatus {
+	case VERIFYING, PENDING_VERIFICATION, REJECTED:
+	default:
+		return
+	}
+	if bk.PaymentDetailsID == "" || (bk.Currency != "" && bk.Currency != domain.CurrencyINR) {
+		return
+	}
+
+	pd, err := paymentdetails.GetPayment(bk.PaymentDetailsID)
+	if err != nil || !pd.IsUPI() {
+		return
+	}
+	if bk.PaymentReference == "" {
+		bk.PaymentReference = paymentReference(bk.BookingID)
+	}
+
+	intent, err := paymentdetails.BuildUPIIntent(pd, bk.TotalAmount, bk.PaymentReference, fmt.Sprintf("Booking %s", bk.BookingID))
+	if err != nil {
+		logger.Log.Warn(fmt.Sprintf("[upi-payment] Could not build UPI intent for %s: %v", bk.BookingID, err))
+		return
+	}
+	bk.UPI = intent
+}
+
+// GetBookingDetailsUC returns a booking together with its payment instructions.
+func GetBookingDetailsUC(bookingID string) (*Booking, error) {
+	bk, err := GetBooking(bookingID)
+	if err != nil {
+		return nil, err
+	}
+	bk.attachUPIIntent()
+	return bk, nil
+}
@@ -0,0 +1,137 @@
+package invoice
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Party is the seller or buyer printed on an invoice.
+type Party struct {
+	Name    string `json:"name,omitempty"`
+	Address string `json:"address,omitempty"`
+	TaxID   string `json:"taxID,omitempty"` // GSTIN / VAT number
+	Email   string `json:"email,omitempty"`
+}
+
+// Line is one invoice row. Discounts and credit notes use negative amounts.
+type Line struct {
+	Description string  `json:"description"`
+	Quantity    int     `json:"quantity,omitempty"`
+	UnitPrice   float64 `json:"unitPrice,omitempty"`
+	Amount      float64 `json:"amount"`
+}
+
+type Invoice struct {
+	DocumentID    string    `json:"documentID"`
+	Number        string    `json:"number"`
+	Kind          string    `json:"kind"`
+	BookingID     string    `json:"bookingID"`
+	RefundID      string    `json:"refundID,omitempty"`
+	RelatedNumber string    `json:"relatedNumber,omitempty"` // credit note: the invoice it corrects
+	IssuedAt      time.Time `json:"issuedAt"`
+	Seller        Party     `json:"seller"`
+	Buyer         Party     `json:"buyer"`
+	Lines         []Line    `json:"lines"`
+	Total         float64   `json:"total"`
+	Currency      string    `json:"currency"`
+	Note          string    `json:"note,omitempty"`
+}
+
+const (
+	KindInvoice    = "INVOICE"
+	KindCreditNote = "CREDIT_NOTE"
+)
+
+var (
+	ErrInvoiceNotFound = errors.New("invoice not found")
+	ErrNoInvoice       = errors.New("booking has no invoice to credit")
+)
+
+// Organizer is the code of the selling organizer; each organizer has its own
+// numbering series. Configured with INVOICE_ORGANIZER (default BK).
+func Organizer() string {
+	if o := os.Getenv("INVOICE_ORGANIZER"); o != "" {
+		return o
+	}
+	return "BK"
+}
+
+// Seller reads the organizer's legal details from INVOICE_SELLER_* variables.
+func Seller() Party {
+	return Party{
+		Name:    os.Getenv("INVOICE_SELLER_NAME"),
+		Address: os.Getenv("INVOICE_SELLER_ADDRESS"),
+		TaxID:   os.Getenv("INVOICE_SELLER_TAX_ID"),
+		Email:   os.Getenv("INVOICE_SELLER_EMAIL"),
+	}
+}
+
+func seriesFor(kind string, at time.Time) string {
+	if kind == KindCreditNote {
+		return fmt.Sprintf("%s-CN-%d", Organizer(), at.Year())
+	}
+	return fmt.Sprintf("%s-%d", Organizer(), at.Year())
+}
+
+// nextNumberTx allocates the next number of a series. The counter row stays
+// locked until the transaction ends, and a rollback also undoes the
+// increment, so issued numbers have no gaps.
+func nextNumberTx(tx *sql.Tx, series string) (string, error) {
+	var n int
+	err := tx.QueryRow(`
+		INSERT INTO invoice_series (series, last_number) VALUES ($1, 1)
+		ON CONFLICT (series) DO UPDATE SET last_number = invoice_series.last_number + 1
+		RETURNING last_number`, series).Scan(&n)
+	if err != nil {
+		return "", fmt.Errorf("failed to allocate invoice number: %w", err)
+	}
+	return fmt.Sprintf("%s-%06d", series, n), nil
+}
+
+const invoiceColumns = `
+	document_id, number, kind, booking_id, COALESCE(refund_id::text, ''), COALESCE(related_number, ''),
+	issued_at, seller, buyer, lines, total, currency, COALESCE(note, '')`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanInvoice(row rowScanner) (*Invoice, error) {
+	inv := &Invoice{}
+	var seller, buyer, lines []byte
+	err := row.Scan(
+		&inv.DocumentID, &inv.Number, &inv.Kind, &inv.BookingID, &inv.RefundID, &inv.RelatedNumber,
+		&inv.IssuedAt, &seller, &buyer, &lines, &inv.Total, &inv.Currency, &inv.Note,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrInvoiceNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	_ = json.Unmarshal(seller, &inv.Seller)
+	_ = json.Unmarshal(buyer, &inv.Buyer)
+	_ = json.Unmarshal(lines, &inv.Lines)
+	return inv, nil
+}
+
+func insertTx(tx *sql.Tx, inv *Invoice) error {
+	seller, _ := json.Marshal(inv.Seller)
+	buyer, _ := json.Marshal(inv.Buyer)
+	lines, _ := json.Marshal(inv.Lines)
+	_, err := tx.Exec(`
+		INSERT INTO invoice (
+			document_id, number, kind, booking_id, refund_id, related_number,
+			issued_at, seller, buyer, lines, total, currency, note
+		) VALUES ($1, $2, $3, $4, NULLIF($5, '')::uuid, NULLIF($6, ''), $7, $8, $9, $10, $11, $12, NULLIF($13, ''))`,
+		inv.DocumentID, inv.Number, inv.Kind, inv.BookingID, inv.RefundID, inv.RelatedNumber,
+		inv.IssuedAt, seller, buyer, lines, inv.Total, inv.Currency, inv.Note)
+	if err != nil {
+		return fmt.Errorf("failed to insert %s: %w", inv.Kind, err)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package invoice
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"time"
+
+	"supra/db"
+	"supra/logger"
+
+	"github.com/google/uuid"
+)
+
+// Source is what the booking side supplies to invoice a booking.
+type Source struct {
+	BookingID string
+	Buyer     Party
+	Lines     []Line
+	Total     float64
+	Currency  string
+}
+
+// IssueInvoiceTx returns the booking's invoice, issuing it with the next
+// number of the organizer's series if it does not exist yet.
+func IssueInvoiceTx(tx *sql.Tx, src Source) (*Invoice, error) {
+	// Serialize issuance per booking so two requests cannot both number it.
+	if _, err := tx.Exec(`SELECT 1 FROM booking WHERE booking_id = $1 FOR UPDATE`, src.BookingID); err != nil {
+		return nil, fmt.Errorf("failed to lock booking: %w", err)
+	}
+	inv, err := scanInvoice(tx.QueryRow(`SELECT `+invoiceColumns+` FROM invoice WHERE booking_id = $1 AND kind = $2`, src.BookingID, KindInvoice))
+	if err != ErrInvoiceNotFound {
+		return inv, err
+	}
+
+	now := time.Now()
+	number, err := nextNumberTx(tx, seriesFor(KindInvoice, now))
+	if err != nil {
+		return nil, err
+	}
+	inv = &Invoice{
+		DocumentID: uuid.New().String(),
+		Number:     number,
+		Kind:       KindInvoice,
+		BookingID:  src.BookingID,
+		IssuedAt:   now,
+		Seller:     Seller(),
+		Buyer:      src.Buyer,
+		Lines:      src.Lines,
+		Total:      src.Total,
+		Currency:   src.Currency,
+	}
+	if err := insertTx(tx, inv); err != nil {
+		return nil, err
+	}
+	logger.Log.Info(fmt.Sprintf("[invoice-uc] Invoice %s issued for booking %s (%.2f %s)", inv.Number, inv.BookingID, inv.Total, inv.Currency))
+	return inv, nil
+}
+
+// GetInvoiceUC returns an already issued invoice of a booking.
+func GetInvoiceUC(bookingID string) (*Invoice, error) {
+	return scanInvoice(db.DB.QueryRow(`SELECT `+invoiceColumns+` FROM invoice WHERE booking_id = $1 AND kind = $2`, bookingID, KindInvoice))
+}
+
+// GetCreditNoteUC returns the credit note issued for a refund.
+func GetCreditNoteUC(refundID string) (*Invoice, error) {
+	if _, err := uuid.Parse(refundID); err != nil {
+		return nil, fmt.Errorf("invalid refund ID format: %w", err)
+	}
+	return scanInvoice(db.DB.QueryRow(`SELECT `+invoiceColumns+` FROM invoice WHERE refund_id = $1 AND kind = $2`, refundID, KindCreditNote))
+}
+
+// IssueCreditNoteUC credits amount of the booking's invoice for a paid
+// refund. It is idempotent per refund.
+func IssueCreditNoteUC(bookingID, refundID string, amount float64, reason string) (*Invoice, error) {
+	logger.Log.Info(fmt.Sprintf("[invoice-uc] Credit note requested for refund %s (booking %s)", refundID, bookingID))
+
+	tx, err := db.DB.BeginTx(context.Background(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`SELECT 1 FROM booking WHERE booking_id = $1 FOR UPDATE`, bookingID); err != nil {
+		return nil, fmt.Errorf("failed to lock booking: %w", err)
+	}
+	if cn, err := scanInvoice(tx.QueryRow(`SELECT `+invoiceColumns+` FROM invoice WHERE refund_id = $1`, refundID)); err != ErrInvoiceNotFound {
+		return cn, err
+	}
+	orig, err := scanInvoice(tx.QueryRow(`SELECT `+invoiceColumns+` FROM invoice WHERE booking_id = $1 AND kind = $2`, bookingID, KindInvoice))
+	if err == ErrInvoiceNotFound {
+		return nil, ErrNoInvoice
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	amount = math.Min(math.Round(amount*100)/100, orig.Total)
+	now := time.Now()
+	number, err := nextNumberTx(tx, seriesFor(KindCreditNote, now))
+	if err != nil {
+		return nil, err
+	}
+	cn := &Invoice{
+		DocumentID:    uuid.New().String(),
+		Number:        number,
+		Kind:          KindCreditNote,
+		BookingID:     bookingID,
+		RefundID:      refundID,
+		RelatedNumber: orig.Number,
+		IssuedAt:      now,
+		Seller:        Seller(),
+		Buyer:         orig.Buyer,
+		Lines:         []Line{{Description: fmt.Sprintf("Refund against invoice %s", orig.Number), Quantity: 1, UnitPrice: -amount, Amount: -amount}},
+		Total:         -amount,
+		Currency:      orig.Currency,
+		Note:          reason,
+	}
+	if err := insertTx(tx, cn); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit failed: %w", err)
+	}
+
+	logger.Log.Info(fmt.Sprintf("[invoice-uc] Credit note %s issued against %s (%.2f %s)", cn.Number, orig.Number, cn.Total, cn.Currency))
+	return cn, nil
+}
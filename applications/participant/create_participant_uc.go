@@ -7,6 +7,7 @@ import (
 
 	"supra/db"     // Using the correct module path
 	"supra/logger" // ⬅️ Assuming this import path
+	"supra/validation"
 
 	"github.com/google/uuid"
 )
@@ -15,9 +16,9 @@ import (
 
 // CreateParticipantParams is used for the creation payload.
 type CreateParticipantParams struct {
-	Name  string `json:"name" validate:"required"`
-	WaNum string `json:"waNum" validate:"required"`
-	Email string `json:"email,omitempty"`
+	Name  string `json:"name" validate:"required,max=100"`
+	WaNum string `json:"waNum" validate:"required,max=20"`
+	Email string `json:"email,omitempty" validate:"email"`
 }
 
 // AddParticipant handles the creation of a new participant record in the database.
@@ -29,6 +30,9 @@ func AddParticipant(payload []byte) (*Participant, error) {
 		logger.Log.Error(fmt.Sprintf("[create-participant-uc] Unmarshal failed: %v", err))
 		return nil, fmt.Errorf("failed to unmarshal payload: %w", err)
 	}
+	if err := validation.Struct(&p); err != nil {
+		return nil, err
+	}
 
 	newID := uuid.New().String()
 	logger.Log.Info(fmt.Sprintf("[create-participant-uc] Generated UserID: %s for Name: %s", newID, p.Name))
@@ -73,6 +77,9 @@ func AddParticipantTx(tx *sql.Tx, payload []byte) (*Participant, error) {
 		logger.Log.Error(fmt.Sprintf("[create-participant-uc] Transactional unmarshal failed: %v", err))
 		return nil, fmt.Errorf("failed to unmarshal participant payload: %w", err)
 	}
+	if err := validation.Struct(&p); err != nil {
+		return nil, err
+	}
 
 	newID := uuid.New().String()
 	logger.Log.Info(fmt.Sprintf("[create-participant-uc] Generated Transactional UserID: %s for Name: %s", newID, p.Name))
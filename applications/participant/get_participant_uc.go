@@ -58,3 +58,20 @@ func GetParticipant(userID string) (*Participant, error) {
 	logger.Log.Info(fmt.Sprintf("[get-participant-uc] Participant %s retrieved successfully. Name: %s", userID, p.Name))
 	return p, nil
 }
+
+// GetParticipantTx reads a participant within a transaction.
+func GetParticipantTx(tx *sql.Tx, userID string) (*Participant, error) {
+	p := &Participant{}
+	var userIDUUID uuid.UUID
+	err := tx.QueryRow(`SELECT user_id, name, wa_num, email, attended FROM participant WHERE user_id = $1`, userID).Scan(
+		&userIDUUID, &p.Name, &p.WaNum, &p.Email, &p.Attended,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("participant with ID %s not found", userID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("database query error: %w", err)
+	}
+	p.UserID = userIDUUID.String()
+	return p, nil
+}
@@ -8,15 +8,16 @@ import (
 
 	"supra/db"     // Using the correct module path
 	"supra/logger" // ⬅️ Assuming this import path
+	"supra/validation"
 
 	"github.com/google/uuid"
 )
 
 // UpdateParticipantParams defines fields that can be optionally updated.
 type UpdateParticipantParams struct {
-	Name     string `json:"name,omitempty"`
-	WaNum    string `json:"waNum,omitempty"`
-	Email    string `json:"email,omitempty"`
+	Name     string `json:"name,omitempty" validate:"max=100"`
+	WaNum    string `json:"waNum,omitempty" validate:"max=20"`
+	Email    string `json:"email,omitempty" validate:"email"`
 	Attended *bool  `json:"attended,omitempty"` // Use pointer to differentiate false from omitted
 }
 
@@ -31,6 +32,9 @@ func UpdateParticipant(userID string, payload []byte) (*Participant, error) {
 		logger.Log.Error(fmt.Sprintf("[update-participant-uc] Unmarshal failed for %s: %v", userID, err))
 		return nil, fmt.Errorf("failed to unmarshal payload: %w", err)
 	}
+	if err := validation.Struct(&p); err != nil {
+		return nil, err
+	}
 
 	id, err := uuid.Parse(userID)
 	if err != nil {
@@ -96,3 +100,31 @@ func UpdateParticipant(userID string, payload []byte) (*Participant, error) {
 	logger.Log.Info(fmt.Sprintf("[update-participant-uc] Participant %s updated successfully. Name: %s", userID, pt.Name))
 	return pt, nil
 }
+
+// UpdateParticipantDetailsTx changes the contact details of a participant
+// within a transaction. Empty fields are left unchanged and the attendance
+// flag is never touched; it is used by self-service booking edits.
+func UpdateParticipantDetailsTx(tx *sql.Tx, userID string, p UpdateParticipantParams) (*Participant, error) {
+	const updateSQL = `
+		UPDATE participant
+		SET name = COALESCE(NULLIF($2, ''), name),
+		    wa_num = COALESCE(NULLIF($3, ''), wa_num),
+		    email = COALESCE(NULLIF($4, ''), email)
+		WHERE user_id = $1
+		RETURNING user_id, name, wa_num, email, attended`
+
+	pt := &Participant{}
+	var userIDUUID uuid.UUID
+	err := tx.QueryRow(updateSQL, userID, strings.TrimSpace(p.Name), strings.TrimSpace(p.WaNum), strings.TrimSpace(p.Email)).Scan(
+		&userIDUUID, &pt.Name, &pt.WaNum, &pt.Email, &pt.Attended,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("participant with ID %s not found", userID)
+	}
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("[update-participant-uc] Transactional update error for %s: %v", userID, err))
+		return nil, fmt.Errorf("transactional update failed: %w", err)
+	}
+	pt.UserID = userIDUUID.String()
+	return pt, nil
+}
@@ -6,6 +6,7 @@ import (
 
 	"supra/db"     // Using the correct module path
 	"supra/logger" // ⬅️ Assuming this import path
+	"supra/validation"
 
 	"github.com/google/uuid"
 )
@@ -14,9 +15,9 @@ import (
 
 // CreatePaymentParams is used for the creation payload.
 type CreatePaymentParams struct {
-	PaymentType string `json:"paymentType" validate:"required"`
-	Details     string `json:"details" validate:"required"`
-	Notes       string `json:"notes,omitempty"`
+	PaymentType string `json:"paymentType" validate:"required,max=50"`
+	Details     string `json:"details" validate:"required,max=1000"`
+	Notes       string `json:"notes,omitempty" validate:"max=500"`
 }
 
 // AddPayment handles the creation of a new payment record in the database.
@@ -28,6 +29,9 @@ func AddPayment(payload []byte) (*PaymentDetails, error) {
 		logger.Log.Error(fmt.Sprintf("[create-paymentdetails-uc] Unmarshal failed: %v", err))
 		return nil, fmt.Errorf("failed to unmarshal payload: %w", err)
 	}
+	if err := validation.Struct(&p); err != nil {
+		return nil, err
+	}
 
 	newID := uuid.New().String()
 	logger.Log.Info(fmt.Sprintf("[create-paymentdetails-uc] Generated PaymentID: %s for type: %s", newID, p.PaymentType))
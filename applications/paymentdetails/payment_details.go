@@ -2,7 +2,7 @@ package paymentdetails
 
 type PaymentDetails struct {
 	PaymentID   string `json:"paymentID" validate:"required"`
-	PaymentType string `json:"paymentType" validate:"requried"`
-	Details     string `json:"details" validate:"requried"`
+	PaymentType string `json:"paymentType" validate:"required"`
+	Details     string `json:"details" validate:"required"`
 	Notes       string `json:"notes,omitempty"`
 }
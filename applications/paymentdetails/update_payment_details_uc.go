@@ -8,6 +8,7 @@ import (
 
 	"supra/db"     // Using the correct module path
 	"supra/logger" // ⬅️ Assuming this import path
+	"supra/validation"
 
 	"github.com/google/uuid"
 )
@@ -15,9 +16,9 @@ import (
 // UpdatePaymentParams defines fields that can be optionally updated for a payment record.
 // All fields use the omitempty tag to enable partial updates.
 type UpdatePaymentParams struct {
-	PaymentType string `json:"paymentType,omitempty"`
-	Details     string `json:"details,omitempty"`
-	Notes       string `json:"notes,omitempty"`
+	PaymentType string `json:"paymentType,omitempty" validate:"max=50"`
+	Details     string `json:"details,omitempty" validate:"max=1000"`
+	Notes       string `json:"notes,omitempty" validate:"max=500"`
 }
 
 // NOTE: The PaymentDetails struct and GetPayment function are assumed to be defined elsewhere in this package.
@@ -32,6 +33,9 @@ func UpdatePayment(paymentID string, payload []byte) (*PaymentDetails, error) {
 		logger.Log.Error(fmt.Sprintf("[update-payment-details-uc] Unmarshal failed for %s: %v", paymentID, err))
 		return nil, fmt.Errorf("failed to unmarshal payload: %w", err)
 	}
+	if err := validation.Struct(&p); err != nil {
+		return nil, err
+	}
 
 	// 1. Validate ID
 	id, err := uuid.Parse(paymentID)
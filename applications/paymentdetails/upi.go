@@ -0,0 +1,77 @@
+package paymentdetails
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// PaymentTypeUPI marks payment details whose Details field holds a UPI ID (VPA).
+const PaymentTypeUPI = "UPI"
+
+var vpaRe = regexp.MustCompile(`[A-Za-z0-9.\-_]{2,256}@[A-Za-z][A-Za-z0-9]{1,63}`)
+
+// UPIIntent is a ready-to-pay UPI request for one booking.
+type UPIIntent struct {
+	Link      string  `json:"link"`      // upi://pay deep link
+	QRCode    string  `json:"qrCode"`    // base64 PNG of the link
+	Reference string  `json:"reference"` // transaction reference (tr) to match the payment
+	Payee     string  `json:"payee"`
+	Amount    float64 `json:"amount"`
+}
+
+// IsUPI reports whether the payment details describe a UPI payee.
+func (pd *PaymentDetails) IsUPI() bool {
+	return pd != nil && strings.EqualFold(strings.TrimSpace(pd.PaymentType), PaymentTypeUPI)
+}
+
+// UPIAddress extracts the UPI ID from the free-text details.
+func (pd *PaymentDetails) UPIAddress() (string, bool) {
+	if !pd.IsUPI() {
+		return "", false
+	}
+	vpa := vpaRe.FindString(pd.Details)
+	return vpa, vpa != ""
+}
+
+// BuildUPIIntent creates a upi://pay link (and its QR) for an exact amount.
+// reference becomes the transaction reference and note is shown in the payer's app.
+func BuildUPIIntent(pd *PaymentDetails, amount float64, reference, note string) (*UPIIntent, error) {
+	vpa, ok := pd.UPIAddress()
+	if !ok {
+		return nil, fmt.Errorf("payment %s has no UPI ID", pd.PaymentID)
+	}
+
+	payee := os.Getenv("UPI_PAYEE_NAME")
+	if payee == "" {
+		payee = "BlackTicket Entertainments"
+	}
+
+	q := url.Values{}
+	q.Set("pa", vpa)
+	q.Set("pn", payee)
+	q.Set("am", fmt.Sprintf("%.2f", amount))
+	q.Set("cu", "INR")
+	q.Set("tr", reference)
+	q.Set("tn", note)
+	// UPI apps expect %20 rather than + for spaces.
+	link := "upi://pay?" + strings.ReplaceAll(q.Encode(), "+", "%20")
+
+	png, err := qrcode.Encode(link, qrcode.Medium, 512)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render UPI QR: %w", err)
+	}
+
+	return &UPIIntent{
+		Link:      link,
+		QRCode:    base64.StdEncoding.EncodeToString(png),
+		Reference: reference,
+		Payee:     vpa,
+		Amount:    amount,
+	}, nil
+}
@@ -0,0 +1,77 @@
+package promotion
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"supra/logger"
+
+	"github.com/google/uuid"
+)
+
+// Cart is what a booking would buy; rules are checked against it.
+type Cart struct {
+	ConcertID string
+	SeatType  string
+	Quantity  int
+	Email     string
+	Subtotal  float64
+}
+
+// ApplyTx checks code against the cart and returns the promotion and the
+// discount it grants. The promotion row is locked so concurrent bookings
+// cannot overshoot the usage limits.
+func ApplyTx(tx *sql.Tx, code string, cart Cart) (*Promotion, float64, error) {
+	code = NormalizeCode(code)
+	p, err := scanPromotion(tx.QueryRow(`SELECT `+promotionColumns+` FROM promotion p WHERE p.code = $1 FOR UPDATE OF p`, code))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	now := time.Now()
+	switch {
+	case !p.Active:
+		return nil, 0, ErrPromoInactive
+	case p.ValidFrom != nil && now.Before(*p.ValidFrom):
+		return nil, 0, ErrPromoNotStarted
+	case p.ValidUntil != nil && now.After(*p.ValidUntil):
+		return nil, 0, ErrPromoExpired
+	case len(p.ConcertIDs) > 0 && !contains(p.ConcertIDs, cart.ConcertID),
+		len(p.SeatTypes) > 0 && !contains(p.SeatTypes, cart.SeatType):
+		return nil, 0, ErrPromoNotApplicable
+	case cart.Quantity < p.MinQuantity:
+		return nil, 0, fmt.Errorf("%w: minimum %d", ErrPromoMinQuantity, p.MinQuantity)
+	case p.MaxUses > 0 && p.Redemptions >= p.MaxUses:
+		return nil, 0, ErrPromoExhausted
+	}
+
+	if p.MaxUsesPerEmail > 0 {
+		var used int
+		err := tx.QueryRow(`
+			SELECT COUNT(*) FROM promotion_redemption r JOIN booking b ON b.booking_id = r.booking_id
+			WHERE r.code = $1 AND lower(r.email) = lower($2) AND b.booking_status <> 'CANCELLED'`,
+			code, cart.Email).Scan(&used)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to count promo usage: %w", err)
+		}
+		if used >= p.MaxUsesPerEmail {
+			return nil, 0, ErrPromoEmailLimit
+		}
+	}
+
+	discount := p.Discount(cart.Subtotal)
+	logger.Log.Info(fmt.Sprintf("[apply-promotion] %s grants %.2f off %.2f", code, discount, cart.Subtotal))
+	return p, discount, nil
+}
+
+// RecordRedemptionTx ties a promo code to the booking that used it.
+func RecordRedemptionTx(tx *sql.Tx, code string, bookingID uuid.UUID, email string, discount float64) error {
+	_, err := tx.Exec(`
+		INSERT INTO promotion_redemption (booking_id, code, email, discount, created_at)
+		VALUES ($1, $2, $3, $4, $5)`, bookingID, NormalizeCode(code), email, discount, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to record promo redemption: %w", err)
+	}
+	return nil
+}
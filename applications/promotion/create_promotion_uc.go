@@ -0,0 +1,54 @@
+package promotion
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"supra/db"
+	"supra/logger"
+
+	"github.com/lib/pq"
+)
+
+// CreatePromotionUC stores a new promo code.
+func CreatePromotionUC(payload []byte) (*Promotion, error) {
+	logger.Log.Info("[create-promotion-uc] Creating promotion")
+
+	var p Promotion
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return nil, fmt.Errorf("invalid promotion payload: %w", err)
+	}
+	p.Code = NormalizeCode(p.Code)
+	if p.DiscountType == "" {
+		p.DiscountType = PERCENT
+	}
+	p.Active = true
+	p.CreatedAt = time.Now()
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	concertIDs, _ := json.Marshal(p.ConcertIDs)
+	seatTypes, _ := json.Marshal(p.SeatTypes)
+	_, err := db.DB.Exec(`
+		INSERT INTO promotion (
+			code, description, discount_type, value, valid_from, valid_until,
+			max_uses, max_uses_per_email, concert_ids, seat_types, min_quantity, active, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`,
+		p.Code, p.Description, p.DiscountType, p.Value, p.ValidFrom, p.ValidUntil,
+		p.MaxUses, p.MaxUsesPerEmail, concertIDs, seatTypes, p.MinQuantity, p.Active, p.CreatedAt,
+	)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return nil, ErrPromoExists
+		}
+		logger.Log.Error(fmt.Sprintf("[create-promotion-uc] Insert failed for %s: %v", p.Code, err))
+		return nil, fmt.Errorf("failed to insert promotion: %w", err)
+	}
+
+	logger.Log.Info(fmt.Sprintf("[create-promotion-uc] Promotion %s created (%s %.2f)", p.Code, p.DiscountType, p.Value))
+	return &p, nil
+}
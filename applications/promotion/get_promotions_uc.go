@@ -0,0 +1,93 @@
+package promotion
+
+import (
+	"fmt"
+
+	"supra/db"
+	"supra/logger"
+)
+
+// GetPromotionUC returns one promo code with its usage.
+func GetPromotionUC(code string) (*Promotion, error) {
+	return scanPromotion(db.DB.QueryRow(`SELECT `+promotionColumns+` FROM promotion p WHERE p.code = $1`, NormalizeCode(code)))
+}
+
+// GetPromotionsUC lists every promo code with its usage, newest first.
+func GetPromotionsUC() ([]*Promotion, error) {
+	logger.Log.Info("[get-promotions-uc] Listing promotions")
+
+	rows, err := db.DB.Query(`SELECT ` + promotionColumns + ` FROM promotion p ORDER BY p.created_at DESC`)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("[get-promotions-uc] Database query failed: %v", err))
+		return nil, fmt.Errorf("database query error: %w", err)
+	}
+	defer rows.Close()
+
+	promos := make([]*Promotion, 0)
+	for rows.Next() {
+		p, err := scanPromotion(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning promotion row: %w", err)
+		}
+		promos = append(promos, p)
+	}
+	return promos, rows.Err()
+}
+
+// ConcertStats summarises discounts granted for one concert.
+type ConcertStats struct {
+	ConcertID     string         `json:"concertID"`
+	Bookings      int            `json:"bookings"`
+	Discounted    int            `json:"discountedBookings"`
+	GrossAmount   float64        `json:"grossAmount"` // before discounts
+	TotalDiscount float64        `json:"totalDiscount"`
+	NetAmount     float64        `json:"netAmount"`
+	ByCode        map[string]int `json:"redemptionsByCode"`
+}
+
+// GetPromotionStatsUC reports discount usage per concert across non-cancelled bookings.
+func GetPromotionStatsUC() ([]*ConcertStats, error) {
+	logger.Log.Info("[get-promotions-uc] Building promotion stats")
+
+	rows, err := db.DB.Query(`
+		SELECT b.concert_id, COALESCE(r.code, ''), COUNT(*),
+		       COALESCE(SUM(b.total_amount), 0), COALESCE(SUM(r.discount), 0)
+		FROM booking b
+		LEFT JOIN promotion_redemption r ON r.booking_id = b.booking_id
+		WHERE b.booking_status <> 'CANCELLED'
+		GROUP BY b.concert_id, r.code
+		ORDER BY b.concert_id`)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("[get-promotions-uc] Stats query failed: %v", err))
+		return nil, fmt.Errorf("database query error: %w", err)
+	}
+	defer rows.Close()
+
+	stats := make([]*ConcertStats, 0)
+	byConcert := map[string]*ConcertStats{}
+	for rows.Next() {
+		var concertID, code string
+		var count int
+		var net, discount float64
+		if err := rows.Scan(&concertID, &code, &count, &net, &discount); err != nil {
+			return nil, fmt.Errorf("error scanning stats row: %w", err)
+		}
+		s, ok := byConcert[concertID]
+		if !ok {
+			s = &ConcertStats{ConcertID: concertID, ByCode: map[string]int{}}
+			byConcert[concertID] = s
+			stats = append(stats, s)
+		}
+		s.Bookings += count
+		s.NetAmount += net
+		s.TotalDiscount += discount
+		if code != "" {
+			s.Discounted += count
+			s.ByCode[code] += count
+		}
+	}
+	for _, s := range stats {
+		s.GrossAmount = s.NetAmount + s.TotalDiscount
+	}
+	return stats, rows.Err()
+}
@@ -0,0 +1,141 @@
+package promotion
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+type Promotion struct {
+	Code            string     `json:"code"`
+	Description     string     `json:"description,omitempty"`
+	DiscountType    string     `json:"discountType"` // PERCENT or FIXED
+	Value           float64    `json:"value"`        // percent (0-100] or amount off the subtotal
+	ValidFrom       *time.Time `json:"validFrom,omitempty"`
+	ValidUntil      *time.Time `json:"validUntil,omitempty"`
+	MaxUses         int        `json:"maxUses"`         // 0 = unlimited
+	MaxUsesPerEmail int        `json:"maxUsesPerEmail"` // 0 = unlimited
+	ConcertIDs      []string   `json:"concertIDs,omitempty"`
+	SeatTypes       []string   `json:"seatTypes,omitempty"`
+	MinQuantity     int        `json:"minQuantity"`
+	Active          bool       `json:"active"`
+	CreatedAt       time.Time  `json:"createdAt"`
+	Redemptions     int        `json:"redemptions"` // bookings that used the code and are not cancelled
+	TotalDiscount   float64    `json:"totalDiscount"`
+}
+
+const (
+	PERCENT = "PERCENT"
+	FIXED   = "FIXED"
+)
+
+var (
+	ErrPromoNotFound      = errors.New("promo code not found")
+	ErrPromoExists        = errors.New("promo code already exists")
+	ErrPromoInactive      = errors.New("promo code is not active")
+	ErrPromoNotStarted    = errors.New("promo code is not valid yet")
+	ErrPromoExpired       = errors.New("promo code has expired")
+	ErrPromoExhausted     = errors.New("promo code usage limit reached")
+	ErrPromoEmailLimit    = errors.New("promo code already used the maximum number of times by this email")
+	ErrPromoNotApplicable = errors.New("promo code does not apply to this concert or seat type")
+	ErrPromoMinQuantity   = errors.New("promo code requires more seats")
+)
+
+// NormalizeCode makes codes case-insensitive for customers.
+func NormalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Validate checks a promotion's rule fields before it is stored.
+func (p *Promotion) Validate() error {
+	if p.Code == "" {
+		return fmt.Errorf("invalid promotion: code is required")
+	}
+	switch p.DiscountType {
+	case PERCENT:
+		if p.Value <= 0 || p.Value > 100 {
+			return fmt.Errorf("invalid promotion: percentage must be in (0, 100]")
+		}
+	case FIXED:
+		if p.Value <= 0 {
+			return fmt.Errorf("invalid promotion: fixed discount must be positive")
+		}
+	default:
+		return fmt.Errorf("invalid promotion: discountType must be %s or %s", PERCENT, FIXED)
+	}
+	if p.MaxUses < 0 || p.MaxUsesPerEmail < 0 || p.MinQuantity < 0 {
+		return fmt.Errorf("invalid promotion: limits cannot be negative")
+	}
+	if p.ValidFrom != nil && p.ValidUntil != nil && !p.ValidUntil.After(*p.ValidFrom) {
+		return fmt.Errorf("invalid promotion: validUntil must be after validFrom")
+	}
+	return nil
+}
+
+// Discount returns the amount taken off subtotal, never more than subtotal.
+func (p *Promotion) Discount(subtotal float64) float64 {
+	var d float64
+	if p.DiscountType == PERCENT {
+		d = subtotal * p.Value / 100
+	} else {
+		d = p.Value
+	}
+	d = math.Min(d, subtotal)
+	return math.Round(d*100) / 100
+}
+
+func contains(list []string, v string) bool {
+	for _, s := range list {
+		if strings.EqualFold(s, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// promotionColumns selects a promotion with its live usage figures.
+const promotionColumns = `
+	p.code, COALESCE(p.description, ''), p.discount_type, p.value, p.valid_from, p.valid_until,
+	p.max_uses, p.max_uses_per_email, p.concert_ids, p.seat_types, p.min_quantity, p.active, p.created_at,
+	(SELECT COUNT(*) FROM promotion_redemption r JOIN booking b ON b.booking_id = r.booking_id
+	  WHERE r.code = p.code AND b.booking_status <> 'CANCELLED'),
+	(SELECT COALESCE(SUM(r.discount), 0) FROM promotion_redemption r JOIN booking b ON b.booking_id = r.booking_id
+	  WHERE r.code = p.code AND b.booking_status <> 'CANCELLED')`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanPromotion(row rowScanner) (*Promotion, error) {
+	p := &Promotion{}
+	var from, until sql.NullTime
+	var concertIDs, seatTypes []byte
+	err := row.Scan(
+		&p.Code, &p.Description, &p.DiscountType, &p.Value, &from, &until,
+		&p.MaxUses, &p.MaxUsesPerEmail, &concertIDs, &seatTypes, &p.MinQuantity, &p.Active, &p.CreatedAt,
+		&p.Redemptions, &p.TotalDiscount,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrPromoNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if from.Valid {
+		p.ValidFrom = &from.Time
+	}
+	if until.Valid {
+		p.ValidUntil = &until.Time
+	}
+	if len(concertIDs) > 0 {
+		_ = json.Unmarshal(concertIDs, &p.ConcertIDs)
+	}
+	if len(seatTypes) > 0 {
+		_ = json.Unmarshal(seatTypes, &p.SeatTypes)
+	}
+	return p, nil
+}
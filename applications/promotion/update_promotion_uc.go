@@ -0,0 +1,64 @@
+package promotion
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"supra/db"
+	"supra/logger"
+)
+
+// UpdatePromotionUC replaces the rules of an existing promo code. The code
+// itself cannot change because redemptions reference it.
+func UpdatePromotionUC(code string, payload []byte) (*Promotion, error) {
+	code = NormalizeCode(code)
+	logger.Log.Info(fmt.Sprintf("[update-promotion-uc] Updating promotion %s", code))
+
+	current, err := GetPromotionUC(code)
+	if err != nil {
+		return nil, err
+	}
+
+	p := *current
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return nil, fmt.Errorf("invalid promotion payload: %w", err)
+	}
+	p.Code = code
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	concertIDs, _ := json.Marshal(p.ConcertIDs)
+	seatTypes, _ := json.Marshal(p.SeatTypes)
+	_, err = db.DB.Exec(`
+		UPDATE promotion SET
+			description = $2, discount_type = $3, value = $4, valid_from = $5, valid_until = $6,
+			max_uses = $7, max_uses_per_email = $8, concert_ids = $9, seat_types = $10,
+			min_quantity = $11, active = $12
+		WHERE code = $1`,
+		p.Code, p.Description, p.DiscountType, p.Value, p.ValidFrom, p.ValidUntil,
+		p.MaxUses, p.MaxUsesPerEmail, concertIDs, seatTypes, p.MinQuantity, p.Active,
+	)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("[update-promotion-uc] Update failed for %s: %v", code, err))
+		return nil, fmt.Errorf("failed to update promotion: %w", err)
+	}
+
+	logger.Log.Info(fmt.Sprintf("[update-promotion-uc] Promotion %s updated (active=%t)", code, p.Active))
+	return &p, nil
+}
+
+// DeactivatePromotionUC switches a code off. Codes are never deleted so past
+// bookings keep their discount history.
+func DeactivatePromotionUC(code string) error {
+	code = NormalizeCode(code)
+	res, err := db.DB.Exec(`UPDATE promotion SET active = FALSE WHERE code = $1`, code)
+	if err != nil {
+		return fmt.Errorf("failed to deactivate promotion: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrPromoNotFound
+	}
+	logger.Log.Info(fmt.Sprintf("[update-promotion-uc] Promotion %s deactivated", code))
+	return nil
+}
@@ -0,0 +1,81 @@
+package reconciliation
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// camtDocument covers the parts of an ISO 20022 camt.053 statement we need.
+type camtDocument struct {
+	Statements []struct {
+		Entries []camtEntry `xml:"Ntry"`
+	} `xml:"BkToCstmrStmt>Stmt"`
+}
+
+type camtEntry struct {
+	Ref    string `xml:"NtryRef"`
+	SvcRef string `xml:"AcctSvcrRef"`
+	Amount struct {
+		Value string `xml:",chardata"`
+	} `xml:"Amt"`
+	CdtDbt   string `xml:"CdtDbtInd"`
+	BookDate string `xml:"BookgDt>Dt"`
+	BookDtTm string `xml:"BookgDt>DtTm"`
+	ValDate  string `xml:"ValDt>Dt"`
+	Info     string `xml:"AddtlNtryInf"`
+	Details  []struct {
+		EndToEnd string   `xml:"Refs>EndToEndId"`
+		TxID     string   `xml:"Refs>TxId"`
+		Ustrd    []string `xml:"RmtInf>Ustrd"`
+		Ref      string   `xml:"RmtInf>Strd>CdtrRefInf>Ref"`
+		Debtor   string   `xml:"RltdPties>Dbtr>Nm"`
+	} `xml:"NtryDtls>TxDtls"`
+}
+
+func parseCAMT053(data []byte) ([]Transaction, error) {
+	var doc camtDocument
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to read CAMT.053 statement: %w", err)
+	}
+
+	var txns []Transaction
+	for _, st := range doc.Statements {
+		for i, e := range st.Entries {
+			rawDate := e.BookDate
+			if rawDate == "" && len(e.BookDtTm) >= 10 {
+				rawDate = e.BookDtTm[:10]
+			}
+			if rawDate == "" {
+				rawDate = e.ValDate
+			}
+			date, err := parseStatementDate(rawDate)
+			if err != nil {
+				return nil, fmt.Errorf("CAMT entry %d: %w", i+1, err)
+			}
+			amount, err := parseStatementAmount(e.Amount.Value)
+			if err != nil {
+				return nil, fmt.Errorf("CAMT entry %d: %w", i+1, err)
+			}
+			if strings.EqualFold(e.CdtDbt, "DBIT") {
+				amount = -amount
+			}
+
+			parts := []string{e.Info}
+			for _, d := range e.Details {
+				parts = append(parts, d.Debtor, d.EndToEnd, d.TxID, d.Ref)
+				parts = append(parts, d.Ustrd...)
+			}
+
+			id := e.SvcRef
+			if id == "" {
+				id = e.Ref
+			}
+			if id == "" {
+				id = fmt.Sprintf("camt-%d", i+1)
+			}
+			txns = append(txns, Transaction{ID: id, Date: date, Amount: amount, Narration: strings.Join(strings.Fields(strings.Join(parts, " ")), " ")})
+		}
+	}
+	return txns, nil
+}
@@ -0,0 +1,104 @@
+package reconciliation
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strings"
+)
+
+// csvColumns maps our fields to common header names used by Indian and European banks.
+var csvColumns = map[string][]string{
+	"date":      {"date", "txn date", "transaction date", "value date", "posting date", "booking date"},
+	"amount":    {"amount", "transaction amount"},
+	"credit":    {"credit", "deposit", "deposits", "credit amount", "cr", "deposit amt.", "deposit amount"},
+	"debit":     {"debit", "withdrawal", "withdrawals", "debit amount", "dr", "withdrawal amt.", "withdrawal amount"},
+	"narration": {"narration", "description", "remarks", "particulars", "details", "transaction remarks", "reference", "memo"},
+	"reference": {"ref no", "reference no", "ref no./cheque no.", "chq/ref number", "utr", "transaction id", "chq./ref.no."},
+}
+
+func parseCSV(data []byte) ([]Transaction, error) {
+	sep := ','
+	if firstLine, _, _ := bytes.Cut(data, []byte("\n")); bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		sep = ';'
+	}
+	r := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	r.Comma = sep
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV statement: %w", err)
+	}
+
+	// Bank exports often start with account summary lines; find the header row.
+	headerRow, cols := -1, map[string]int{}
+	for i, rec := range records {
+		found := map[string]int{}
+		for j, cell := range rec {
+			name := strings.ToLower(strings.TrimSpace(cell))
+			for field, aliases := range csvColumns {
+				if _, ok := found[field]; ok {
+					continue
+				}
+				for _, alias := range aliases {
+					if name == alias {
+						found[field] = j
+					}
+				}
+			}
+		}
+		_, hasDate := found["date"]
+		_, hasAmount := found["amount"]
+		_, hasCredit := found["credit"]
+		if hasDate && (hasAmount || hasCredit) {
+			headerRow, cols = i, found
+			break
+		}
+	}
+	if headerRow < 0 {
+		return nil, fmt.Errorf("CSV statement has no recognisable header (need a date and an amount or credit column)")
+	}
+
+	cell := func(rec []string, field string) string {
+		if j, ok := cols[field]; ok && j < len(rec) {
+			return strings.TrimSpace(rec[j])
+		}
+		return ""
+	}
+
+	var txns []Transaction
+	for i, rec := range records[headerRow+1:] {
+		rawDate := cell(rec, "date")
+		if rawDate == "" {
+			continue
+		}
+		date, err := parseStatementDate(rawDate)
+		if err != nil {
+			continue // footer / summary rows
+		}
+
+		var amount float64
+		if _, ok := cols["credit"]; ok {
+			amount, err = parseStatementAmount(cell(rec, "credit"))
+		} else {
+			amount, err = parseStatementAmount(cell(rec, "amount"))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("CSV row %d: %w", headerRow+i+2, err)
+		}
+
+		narration := cell(rec, "narration")
+		if ref := cell(rec, "reference"); ref != "" {
+			narration = strings.TrimSpace(narration + " " + ref)
+		}
+		id := cell(rec, "reference")
+		if id == "" {
+			id = fmt.Sprintf("row-%d", headerRow+i+2)
+		}
+
+		txns = append(txns, Transaction{ID: id, Date: date, Amount: amount, Narration: narration})
+	}
+	return txns, nil
+}
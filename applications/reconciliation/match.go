@@ -0,0 +1,103 @@
+package reconciliation
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"supra/applications/booking"
+)
+
+// Scoring weights; a match needs the amount plus at least one of reference/date.
+const (
+	scoreAmount    = 0.5
+	scoreReference = 0.4
+	scoreDate      = 0.1
+
+	minProposalScore = 0.6
+)
+
+// Date window around the booking in which its transfer is expected to land.
+var (
+	windowBefore = 24 * time.Hour
+	windowAfter  = 5 * 24 * time.Hour
+)
+
+// Match proposes a booking for a bank transaction.
+type Match struct {
+	Transaction Transaction `json:"transaction"`
+	BookingID   string      `json:"bookingID"`
+	BookingRef  string      `json:"paymentReference"`
+	Expected    float64     `json:"expectedAmount"`
+	Confidence  float64     `json:"confidence"`
+	Reasons     []string    `json:"reasons"`
+	Applied     bool        `json:"applied"`
+	ApplyError  string      `json:"applyError,omitempty"`
+}
+
+func scoreMatch(t Transaction, bk *booking.Booking) (float64, []string) {
+	var score float64
+	var reasons []string
+
+	if math.Abs(t.Amount-bk.TotalAmount) < 0.01 {
+		score += scoreAmount
+		reasons = append(reasons, "amount")
+	}
+
+	narration := strings.ToUpper(strings.ReplaceAll(t.Narration, " ", ""))
+	compactID := strings.ToUpper(strings.ReplaceAll(bk.BookingID.String(), "-", ""))
+	if (bk.PaymentReference != "" && strings.Contains(narration, bk.PaymentReference)) ||
+		strings.Contains(narration, compactID[:12]) {
+		score += scoreReference
+		reasons = append(reasons, "reference")
+	}
+
+	if !t.Date.Before(bk.CreatedAt.Add(-windowBefore).Truncate(24*time.Hour)) && !t.Date.After(bk.CreatedAt.Add(windowAfter)) {
+		score += scoreDate
+		reasons = append(reasons, "date")
+	}
+
+	return math.Round(score*100) / 100, reasons
+}
+
+// matchTransactions pairs each transaction with at most one booking (and vice
+// versa), best scores first.
+func matchTransactions(txns []Transaction, pending []*booking.Booking) ([]*Match, []Transaction) {
+	var candidates []*Match
+	for _, t := range txns {
+		for _, bk := range pending {
+			score, reasons := scoreMatch(t, bk)
+			if score < minProposalScore {
+				continue
+			}
+			candidates = append(candidates, &Match{
+				Transaction: t,
+				BookingID:   bk.BookingID.String(),
+				BookingRef:  bk.PaymentReference,
+				Expected:    bk.TotalAmount,
+				Confidence:  score,
+				Reasons:     reasons,
+			})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].Confidence > candidates[j].Confidence })
+
+	usedTxn, usedBooking := map[string]bool{}, map[string]bool{}
+	var matches []*Match
+	for _, m := range candidates {
+		if usedTxn[m.Transaction.ID] || usedBooking[m.BookingID] {
+			continue
+		}
+		usedTxn[m.Transaction.ID], usedBooking[m.BookingID] = true, true
+		matches = append(matches, m)
+	}
+
+	var unmatched []Transaction
+	for _, t := range txns {
+		if !usedTxn[t.ID] {
+			unmatched = append(unmatched, t)
+		}
+	}
+	return matches, unmatched
+}
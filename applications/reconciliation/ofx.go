@@ -0,0 +1,50 @@
+package reconciliation
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	ofxTxnRe = regexp.MustCompile(`(?is)<STMTTRN>(.*?)(?:</STMTTRN>|<STMTTRN>|</BANKTRANLIST>)`)
+	ofxTagRe = regexp.MustCompile(`(?i)<(TRNTYPE|DTPOSTED|TRNAMT|FITID|NAME|MEMO|REFNUM)>([^<\r\n]*)`)
+)
+
+// parseOFX reads STMTTRN blocks. It handles both SGML (OFX 1.x, unclosed tags)
+// and XML (OFX 2.x) exports.
+func parseOFX(data []byte) ([]Transaction, error) {
+	blocks := ofxTxnRe.FindAllStringSubmatch(string(data), -1)
+	if len(blocks) == 0 {
+		return nil, fmt.Errorf("OFX statement contains no transactions")
+	}
+
+	var txns []Transaction
+	for i, b := range blocks {
+		fields := map[string]string{}
+		for _, m := range ofxTagRe.FindAllStringSubmatch(b[1], -1) {
+			fields[strings.ToUpper(m[1])] = strings.TrimSpace(m[2])
+		}
+
+		posted := fields["DTPOSTED"]
+		if len(posted) > 8 {
+			posted = posted[:8] // drop time and timezone, e.g. 20251012120000[+5.5:IST]
+		}
+		date, err := parseStatementDate(posted)
+		if err != nil {
+			return nil, fmt.Errorf("OFX transaction %d: %w", i+1, err)
+		}
+		amount, err := parseStatementAmount(fields["TRNAMT"])
+		if err != nil {
+			return nil, fmt.Errorf("OFX transaction %d: %w", i+1, err)
+		}
+
+		id := fields["FITID"]
+		if id == "" {
+			id = fmt.Sprintf("ofx-%d", i+1)
+		}
+		narration := strings.TrimSpace(strings.Join([]string{fields["NAME"], fields["MEMO"], fields["REFNUM"]}, " "))
+		txns = append(txns, Transaction{ID: id, Date: date, Amount: amount, Narration: narration})
+	}
+	return txns, nil
+}
@@ -0,0 +1,73 @@
+package reconciliation
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"supra/applications/booking"
+	"supra/logger"
+)
+
+const defaultAutoApproveThreshold = 0.9
+
+// Report is the outcome of reconciling one statement.
+type Report struct {
+	Transactions int           `json:"transactions"`
+	Matches      []*Match      `json:"matches"`
+	Unmatched    []Transaction `json:"unmatched"`
+	AutoApplied  int           `json:"autoApplied"`
+	Threshold    float64       `json:"threshold"`
+}
+
+// AutoApproveThreshold reads RECONCILE_AUTO_APPROVE_THRESHOLD (default 0.9).
+func AutoApproveThreshold() float64 {
+	if v, err := strconv.ParseFloat(os.Getenv("RECONCILE_AUTO_APPROVE_THRESHOLD"), 64); err == nil && v > 0 {
+		return v
+	}
+	return defaultAutoApproveThreshold
+}
+
+// ReconcileStatementUC matches a bank statement against bookings awaiting
+// verification. With autoApply, matches at or above threshold are approved.
+func ReconcileStatementUC(filename string, data []byte, autoApply bool, threshold float64) (*Report, error) {
+	logger.Log.Info(fmt.Sprintf("[reconcile-statement-uc] Reconciling statement %q (%d bytes, autoApply=%t)", filename, len(data), autoApply))
+
+	txns, err := ParseStatement(filename, data)
+	if err != nil {
+		logger.Log.Warn(fmt.Sprintf("[reconcile-statement-uc] Failed to parse %q: %v", filename, err))
+		return nil, err
+	}
+
+	pending, err := booking.GetPendingBookingsUC()
+	if err != nil {
+		return nil, err
+	}
+
+	if threshold <= 0 {
+		threshold = AutoApproveThreshold()
+	}
+
+	matches, unmatched := matchTransactions(txns, pending)
+	report := &Report{Transactions: len(txns), Matches: matches, Unmatched: unmatched, Threshold: threshold}
+
+	if autoApply {
+		for _, m := range matches {
+			if m.Confidence < threshold {
+				continue
+			}
+			if _, err := booking.ApproveBookingUC(m.BookingID); err != nil {
+				m.ApplyError = err.Error()
+				logger.Log.Error(fmt.Sprintf("[reconcile-statement-uc] ❌ Auto-approve failed for %s: %v", m.BookingID, err))
+				continue
+			}
+			m.Applied = true
+			report.AutoApplied++
+			logger.Log.Info(fmt.Sprintf("[reconcile-statement-uc] ✅ Auto-approved %s from transaction %s (confidence %.2f)", m.BookingID, m.Transaction.ID, m.Confidence))
+		}
+	}
+
+	logger.Log.Info(fmt.Sprintf("[reconcile-statement-uc] %d transactions, %d matched, %d unmatched, %d auto-approved",
+		len(txns), len(matches), len(unmatched), report.AutoApplied))
+	return report, nil
+}
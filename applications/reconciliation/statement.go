@@ -0,0 +1,95 @@
+package reconciliation
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrUnknownFormat is returned when a statement is neither CSV, OFX nor CAMT.053.
+var ErrUnknownFormat = errors.New("unrecognised bank statement format (expected CSV, OFX or CAMT.053)")
+
+// Transaction is one credit line from a bank statement.
+type Transaction struct {
+	ID        string    `json:"id"`
+	Date      time.Time `json:"date"`
+	Amount    float64   `json:"amount"`
+	Narration string    `json:"narration"`
+}
+
+// ParseStatement detects the export format and returns the credit transactions.
+func ParseStatement(filename string, data []byte) ([]Transaction, error) {
+	head := strings.ToUpper(string(data[:min(len(data), 4096)]))
+	ext := strings.ToLower(filepath.Ext(filename))
+
+	var (
+		txns []Transaction
+		err  error
+	)
+	switch {
+	case strings.Contains(head, "BKTOCSTMRSTMT"):
+		txns, err = parseCAMT053(data)
+	case strings.Contains(head, "<OFX>") || strings.Contains(head, "OFXHEADER") || ext == ".ofx":
+		txns, err = parseOFX(data)
+	case ext == ".csv" || ext == ".txt" || bytes.ContainsAny(data[:min(len(data), 1024)], ",;\t"):
+		txns, err = parseCSV(data)
+	default:
+		return nil, ErrUnknownFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	credits := txns[:0]
+	for _, t := range txns {
+		if t.Amount > 0 {
+			credits = append(credits, t)
+		}
+	}
+	return credits, nil
+}
+
+var statementDateLayouts = []string{
+	"2006-01-02", "2006-01-02T15:04:05", "2006-01-02 15:04:05", time.RFC3339,
+	"02/01/2006", "02-01-2006", "02.01.2006", "02/01/06", "02-01-06",
+	"02 Jan 2006", "02-Jan-2006", "02-Jan-06", "Jan 02, 2006",
+	"20060102", "20060102150405",
+}
+
+func parseStatementDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range statementDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognised date %q", s)
+}
+
+// parseStatementAmount accepts "1,500.00", "₹1500", "1500 CR" and "(1500)" style values.
+func parseStatementAmount(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	negative := strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") ||
+		strings.HasSuffix(strings.ToUpper(s), "DR")
+	cleaned := strings.Map(func(r rune) rune {
+		if (r >= '0' && r <= '9') || r == '.' || r == '-' {
+			return r
+		}
+		return -1
+	}, s)
+	if cleaned == "" {
+		return 0, nil
+	}
+	v, err := strconv.ParseFloat(cleaned, 64)
+	if err != nil {
+		return 0, fmt.Errorf("unrecognised amount %q", s)
+	}
+	if negative && v > 0 {
+		v = -v
+	}
+	return v, nil
+}
@@ -0,0 +1,47 @@
+package refund
+
+import (
+	"fmt"
+
+	"supra/db"
+	"supra/logger"
+)
+
+// GetRefundsUC lists refunds for admins, optionally filtered by status.
+func GetRefundsUC(status string) ([]*Refund, error) {
+	logger.Log.Info(fmt.Sprintf("[get-refunds-uc] Listing refunds (status=%q)", status))
+
+	query := `SELECT ` + refundColumns + ` FROM refund`
+	var args []interface{}
+	if status != "" {
+		query += ` WHERE status = $1`
+		args = append(args, status)
+	}
+	query += ` ORDER BY created_at DESC`
+
+	return queryRefunds(query, args...)
+}
+
+// GetBookingRefundsUC lists the refunds of one booking.
+func GetBookingRefundsUC(bookingID string) ([]*Refund, error) {
+	return queryRefunds(`SELECT `+refundColumns+` FROM refund WHERE booking_id = $1 ORDER BY created_at DESC`, bookingID)
+}
+
+func queryRefunds(query string, args ...interface{}) ([]*Refund, error) {
+	rows, err := db.DB.Query(query, args...)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("[get-refunds-uc] Query failed: %v", err))
+		return nil, fmt.Errorf("failed to fetch refunds: %w", err)
+	}
+	defer rows.Close()
+
+	refunds := []*Refund{}
+	for rows.Next() {
+		r, err := scanRefund(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan refund: %w", err)
+		}
+		refunds = append(refunds, r)
+	}
+	return refunds, rows.Err()
+}
@@ -0,0 +1,125 @@
+package refund
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"supra/applications/booking"
+	"supra/applications/invoice"
+	"supra/applications/seat"
+	"supra/db"
+	"supra/logger"
+)
+
+type ProcessRefundParams struct {
+	Amount    *float64 `json:"amount,omitempty"`    // approve: override the policy amount
+	Reference string   `json:"reference,omitempty"` // paid: payout transaction reference
+	Note      string   `json:"note,omitempty"`
+}
+
+// ProcessRefundUC moves a refund through the admin steps: approve, deny or paid.
+func ProcessRefundUC(refundID, action string, payload []byte) (*Refund, error) {
+	logger.Log.Info(fmt.Sprintf("[process-refund-uc] %s requested for refund %s", action, refundID))
+
+	var p ProcessRefundParams
+	if len(payload) > 0 {
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return nil, fmt.Errorf("invalid payload: %w", err)
+		}
+	}
+
+	var from, to string
+	switch strings.ToLower(action) {
+	case "approve":
+		from, to = REQUESTED, APPROVED
+	case "deny":
+		from, to = REQUESTED, DENIED
+	case "paid":
+		from, to = APPROVED, PAID
+	default:
+		return nil, fmt.Errorf("invalid action %q; must be approve, deny or paid", action)
+	}
+
+	tx, err := db.DB.BeginTx(context.Background(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	r, err := scanRefund(tx.QueryRow(`SELECT `+refundColumns+` FROM refund WHERE refund_id = $1 FOR UPDATE`, refundID))
+	if err != nil {
+		return nil, err
+	}
+	if r.Status != from {
+		return nil, fmt.Errorf("%w: cannot %s a %s refund", ErrInvalidTransition, action, r.Status)
+	}
+
+	var bk *booking.Booking
+	if to == APPROVED {
+		if bk, err = booking.GetBookingTx(tx, r.BookingID); err != nil {
+			return nil, fmt.Errorf("failed to load booking %s: %w", r.BookingID, err)
+		}
+	}
+	if p.Amount != nil {
+		if to != APPROVED || *p.Amount <= 0 {
+			return nil, fmt.Errorf("amount can only be set (to a positive value) when approving")
+		}
+		if *p.Amount > bk.TotalAmount {
+			return nil, fmt.Errorf("invalid amount: %.2f exceeds the booking total of %.2f", *p.Amount, bk.TotalAmount)
+		}
+		r.Amount = *p.Amount
+	}
+	if p.Reference != "" {
+		r.Reference = p.Reference
+	}
+	if p.Note != "" {
+		r.AdminNote = p.Note
+	}
+	r.Status = to
+	r.UpdatedAt = time.Now()
+
+	if _, err := tx.Exec(`
+		UPDATE refund
+		SET status = $2, amount = $3, reference = NULLIF($4, ''), admin_note = NULLIF($5, ''), updated_at = $6
+		WHERE refund_id = $1`,
+		r.RefundID, r.Status, r.Amount, r.Reference, r.AdminNote, r.UpdatedAt); err != nil {
+		return nil, fmt.Errorf("failed to update refund: %w", err)
+	}
+	// An approved refund releases the booking and its seats, in the same
+	// transaction so the refund is never approved for a live booking.
+	if to == APPROVED {
+		if _, err := booking.CancelBookingTx(tx, r.BookingID); err != nil && !errors.Is(err, booking.ErrAlreadyCancelled) {
+			logger.Log.Error(fmt.Sprintf("[refund] ❌ Failed to cancel refunded booking %s: %v", r.BookingID, err))
+			return nil, fmt.Errorf("failed to cancel booking %s: %w", r.BookingID, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit failed: %w", err)
+	}
+	logger.Log.Info(fmt.Sprintf("[process-refund-uc] ✅ Refund %s is now %s", r.RefundID, r.Status))
+
+	if to == APPROVED {
+		go seat.ProcessWaitlist(bk.SeatID)
+	}
+	// A paid refund is documented with a credit note against the invoice.
+	if to == PAID {
+		issueCreditNote(r)
+	}
+
+	go notifyRefund(r, p.Note)
+	return r, nil
+}
+
+func issueCreditNote(r *Refund) {
+	_, err := invoice.IssueCreditNoteUC(r.BookingID, r.RefundID, r.Amount, r.Reason)
+	switch {
+	case errors.Is(err, invoice.ErrNoInvoice):
+		logger.Log.Warn(fmt.Sprintf("[refund] ⚠️ Booking %s has no invoice; no credit note for refund %s", r.BookingID, r.RefundID))
+	case err != nil:
+		logger.Log.Error(fmt.Sprintf("[refund] ❌ Failed to issue credit note for refund %s: %v", r.RefundID, err))
+	}
+}
@@ -0,0 +1,76 @@
+package refund
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+)
+
+type Refund struct {
+	RefundID    string    `json:"refundID"`
+	BookingID   string    `json:"bookingID"`
+	ConcertID   string    `json:"concertID"`
+	Email       string    `json:"email"`
+	Amount      float64   `json:"amount"`
+	Method      string    `json:"method"`
+	PayoutTo    string    `json:"payoutTo,omitempty"` // UPI ID / account details for manual payouts
+	Status      string    `json:"status"`
+	Reason      string    `json:"reason,omitempty"`
+	AdminNote   string    `json:"adminNote,omitempty"`
+	Reference   string    `json:"reference,omitempty"` // payout transaction reference
+	RequestedBy string    `json:"requestedBy"`         // USER or ADMIN
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+const (
+	REQUESTED = "REQUESTED"
+	APPROVED  = "APPROVED"
+	PAID      = "PAID"
+	DENIED    = "DENIED"
+)
+
+// Refund methods
+const (
+	MethodOriginal = "ORIGINAL" // back to the source (gateway or the account that paid)
+	MethodUPI      = "UPI"
+	MethodBank     = "BANK_TRANSFER"
+)
+
+var (
+	ErrRefundNotFound    = errors.New("refund not found")
+	ErrInvalidTransition = errors.New("invalid refund status transition")
+	ErrRefundExists      = errors.New("an active refund already exists for this booking")
+	ErrNotRefundable     = errors.New("booking is not eligible for a refund")
+	ErrInvalidMethod     = errors.New("invalid refund method")
+)
+
+func validMethod(m string) bool {
+	switch m {
+	case MethodOriginal, MethodUPI, MethodBank:
+		return true
+	}
+	return false
+}
+
+const refundColumns = `
+	refund_id, booking_id, concert_id, email, amount, method, COALESCE(payout_to, ''), status,
+	COALESCE(reason, ''), COALESCE(admin_note, ''), COALESCE(reference, ''), requested_by,
+	created_at, updated_at`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanRefund(row rowScanner) (*Refund, error) {
+	r := &Refund{}
+	err := row.Scan(
+		&r.RefundID, &r.BookingID, &r.ConcertID, &r.Email, &r.Amount, &r.Method, &r.PayoutTo, &r.Status,
+		&r.Reason, &r.AdminNote, &r.Reference, &r.RequestedBy,
+		&r.CreatedAt, &r.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrRefundNotFound
+	}
+	return r, err
+}
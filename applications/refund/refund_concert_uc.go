@@ -0,0 +1,88 @@
+package refund
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"supra/applications/booking"
+	"supra/applications/seat"
+	"supra/db"
+	"supra/logger"
+
+	"github.com/google/uuid"
+)
+
+// RefundConcertUC issues full refunds for every paid booking of a cancelled
+// concert. Bookings that already have an active refund are skipped.
+func RefundConcertUC(concertID, note string) ([]*Refund, error) {
+	logger.Log.Info(fmt.Sprintf("[refund-concert-uc] Issuing refunds for concert %s", concertID))
+
+	bookings, err := booking.GetAllBookingsByConcertID(concertID)
+	if err != nil {
+		return nil, err
+	}
+
+	var issued []*Refund
+	for _, bk := range bookings {
+		if bk.BookingStatus != booking.APPROVED && bk.BookingStatus != booking.CONFIRMED {
+			continue
+		}
+
+		r := &Refund{
+			RefundID:    uuid.NewString(),
+			BookingID:   bk.BookingID.String(),
+			ConcertID:   concertID,
+			Email:       bk.BookingEmail,
+			Amount:      bk.TotalAmount,
+			Method:      MethodOriginal,
+			Status:      APPROVED,
+			Reason:      "Concert cancelled",
+			AdminNote:   note,
+			RequestedBy: "ADMIN",
+			CreatedAt:   time.Now(),
+		}
+		r.UpdatedAt = r.CreatedAt
+		created, err := approveConcertRefund(r)
+		if err != nil {
+			return issued, err
+		}
+		if !created {
+			logger.Log.Info(fmt.Sprintf("[refund-concert-uc] Booking %s already has an active refund; skipping.", r.BookingID))
+			continue
+		}
+
+		go seat.ProcessWaitlist(bk.SeatID)
+		go notifyRefund(r, note)
+		issued = append(issued, r)
+	}
+
+	logger.Log.Info(fmt.Sprintf("[refund-concert-uc] ✅ %d refunds issued for concert %s", len(issued), concertID))
+	return issued, nil
+}
+
+// approveConcertRefund saves the approved refund and cancels its booking in
+// one transaction. It reports false when the booking already has a refund.
+func approveConcertRefund(r *Refund) (bool, error) {
+	tx, err := db.DB.BeginTx(context.Background(), nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := insertRefund(tx, r); err != nil {
+		if err == ErrRefundExists {
+			return false, nil
+		}
+		return false, err
+	}
+	if _, err := booking.CancelBookingTx(tx, r.BookingID); err != nil && !errors.Is(err, booking.ErrAlreadyCancelled) {
+		logger.Log.Error(fmt.Sprintf("[refund-concert-uc] ❌ Failed to cancel refunded booking %s: %v", r.BookingID, err))
+		return false, fmt.Errorf("failed to cancel booking %s: %w", r.BookingID, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("commit failed: %w", err)
+	}
+	return true, nil
+}
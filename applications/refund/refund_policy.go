@@ -0,0 +1,50 @@
+package refund
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"supra/applications/auth"
+	"supra/applications/booking"
+	"supra/db"
+
+	"github.com/google/uuid"
+)
+
+// CheckRefundOwner is the auth.OwnerCheck for routes keyed by :refundID.
+func CheckRefundOwner(refundID string, p auth.Principal) error {
+	id, err := uuid.Parse(refundID)
+	if err != nil {
+		return fmt.Errorf("invalid refund ID: %w", err)
+	}
+
+	var bookingID string
+	err = db.DB.QueryRow(`SELECT booking_id FROM refund WHERE refund_id = $1`, id).Scan(&bookingID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrRefundNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to look up refund owner: %w", err)
+	}
+	// A refund belongs to whoever owns its booking.
+	return booking.CheckBookingOwner(bookingID, p)
+}
+
+// ConcertIDOf returns the concert of a refund's booking, for scoping staff
+// permissions on routes keyed by :refundID.
+func ConcertIDOf(refundID string) (string, error) {
+	id, err := uuid.Parse(refundID)
+	if err != nil {
+		return "", fmt.Errorf("invalid refund ID: %w", err)
+	}
+	var bookingID string
+	err = db.DB.QueryRow(`SELECT booking_id FROM refund WHERE refund_id = $1`, id).Scan(&bookingID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrRefundNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to look up refund booking: %w", err)
+	}
+	return booking.ConcertIDOf(bookingID)
+}
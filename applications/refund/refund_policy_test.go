@@ -0,0 +1,72 @@
+package refund_test
+
+import (
+	"database/sql/driver"
+	"net/http"
+	"strings"
+	"testing"
+
+	"supra/applications/auth"
+	"supra/applications/auth/authtest"
+	"supra/applications/refund"
+	"supra/db/dbtest"
+)
+
+const (
+	aliceRefund   = "a1b2c3d4-e5f6-4789-8abc-def012345678"
+	guestRefund   = "b2c3d4e5-f607-4890-9bcd-ef0123456789"
+	missingRefund = "c3d4e5f6-0718-49a1-acde-f01234567890"
+)
+
+// fakeRefundDB serves a refund on alice's booking and one on a booking made
+// by guest before they had an account.
+func fakeRefundDB(query string, args []driver.Value) (*dbtest.Rows, error) {
+	switch {
+	case strings.Contains(query, "SELECT booking_id FROM refund"):
+		refunds := map[string]string{aliceRefund: dbtest.AliceBooking, guestRefund: dbtest.GuestBooking}
+		bookingID, ok := refunds[args[0].(string)]
+		if !ok {
+			return dbtest.NoRows("booking_id"), nil
+		}
+		return dbtest.Row([]string{"booking_id"}, bookingID), nil
+
+	case strings.Contains(query, "SELECT booking_email, COALESCE(user_id::text, '') FROM booking"):
+		cols := []string{"booking_email", "user_id"}
+		switch args[0].(string) {
+		case dbtest.AliceBooking:
+			return dbtest.Row(cols, "alice@example.com", dbtest.AliceID), nil
+		case dbtest.GuestBooking:
+			return dbtest.Row(cols, "guest@example.com", ""), nil
+		}
+		return dbtest.NoRows(cols...), nil
+	}
+	return nil, nil
+}
+
+func TestOwnerOrAdminRefund(t *testing.T) {
+	dbtest.Use(t, fakeRefundDB)
+	mw := auth.OwnerOrAdmin("refundID", refund.CheckRefundOwner)
+
+	tests := []struct {
+		name     string
+		who      auth.Principal
+		refundID string
+		want     int
+	}{
+		{"booking owner", authtest.Alice, aliceRefund, http.StatusOK},
+		{"other user", authtest.Bob, aliceRefund, http.StatusForbidden},
+		{"admin bypasses", authtest.Admin, aliceRefund, http.StatusOK},
+		{"unclaimed booking by email", authtest.Guest, guestRefund, http.StatusOK},
+		{"unclaimed booking, other user", authtest.Alice, guestRefund, http.StatusForbidden},
+		{"unclaimed booking, admin", authtest.Admin, guestRefund, http.StatusOK},
+		{"missing refund", authtest.Alice, missingRefund, http.StatusNotFound},
+		{"malformed ID", authtest.Alice, "not-a-uuid", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := authtest.Serve(t, tt.who, "/refunds/:refundID", "/refunds/"+tt.refundID, mw); got != tt.want {
+				t.Errorf("status = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
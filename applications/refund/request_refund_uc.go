@@ -0,0 +1,126 @@
+package refund
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"supra/applications/auth"
+	"supra/applications/booking"
+	"supra/concert/application"
+	"supra/db"
+	"supra/logger"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type RequestRefundParams struct {
+	Method   string `json:"method"`
+	PayoutTo string `json:"payoutTo"`
+	Reason   string `json:"reason"`
+}
+
+// RequestRefundUC lets a customer ask for a refund of a paid booking, within
+// the concert's refund policy.
+func RequestRefundUC(bookingID string, payload []byte) (*Refund, error) {
+	logger.Log.Info(fmt.Sprintf("[request-refund-uc] Refund requested for booking %s", bookingID))
+
+	var p RequestRefundParams
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return nil, fmt.Errorf("invalid payload: %w", err)
+	}
+	p.Method = strings.ToUpper(strings.TrimSpace(p.Method))
+	if p.Method == "" {
+		p.Method = MethodOriginal
+	}
+	if !validMethod(p.Method) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidMethod, p.Method)
+	}
+	if p.Method != MethodOriginal && strings.TrimSpace(p.PayoutTo) == "" {
+		return nil, fmt.Errorf("payoutTo is required for %s refunds", p.Method)
+	}
+
+	bk, err := booking.GetBooking(bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if bk.BookingStatus != booking.APPROVED && bk.BookingStatus != booking.CONFIRMED {
+		return nil, fmt.Errorf("%w: status %s", ErrNotRefundable, bk.BookingStatus)
+	}
+
+	concert, err := application.NewGetConcertByIDUC(logger.Log).Invoke(bk.ConcertID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load concert: %w", err)
+	}
+	amount, err := concert.RefundPolicy.RefundAmount(bk.TotalAmount, time.Now())
+	if err != nil {
+		logger.Log.Warn(fmt.Sprintf("[request-refund-uc] Refund refused for %s: %v", bookingID, err))
+		return nil, err
+	}
+
+	r := &Refund{
+		RefundID:    uuid.NewString(),
+		BookingID:   bk.BookingID.String(),
+		ConcertID:   bk.ConcertID,
+		Email:       bk.BookingEmail,
+		Amount:      amount,
+		Method:      p.Method,
+		PayoutTo:    strings.TrimSpace(p.PayoutTo),
+		Status:      REQUESTED,
+		Reason:      p.Reason,
+		RequestedBy: "USER",
+		CreatedAt:   time.Now(),
+	}
+	r.UpdatedAt = r.CreatedAt
+	if err := insertRefund(db.DB, r); err != nil {
+		return nil, err
+	}
+	logger.Log.Info(fmt.Sprintf("[request-refund-uc] ✅ Refund %s (₹%.2f) requested for booking %s", r.RefundID, r.Amount, bookingID))
+
+	go notifyRefund(r, "")
+	if adminEmail := os.Getenv("ADMIN_EMAIL"); adminEmail != "" {
+		go func() {
+			if err := auth.SendRefundRequestNotification(adminEmail, r.BookingID, r.RefundID, r.Email, r.Amount, r.Method, r.Reason); err != nil {
+				logger.Log.Error(fmt.Sprintf("[request-refund-uc] ❌ Failed to notify admin: %v", err))
+			}
+		}()
+	}
+	return r, nil
+}
+
+// execer is satisfied by both *sql.DB and *sql.Tx.
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+func insertRefund(ex execer, r *Refund) error {
+	_, err := ex.Exec(`
+		INSERT INTO refund (
+			refund_id, booking_id, concert_id, email, amount, method, payout_to, status,
+			reason, admin_note, requested_by, created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8, NULLIF($9, ''), NULLIF($10, ''), $11, $12, $13)`,
+		r.RefundID, r.BookingID, r.ConcertID, r.Email, r.Amount, r.Method, r.PayoutTo, r.Status,
+		r.Reason, r.AdminNote, r.RequestedBy, r.CreatedAt, r.UpdatedAt)
+	if err != nil {
+		// The partial unique index allows one active refund per booking.
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return ErrRefundExists
+		}
+		logger.Log.Error(fmt.Sprintf("[refund] Insert failed for booking %s: %v", r.BookingID, err))
+		return fmt.Errorf("failed to save refund: %w", err)
+	}
+	return nil
+}
+
+func notifyRefund(r *Refund, note string) {
+	if err := auth.SendRefundStatusMail(r.Email, r.BookingID, r.RefundID, r.Status, r.Amount, note); err != nil {
+		logger.Log.Error(fmt.Sprintf("[refund] ❌ Failed to send %s email for refund %s: %v", r.Status, r.RefundID, err))
+	}
+}
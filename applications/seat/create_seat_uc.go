@@ -6,6 +6,7 @@ import (
 
 	"supra/db"     // Using the correct module path
 	"supra/logger" // ⬅️ Assuming this import path
+	"supra/validation"
 
 	"github.com/google/uuid"
 )
@@ -13,11 +14,11 @@ import (
 // NOTE: The Seat struct is assumed to be defined elsewhere in this package.
 
 type CreateSeatParams struct {
-	SeatType  string  `json:"seatType" validate:"requried"`
-	PriceGel  float64 `json:"priceGel" validate:"requried"`
-	PriceInr  float64 `json:"priceInr" validate:"requried"`
-	Available int     `json:"available"`
-	Notes     string  `json:"notes,omitempty"`
+	SeatType  string  `json:"seatType" validate:"required,max=50"`
+	PriceGel  float64 `json:"priceGel" validate:"required,gt=0"`
+	PriceInr  float64 `json:"priceInr" validate:"required,gt=0"`
+	Available int     `json:"available" validate:"gte=0"`
+	Notes     string  `json:"notes,omitempty" validate:"max=500"`
 }
 
 func AddSeat(payload []byte) (*Seat, error) {
@@ -30,6 +31,13 @@ func AddSeat(payload []byte) (*Seat, error) {
 		logger.Log.Error(fmt.Sprintf("[create-seat-uc] Failed to unmarshal payload: %v", err))
 		return nil, fmt.Errorf("failed to unmarshal payload: %w", err)
 	}
+	if p == nil {
+		return nil, fmt.Errorf("invalid payload: empty seat")
+	}
+	if err := validation.Struct(p); err != nil {
+		logger.Log.Warn(fmt.Sprintf("[create-seat-uc] Invalid seat request: %v", err))
+		return nil, err
+	}
 
 	newID := uuid.New().String()
 	logger.Log.Info(fmt.Sprintf("[create-seat-uc] Generated new SeatID: %s for type: %s", newID, p.SeatType))
@@ -63,6 +63,10 @@ func GetAllSeats() ([]*Seat, error) {
 		return nil, fmt.Errorf("error during row iteration: %w", err)
 	}
 
+	for _, st := range seats {
+		st.attachPricing()
+	}
+
 	logger.Log.Info(fmt.Sprintf("[get-all-seat-uc] Successfully retrieved %d seat records.", recordCount))
 	// 6. Return the slice of seats
 	return seats, nil
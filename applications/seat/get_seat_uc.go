@@ -56,6 +56,8 @@ func GetSeat(seatID string) (*Seat, error) {
 		return nil, fmt.Errorf("database query error: %w", err)
 	}
 
+	st.attachPricing()
+
 	logger.Log.Info(fmt.Sprintf("[get-seat-uc] Seat %s retrieved successfully. Available: %d", seatID, st.Available))
 	// 7. Return the retrieved seat details
 	return st, nil
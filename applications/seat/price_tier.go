@@ -0,0 +1,242 @@
+package seat
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"supra/db"
+	"supra/logger"
+	"supra/validation"
+
+	"github.com/google/uuid"
+)
+
+// PriceTier is one price step of a seat category, e.g. early bird, regular or
+// door price. A tier is open while now is inside [StartsAt, EndsAt) and,
+// when MaxTickets is set, while fewer than MaxTickets seats of the category
+// are sold or held. The first open tier by Position wins.
+type PriceTier struct {
+	TierID     string     `json:"tierID"`
+	SeatID     string     `json:"seatID"`
+	Name       string     `json:"name" validate:"required,max=50"`
+	PriceInr   float64    `json:"priceInr" validate:"gte=0"`
+	PriceGel   float64    `json:"priceGel" validate:"gte=0"`
+	StartsAt   *time.Time `json:"startsAt,omitempty"`
+	EndsAt     *time.Time `json:"endsAt,omitempty"`
+	MaxTickets int        `json:"maxTickets,omitempty" validate:"gte=0"` // 0 = no ticket cap
+	Position   int        `json:"position"`
+}
+
+// PriceChange tells clients when the current price stops applying.
+type PriceChange struct {
+	Tier        string     `json:"tier"`
+	PriceInr    float64    `json:"priceInr"`
+	PriceGel    float64    `json:"priceGel"`
+	At          *time.Time `json:"at,omitempty"`          // time-based change
+	TicketsLeft int        `json:"ticketsLeft,omitempty"` // quantity-based change
+}
+
+// StandardTier is reported for seats without configured tiers.
+const StandardTier = "STANDARD"
+
+type queryer interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func (t *PriceTier) openAt(now time.Time, sold, qty int) bool {
+	if t.StartsAt != nil && now.Before(*t.StartsAt) {
+		return false
+	}
+	if t.EndsAt != nil && !now.Before(*t.EndsAt) {
+		return false
+	}
+	return t.MaxTickets == 0 || sold+qty <= t.MaxTickets
+}
+
+func selectTier(tiers []*PriceTier, now time.Time, sold, qty int) *PriceTier {
+	for _, t := range tiers {
+		if t.openAt(now, sold, qty) {
+			return t
+		}
+	}
+	return nil
+}
+
+// nextChange finds the tier that follows current and when it takes over.
+func nextChange(tiers []*PriceTier, current *PriceTier, now time.Time, sold int) *PriceChange {
+	var next *PriceTier
+	for _, t := range tiers {
+		if t.Position > current.Position && (t.EndsAt == nil || now.Before(*t.EndsAt)) {
+			next = t
+			break
+		}
+	}
+	if next == nil {
+		return nil
+	}
+
+	pc := &PriceChange{Tier: next.Name, PriceInr: next.PriceInr, PriceGel: next.PriceGel}
+	at := current.EndsAt
+	if next.StartsAt != nil && next.StartsAt.After(now) && (at == nil || next.StartsAt.Before(*at)) {
+		at = next.StartsAt
+	}
+	pc.At = at
+	if current.MaxTickets > 0 {
+		pc.TicketsLeft = current.MaxTickets - sold
+	}
+	if pc.At == nil && pc.TicketsLeft == 0 {
+		return nil
+	}
+	return pc
+}
+
+func loadTiers(q queryer, seatID string) ([]*PriceTier, error) {
+	rows, err := q.Query(`
+		SELECT tier_id, seat_id, name, price_inr, price_gel, starts_at, ends_at, max_tickets, position
+		FROM seat_price_tier
+		WHERE seat_id = $1
+		ORDER BY position`, seatID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load price tiers: %w", err)
+	}
+	defer rows.Close()
+
+	tiers := make([]*PriceTier, 0)
+	for rows.Next() {
+		t := &PriceTier{}
+		var starts, ends sql.NullTime
+		if err := rows.Scan(&t.TierID, &t.SeatID, &t.Name, &t.PriceInr, &t.PriceGel, &starts, &ends, &t.MaxTickets, &t.Position); err != nil {
+			return nil, fmt.Errorf("error scanning price tier: %w", err)
+		}
+		if starts.Valid {
+			t.StartsAt = &starts.Time
+		}
+		if ends.Valid {
+			t.EndsAt = &ends.Time
+		}
+		tiers = append(tiers, t)
+	}
+	return tiers, rows.Err()
+}
+
+// soldCount is the number of seats of a category taken by live bookings plus
+// active holds; it drives "first N tickets" tiers.
+func soldCount(q queryer, seatID string) (int, error) {
+	var sold int
+	err := q.QueryRow(`
+		SELECT
+			(SELECT COALESCE(SUM(seat_quantity), 0) FROM booking
+			  WHERE seat_id = $1 AND booking_status NOT IN ('CANCELLED', 'REJECTED'))
+		  + (SELECT COALESCE(SUM(quantity), 0) FROM seat_hold
+			  WHERE seat_id::text = $1 AND status = 'ACTIVE' AND expires_at > now())`, seatID).Scan(&sold)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count sold seats: %w", err)
+	}
+	return sold, nil
+}
+
+// CurrentPrice returns the tier that applies to buying qty seats now and the
+// next scheduled price change. Seats without tiers use their static prices.
+func CurrentPrice(q queryer, st *Seat, qty int, now time.Time) (*PriceTier, *PriceChange, error) {
+	standard := &PriceTier{SeatID: st.SeatID, Name: StandardTier, PriceInr: st.PriceInr, PriceGel: st.PriceGel}
+
+	tiers, err := loadTiers(q, st.SeatID)
+	if err != nil || len(tiers) == 0 {
+		return standard, nil, err
+	}
+	sold, err := soldCount(q, st.SeatID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	current := selectTier(tiers, now, sold, qty)
+	if current == nil {
+		return standard, nil, nil
+	}
+	return current, nextChange(tiers, current, now, sold), nil
+}
+
+// attachPricing fills the seat's active tier for listings. Failures only
+// leave the fields empty.
+func (st *Seat) attachPricing() {
+	tier, next, err := CurrentPrice(db.DB, st, 1, time.Now())
+	if err != nil {
+		logger.Log.Warn(fmt.Sprintf("[seat-pricing] Could not resolve price tier for %s: %v", st.SeatID, err))
+		return
+	}
+	st.ActiveTier = tier
+	st.NextPriceChange = next
+}
+
+// GetPriceTiers lists the configured tiers of a seat category.
+func GetPriceTiers(seatID string) ([]*PriceTier, error) {
+	id, err := uuid.Parse(seatID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid seat ID format: %w", err)
+	}
+	return loadTiers(db.DB, id.String())
+}
+
+// SetPriceTiers replaces all tiers of a seat category. Tier order in the
+// payload is the selection order.
+func SetPriceTiers(seatID string, payload []byte) ([]*PriceTier, error) {
+	logger.Log.Info(fmt.Sprintf("[seat-pricing] Replacing price tiers for SeatID: %s", seatID))
+
+	id, err := uuid.Parse(seatID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid seat ID format: %w", err)
+	}
+
+	var tiers []*PriceTier
+	if err := json.Unmarshal(payload, &tiers); err != nil {
+		return nil, fmt.Errorf("invalid price tiers payload: %w", err)
+	}
+	errs := validation.Check(struct {
+		Tiers []*PriceTier `json:"tiers"`
+	}{tiers})
+	for i, t := range tiers {
+		if t != nil && t.StartsAt != nil && t.EndsAt != nil && !t.EndsAt.After(*t.StartsAt) {
+			errs.Add(fmt.Sprintf("tiers[%d].endsAt", i), "must be after startsAt")
+		}
+	}
+	if err := errs.Err(); err != nil {
+		return nil, err
+	}
+	for i, t := range tiers {
+		t.TierID = uuid.New().String()
+		t.SeatID = id.String()
+		t.Position = i
+	}
+
+	tx, err := db.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := GetSeatForUpdateTx(tx, seatID); err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(`DELETE FROM seat_price_tier WHERE seat_id = $1`, id); err != nil {
+		return nil, fmt.Errorf("failed to clear price tiers: %w", err)
+	}
+	for _, t := range tiers {
+		_, err := tx.Exec(`
+			INSERT INTO seat_price_tier (tier_id, seat_id, name, price_inr, price_gel, starts_at, ends_at, max_tickets, position)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+			t.TierID, t.SeatID, t.Name, t.PriceInr, t.PriceGel, t.StartsAt, t.EndsAt, t.MaxTickets, t.Position)
+		if err != nil {
+			logger.Log.Error(fmt.Sprintf("[seat-pricing] Failed to insert tier %s for %s: %v", t.Name, seatID, err))
+			return nil, fmt.Errorf("failed to insert price tier: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	logger.Log.Info(fmt.Sprintf("[seat-pricing] %d price tiers set for SeatID: %s", len(tiers), seatID))
+	return tiers, nil
+}
@@ -2,9 +2,13 @@ package seat
 
 type Seat struct {
 	SeatID    string  `json:"seatID" validate:"required"`
-	SeatType  string  `json:"seatType" validate:"requried"`
-	PriceGel  float64 `json:"priceGel" validate:"requried"`
-	PriceInr  float64 `json:"priceInr" validate:"requried"`
+	SeatType  string  `json:"seatType" validate:"required"`
+	PriceGel  float64 `json:"priceGel" validate:"required"`
+	PriceInr  float64 `json:"priceInr" validate:"required"`
 	Available int     `json:"available"`
 	Notes     string  `json:"notes,omitempty"`
+
+	// ActiveTier is the price that applies right now; NextPriceChange says when it changes.
+	ActiveTier      *PriceTier   `json:"activeTier,omitempty"`
+	NextPriceChange *PriceChange `json:"nextPriceChange,omitempty"`
 }
@@ -0,0 +1,204 @@
+package seat
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"supra/db"
+	"supra/logger"
+	"supra/validation"
+
+	"github.com/google/uuid"
+)
+
+// Hold reserves seats for a short time and locks in the price that was active
+// when it was taken, so the customer can pay and upload a receipt without
+// the tier changing underneath them.
+type Hold struct {
+	HoldID    string    `json:"holdID"`
+	SeatID    string    `json:"seatID"`
+	Email     string    `json:"email"`
+	Quantity  int       `json:"quantity"`
+	Tier      string    `json:"tier"`
+	PriceInr  float64   `json:"priceInr"` // unit price
+	PriceGel  float64   `json:"priceGel"` // unit price
+	Status    string    `json:"status"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+const (
+	HoldActive   = "ACTIVE"
+	HoldConsumed = "CONSUMED"
+	HoldReleased = "RELEASED"
+	HoldExpired  = "EXPIRED"
+)
+
+var (
+	ErrSeatsUnavailable = errors.New("not enough seats available to hold")
+	ErrHoldNotFound     = errors.New("seat hold not found")
+	ErrHoldExpired      = errors.New("seat hold has expired or was already used")
+	ErrHoldMismatch     = errors.New("seat hold does not match this booking")
+)
+
+// HoldTTL reads SEAT_HOLD_TTL (a Go duration, default 15m).
+func HoldTTL() time.Duration {
+	if d, err := time.ParseDuration(os.Getenv("SEAT_HOLD_TTL")); err == nil && d > 0 {
+		return d
+	}
+	return 15 * time.Minute
+}
+
+const holdColumns = `hold_id, seat_id, email, quantity, tier, price_inr, price_gel, status, expires_at, created_at`
+
+func scanHold(row *sql.Row) (*Hold, error) {
+	h := &Hold{}
+	err := row.Scan(&h.HoldID, &h.SeatID, &h.Email, &h.Quantity, &h.Tier, &h.PriceInr, &h.PriceGel, &h.Status, &h.ExpiresAt, &h.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrHoldNotFound
+	}
+	return h, err
+}
+
+// CreateHold reserves quantity seats of a category for email.
+func CreateHold(seatID, email string, payload []byte) (*Hold, error) {
+	logger.Log.Info(fmt.Sprintf("[seat-hold-uc] Hold requested on SeatID %s by %s", seatID, email))
+
+	var p struct {
+		Quantity int `json:"quantity" validate:"required,gte=1"`
+	}
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return nil, fmt.Errorf("invalid hold payload: %w", err)
+	}
+	if err := validation.Struct(&p); err != nil {
+		return nil, err
+	}
+
+	tx, err := db.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	st, err := GetSeatForUpdateTx(tx, seatID)
+	if err != nil {
+		return nil, err
+	}
+
+	free, err := freeSeatsTx(tx, st, "")
+	if err != nil {
+		return nil, err
+	}
+	if free < p.Quantity {
+		return nil, fmt.Errorf("%w: requested %d, free %d", ErrSeatsUnavailable, p.Quantity, free)
+	}
+
+	h, err := insertHoldTx(tx, st, email, p.Quantity)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	logger.Log.Info(fmt.Sprintf("[seat-hold-uc] Hold %s: %d x %s at %.2f INR until %s", h.HoldID, h.Quantity, h.Tier, h.PriceInr, h.ExpiresAt.Format(time.RFC3339)))
+	return h, nil
+}
+
+// freeSeatsTx is the seat's availability minus active holds and outstanding
+// waitlist offers (other than excludeOffer, the offer being claimed).
+func freeSeatsTx(tx *sql.Tx, st *Seat, excludeOffer string) (int, error) {
+	var reserved int
+	err := tx.QueryRow(`
+		SELECT
+			(SELECT COALESCE(SUM(quantity), 0) FROM seat_hold
+			  WHERE seat_id = $1 AND status = 'ACTIVE' AND expires_at > now())
+		  + (SELECT COALESCE(SUM(quantity), 0) FROM seat_waitlist
+			  WHERE seat_id = $1 AND status = 'OFFERED' AND offer_expires_at > now()
+			    AND entry_id::text <> $2)`, st.SeatID, excludeOffer).Scan(&reserved)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count reserved seats: %w", err)
+	}
+	return st.Available - reserved, nil
+}
+
+// FreeSeatsTx is the number of seats that can be booked without a hold: the
+// seat's availability minus active holds and outstanding waitlist offers.
+func FreeSeatsTx(tx *sql.Tx, st *Seat) (int, error) {
+	return freeSeatsTx(tx, st, "")
+}
+
+// insertHoldTx creates an active hold at the currently applicable tier price.
+func insertHoldTx(tx *sql.Tx, st *Seat, email string, qty int) (*Hold, error) {
+	now := time.Now()
+	tier, _, err := CurrentPrice(tx, st, qty, now)
+	if err != nil {
+		return nil, err
+	}
+
+	h := &Hold{
+		HoldID:    uuid.New().String(),
+		SeatID:    st.SeatID,
+		Email:     email,
+		Quantity:  qty,
+		Tier:      tier.Name,
+		PriceInr:  tier.PriceInr,
+		PriceGel:  tier.PriceGel,
+		Status:    HoldActive,
+		ExpiresAt: now.Add(HoldTTL()),
+		CreatedAt: now,
+	}
+	_, err = tx.Exec(`INSERT INTO seat_hold (`+holdColumns+`) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		h.HoldID, h.SeatID, h.Email, h.Quantity, h.Tier, h.PriceInr, h.PriceGel, h.Status, h.ExpiresAt, h.CreatedAt)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("[seat-hold-uc] Insert failed for SeatID %s: %v", st.SeatID, err))
+		return nil, fmt.Errorf("failed to create seat hold: %w", err)
+	}
+	return h, nil
+}
+
+// ReleaseHold gives held seats back before the hold expires.
+func ReleaseHold(holdID, email string) error {
+	var seatID string
+	err := db.DB.QueryRow(`
+		UPDATE seat_hold SET status = $3
+		WHERE hold_id = $1 AND lower(email) = lower($2) AND status = 'ACTIVE'
+		RETURNING seat_id`, holdID, email, HoldReleased).Scan(&seatID)
+	if err == sql.ErrNoRows {
+		return ErrHoldNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to release seat hold: %w", err)
+	}
+	logger.Log.Info(fmt.Sprintf("[seat-hold-uc] Hold %s released", holdID))
+	go ProcessWaitlist(seatID)
+	return nil
+}
+
+// ConsumeHoldTx turns an active hold into a booking. The hold must belong to
+// the same seat, email and quantity.
+func ConsumeHoldTx(tx *sql.Tx, holdID, seatID, email string, qty int) (*Hold, error) {
+	if _, err := uuid.Parse(holdID); err != nil {
+		return nil, fmt.Errorf("invalid hold ID format: %w", err)
+	}
+	h, err := scanHold(tx.QueryRow(`SELECT `+holdColumns+` FROM seat_hold WHERE hold_id = $1 FOR UPDATE`, holdID))
+	if err != nil {
+		return nil, err
+	}
+	if h.Status != HoldActive || !time.Now().Before(h.ExpiresAt) {
+		return nil, ErrHoldExpired
+	}
+	if h.SeatID != seatID || !strings.EqualFold(h.Email, email) || h.Quantity != qty {
+		return nil, ErrHoldMismatch
+	}
+	if _, err := tx.Exec(`UPDATE seat_hold SET status = $2 WHERE hold_id = $1`, holdID, HoldConsumed); err != nil {
+		return nil, fmt.Errorf("failed to consume seat hold: %w", err)
+	}
+	h.Status = HoldConsumed
+	return h, nil
+}
@@ -8,16 +8,17 @@ import (
 
 	"supra/db"     // Using the correct module path
 	"supra/logger" // ⬅️ Assuming this import path
+	"supra/validation"
 
 	"github.com/google/uuid"
 )
 
 type PartialUpdateSeatParams struct {
-	SeatType  string  `json:"seatType,omitempty"`
-	PriceGel  float64 `json:"priceGel,omitempty"`
-	PriceInr  float64 `json:"priceInr,omitempty"`
-	Available *int    `json:"available,omitempty"` // Use a pointer to distinguish 0 from 'not provided'
-	Notes     string  `json:"notes,omitempty"`
+	SeatType  string  `json:"seatType,omitempty" validate:"max=50"`
+	PriceGel  float64 `json:"priceGel,omitempty" validate:"gte=0"`
+	PriceInr  float64 `json:"priceInr,omitempty" validate:"gte=0"`
+	Available *int    `json:"available,omitempty" validate:"gte=0"` // Use a pointer to distinguish 0 from 'not provided'
+	Notes     string  `json:"notes,omitempty" validate:"max=500"`
 }
 
 // NOTE: The Seat struct and GetSeat function are assumed to be defined elsewhere in this package.
@@ -32,6 +33,10 @@ func UpdateSeat(seatID string, payload []byte) (*Seat, error) {
 		logger.Log.Error(fmt.Sprintf("[update-seat-uc] Failed to unmarshal update payload for %s: %v", seatID, err))
 		return nil, fmt.Errorf("failed to unmarshal payload: %w", err)
 	}
+	if err := validation.Struct(&p); err != nil {
+		logger.Log.Warn(fmt.Sprintf("[update-seat-uc] Invalid update for %s: %v", seatID, err))
+		return nil, err
+	}
 
 	// 1. Validate ID
 	id, err := uuid.Parse(seatID)
@@ -105,6 +110,10 @@ func UpdateSeat(seatID string, payload []byte) (*Seat, error) {
 	}
 
 	logger.Log.Info(fmt.Sprintf("[update-seat-uc] Seat %s updated successfully.", seatID))
+	// Restocked seats are offered to waitlisted users first.
+	if p.Available != nil {
+		go ProcessWaitlist(st.SeatID)
+	}
 	return st, nil
 }
 
@@ -0,0 +1,400 @@
+package seat
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"supra/applications/auth"
+	"supra/db"
+	"supra/logger"
+	"supra/validation"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// WaitlistEntry is a user waiting for seats of a sold-out category. When
+// seats are released the entry is OFFERED with a claim token; claiming it
+// turns the offer into a regular seat hold.
+type WaitlistEntry struct {
+	EntryID        string     `json:"entryID"`
+	SeatID         string     `json:"seatID"`
+	Email          string     `json:"email"`
+	Quantity       int        `json:"quantity"`
+	Status         string     `json:"status"`
+	OfferExpiresAt *time.Time `json:"offerExpiresAt,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+}
+
+const (
+	WaitlistWaiting = "WAITING"
+	WaitlistOffered = "OFFERED"
+	WaitlistClaimed = "CLAIMED"
+	WaitlistExpired = "EXPIRED"
+	WaitlistLeft    = "LEFT"
+)
+
+var (
+	ErrSeatsStillAvailable = errors.New("seats are still available; book them directly")
+	ErrAlreadyWaitlisted   = errors.New("already on the waitlist for this seat category")
+	ErrOfferNotFound       = errors.New("waitlist entry not found")
+	ErrOfferExpired        = errors.New("waitlist offer has expired")
+)
+
+// WaitlistClaimTTL reads WAITLIST_CLAIM_TTL (a Go duration, default 2h).
+func WaitlistClaimTTL() time.Duration {
+	if d, err := time.ParseDuration(os.Getenv("WAITLIST_CLAIM_TTL")); err == nil && d > 0 {
+		return d
+	}
+	return 2 * time.Hour
+}
+
+func claimURL(token string) string {
+	base := os.Getenv("WAITLIST_CLAIM_URL")
+	if base == "" {
+		base = "https://bkentertainments.vercel.app/waitlist/claim"
+	}
+	return fmt.Sprintf("%s?token=%s", base, token)
+}
+
+const waitlistColumns = `entry_id, seat_id, email, quantity, status, offer_expires_at, created_at`
+
+func scanWaitlistEntry(row interface{ Scan(...interface{}) error }) (*WaitlistEntry, error) {
+	e := &WaitlistEntry{}
+	var expires sql.NullTime
+	if err := row.Scan(&e.EntryID, &e.SeatID, &e.Email, &e.Quantity, &e.Status, &expires, &e.CreatedAt); err != nil {
+		return nil, err
+	}
+	if expires.Valid {
+		e.OfferExpiresAt = &expires.Time
+	}
+	return e, nil
+}
+
+// JoinWaitlist adds email to the waitlist of a category that cannot
+// currently supply quantity seats.
+func JoinWaitlist(seatID, email string, payload []byte) (*WaitlistEntry, error) {
+	logger.Log.Info(fmt.Sprintf("[waitlist-uc] %s joining waitlist for SeatID %s", email, seatID))
+
+	var p struct {
+		Quantity int `json:"quantity" validate:"required,gte=1"`
+	}
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return nil, fmt.Errorf("invalid waitlist payload: %w", err)
+	}
+	if err := validation.Struct(&p); err != nil {
+		return nil, err
+	}
+
+	tx, err := db.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	st, err := GetSeatForUpdateTx(tx, seatID)
+	if err != nil {
+		return nil, err
+	}
+	free, err := freeSeatsTx(tx, st, "")
+	if err != nil {
+		return nil, err
+	}
+	if free >= p.Quantity {
+		return nil, ErrSeatsStillAvailable
+	}
+
+	e := &WaitlistEntry{
+		EntryID:   uuid.New().String(),
+		SeatID:    st.SeatID,
+		Email:     email,
+		Quantity:  p.Quantity,
+		Status:    WaitlistWaiting,
+		CreatedAt: time.Now(),
+	}
+	_, err = tx.Exec(`INSERT INTO seat_waitlist (entry_id, seat_id, email, quantity, status, created_at) VALUES ($1, $2, $3, $4, $5, $6)`,
+		e.EntryID, e.SeatID, e.Email, e.Quantity, e.Status, e.CreatedAt)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return nil, ErrAlreadyWaitlisted
+		}
+		return nil, fmt.Errorf("failed to join waitlist: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	logger.Log.Info(fmt.Sprintf("[waitlist-uc] %s waitlisted for %d seats on %s", email, e.Quantity, e.SeatID))
+	return e, nil
+}
+
+// LeaveWaitlist removes the user's waiting or offered entry.
+func LeaveWaitlist(entryID, email string) error {
+	tx, err := db.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	e, err := scanWaitlistEntry(tx.QueryRow(`SELECT `+waitlistColumns+` FROM seat_waitlist
+		WHERE entry_id = $1 AND lower(email) = lower($2) AND status IN ('WAITING', 'OFFERED') FOR UPDATE`, entryID, email))
+	if err == sql.ErrNoRows {
+		return ErrOfferNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("database query error: %w", err)
+	}
+	if _, err := tx.Exec(`UPDATE seat_waitlist SET status = $2 WHERE entry_id = $1`, e.EntryID, WaitlistLeft); err != nil {
+		return fmt.Errorf("failed to leave waitlist: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit failed: %w", err)
+	}
+
+	logger.Log.Info(fmt.Sprintf("[waitlist-uc] %s left waitlist entry %s", email, e.EntryID))
+	// An abandoned offer frees its seats for the next person.
+	if e.Status == WaitlistOffered {
+		go ProcessWaitlist(e.SeatID)
+	}
+	return nil
+}
+
+// GetMyWaitlist lists the user's active waitlist entries.
+func GetMyWaitlist(email string) ([]*WaitlistEntry, error) {
+	rows, err := db.DB.Query(`SELECT `+waitlistColumns+` FROM seat_waitlist
+		WHERE lower(email) = lower($1) AND status IN ('WAITING', 'OFFERED') ORDER BY created_at`, email)
+	if err != nil {
+		return nil, fmt.Errorf("database query error: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]*WaitlistEntry, 0)
+	for rows.Next() {
+		e, err := scanWaitlistEntry(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning waitlist row: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+type waitlistOffer struct {
+	entry *WaitlistEntry
+	token string
+}
+
+// ProcessWaitlist offers freed seats of a category to waiting users in join
+// order. Entries asking for more seats than are free are skipped so smaller
+// requests behind them can still be served. Offers are emailed after commit.
+func ProcessWaitlist(seatID string) {
+	offers, seatType, err := offerFreedSeats(seatID)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("[waitlist-uc] ❌ Processing waitlist for %s failed: %v", seatID, err))
+		return
+	}
+	for _, o := range offers {
+		if err := auth.SendWaitlistOfferMail(o.entry.Email, seatType, o.entry.Quantity, claimURL(o.token), *o.entry.OfferExpiresAt); err != nil {
+			logger.Log.Error(fmt.Sprintf("[waitlist-uc] ❌ Failed to send offer %s: %v", o.entry.EntryID, err))
+		}
+	}
+}
+
+func offerFreedSeats(seatID string) ([]waitlistOffer, string, error) {
+	tx, err := db.DB.Begin()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	st, err := GetSeatForUpdateTx(tx, seatID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Lapsed offers go back into the pool before counting free seats.
+	if _, err := tx.Exec(`
+		UPDATE seat_waitlist SET status = $2
+		WHERE seat_id = $1 AND status = 'OFFERED' AND offer_expires_at <= now()`, st.SeatID, WaitlistExpired); err != nil {
+		return nil, "", fmt.Errorf("failed to expire offers: %w", err)
+	}
+
+	free, err := freeSeatsTx(tx, st, "")
+	if err != nil || free <= 0 {
+		return nil, st.SeatType, err
+	}
+
+	rows, err := tx.Query(`SELECT `+waitlistColumns+` FROM seat_waitlist
+		WHERE seat_id = $1 AND status = 'WAITING' ORDER BY created_at FOR UPDATE`, st.SeatID)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load waitlist: %w", err)
+	}
+	var waiting []*WaitlistEntry
+	for rows.Next() {
+		e, err := scanWaitlistEntry(rows)
+		if err != nil {
+			rows.Close()
+			return nil, "", fmt.Errorf("error scanning waitlist row: %w", err)
+		}
+		waiting = append(waiting, e)
+	}
+	rows.Close()
+
+	var offers []waitlistOffer
+	expires := time.Now().Add(WaitlistClaimTTL())
+	for _, e := range waiting {
+		if e.Quantity > free {
+			continue
+		}
+		token, err := newClaimToken()
+		if err != nil {
+			return nil, "", err
+		}
+		if _, err := tx.Exec(`
+			UPDATE seat_waitlist SET status = $2, claim_token = $3, offer_expires_at = $4
+			WHERE entry_id = $1`, e.EntryID, WaitlistOffered, token, expires); err != nil {
+			return nil, "", fmt.Errorf("failed to offer seats: %w", err)
+		}
+		e.Status, e.OfferExpiresAt = WaitlistOffered, &expires
+		offers = append(offers, waitlistOffer{entry: e, token: token})
+		free -= e.Quantity
+		if free == 0 {
+			break
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, "", fmt.Errorf("commit failed: %w", err)
+	}
+	if len(offers) > 0 {
+		logger.Log.Info(fmt.Sprintf("[waitlist-uc] Offered seats on %s to %d waitlisted users", st.SeatID, len(offers)))
+	}
+	return offers, st.SeatType, nil
+}
+
+func newClaimToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate claim token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// ClaimWaitlistOffer turns an unexpired offer into a seat hold for its owner.
+func ClaimWaitlistOffer(token, email string) (*Hold, error) {
+	tx, err := db.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	e, err := scanWaitlistEntry(tx.QueryRow(`SELECT `+waitlistColumns+` FROM seat_waitlist
+		WHERE claim_token = $1 AND status = 'OFFERED' FOR UPDATE`, token))
+	if err == sql.ErrNoRows {
+		return nil, ErrOfferNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("database query error: %w", err)
+	}
+	if !strings.EqualFold(e.Email, email) {
+		return nil, ErrOfferNotFound
+	}
+	if e.OfferExpiresAt == nil || !time.Now().Before(*e.OfferExpiresAt) {
+		return nil, ErrOfferExpired
+	}
+
+	st, err := GetSeatForUpdateTx(tx, e.SeatID)
+	if err != nil {
+		return nil, err
+	}
+	free, err := freeSeatsTx(tx, st, e.EntryID)
+	if err != nil {
+		return nil, err
+	}
+	if free < e.Quantity {
+		// Availability was lowered by an admin after the offer went out.
+		return nil, fmt.Errorf("%w: requested %d, free %d", ErrSeatsUnavailable, e.Quantity, free)
+	}
+
+	h, err := insertHoldTx(tx, st, e.Email, e.Quantity)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(`UPDATE seat_waitlist SET status = $2 WHERE entry_id = $1`, e.EntryID, WaitlistClaimed); err != nil {
+		return nil, fmt.Errorf("failed to mark offer claimed: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit failed: %w", err)
+	}
+
+	logger.Log.Info(fmt.Sprintf("[waitlist-uc] Offer %s claimed as hold %s", e.EntryID, h.HoldID))
+	return h, nil
+}
+
+// ExpireHoldsAndOffers marks lapsed holds and offers and re-runs the waitlist
+// of every affected category.
+func ExpireHoldsAndOffers() {
+	rows, err := db.DB.Query(`
+		WITH h AS (
+			UPDATE seat_hold SET status = $1 WHERE status = 'ACTIVE' AND expires_at <= now() RETURNING seat_id
+		), w AS (
+			UPDATE seat_waitlist SET status = $2 WHERE status = 'OFFERED' AND offer_expires_at <= now() RETURNING seat_id
+		)
+		SELECT seat_id::text FROM h UNION SELECT seat_id::text FROM w`, HoldExpired, WaitlistExpired)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("[waitlist-uc] ❌ Expiry sweep failed: %v", err))
+		return
+	}
+	var seats []string
+	for rows.Next() {
+		var id string
+		if rows.Scan(&id) == nil {
+			seats = append(seats, id)
+		}
+	}
+	rows.Close()
+
+	for _, id := range seats {
+		ProcessWaitlist(id)
+	}
+}
+
+// StartWaitlistWorker sweeps expired holds and offers every minute.
+func StartWaitlistWorker() {
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for range ticker.C {
+			ExpireHoldsAndOffers()
+		}
+	}()
+	logger.Log.Info("[waitlist-uc] Hold/offer expiry worker started.")
+}
+
+// GetSeatWaitlist lists the active queue of a seat category for admins.
+func GetSeatWaitlist(seatID string) ([]*WaitlistEntry, error) {
+	rows, err := db.DB.Query(`SELECT `+waitlistColumns+` FROM seat_waitlist
+		WHERE seat_id = $1 AND status IN ('WAITING', 'OFFERED') ORDER BY created_at`, seatID)
+	if err != nil {
+		return nil, fmt.Errorf("database query error: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]*WaitlistEntry, 0)
+	for rows.Next() {
+		e, err := scanWaitlistEntry(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning waitlist row: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
@@ -0,0 +1,127 @@
+package transfer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"supra/applications/auth"
+	"supra/applications/booking"
+	"supra/concert/application"
+	"supra/db"
+	"supra/logger"
+)
+
+// AcceptTransferUC moves the offered tickets to the recipient, who must be
+// logged in (via OTP) as the email the transfer was addressed to. Tickets are
+// reissued for both sides so the sender's old QR codes stop working.
+func AcceptTransferUC(transferID, email string) (*Transfer, error) {
+	logger.Log.Info(fmt.Sprintf("[accept-transfer-uc] %s accepting transfer %s", email, transferID))
+
+	tx, err := db.DB.BeginTx(context.Background(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	t, err := scanTransfer(tx.QueryRow(`SELECT `+transferColumns+` FROM ticket_transfer WHERE transfer_id = $1 FOR UPDATE`, transferID))
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(t.ToEmail, email) {
+		return nil, ErrTransferNotFound
+	}
+	if t.Status != PENDING {
+		return nil, fmt.Errorf("%w: status %s", ErrNotPending, t.Status)
+	}
+	now := time.Now()
+	if !now.Before(t.ExpiresAt) {
+		return nil, ErrTransferExpired
+	}
+
+	bk, err := booking.GetBookingTx(tx, t.BookingID)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(bk.BookingEmail, t.FromEmail) {
+		// The booking changed hands since the offer was made.
+		return nil, fmt.Errorf("%w: booking owner changed", ErrNotPending)
+	}
+	concert, err := application.NewGetConcertByIDUC(logger.Log).Invoke(bk.ConcertID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load concert: %w", err)
+	}
+	if err := concert.TransferPolicy.Check(now); err != nil {
+		return nil, err
+	}
+	if err := checkNoActiveRefund(t.BookingID); err != nil {
+		return nil, err
+	}
+
+	received, err := booking.TransferTicketsTx(tx, t.BookingID, t.ToEmail, t.ParticipantIDs)
+	if err != nil {
+		return nil, err
+	}
+	t.Status, t.NewBookingID, t.UpdatedAt = ACCEPTED, received.BookingID.String(), now
+	if _, err := tx.Exec(`
+		UPDATE ticket_transfer SET status = $2, new_booking_id = $3, updated_at = $4
+		WHERE transfer_id = $1`, t.TransferID, t.Status, t.NewBookingID, t.UpdatedAt); err != nil {
+		return nil, fmt.Errorf("failed to update transfer: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit failed: %w", err)
+	}
+	logger.Log.Info(fmt.Sprintf("[accept-transfer-uc] ✅ Transfer %s accepted; tickets now on booking %s", t.TransferID, t.NewBookingID))
+
+	go notifyAccepted(t)
+	return t, nil
+}
+
+func notifyAccepted(t *Transfer) {
+	booking.SendReissuedTicket(t.NewBookingID, fmt.Sprintf("%s transferred these tickets to you.", t.FromEmail))
+	if t.NewBookingID != t.BookingID {
+		booking.SendReissuedTicket(t.BookingID, fmt.Sprintf("%d ticket(s) were transferred to %s; here are your remaining tickets.", t.Quantity, t.ToEmail))
+	}
+	if err := auth.SendTransferCompletedMail(t.FromEmail, t.BookingID, t.ToEmail, t.Quantity); err != nil {
+		logger.Log.Error(fmt.Sprintf("[accept-transfer-uc] ❌ Failed to notify sender of transfer %s: %v", t.TransferID, err))
+	}
+}
+
+// CancelTransferUC withdraws a pending transfer. The sender cancels it; the
+// recipient declines it.
+func CancelTransferUC(transferID, email string) (*Transfer, error) {
+	tx, err := db.DB.BeginTx(context.Background(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	t, err := scanTransfer(tx.QueryRow(`SELECT `+transferColumns+` FROM ticket_transfer WHERE transfer_id = $1 FOR UPDATE`, transferID))
+	if err != nil {
+		return nil, err
+	}
+	party := strings.EqualFold(t.FromEmail, email) || strings.EqualFold(t.ToEmail, email)
+	if !party {
+		return nil, ErrTransferNotFound
+	}
+	if t.Status != PENDING {
+		return nil, fmt.Errorf("%w: status %s", ErrNotPending, t.Status)
+	}
+	t.Status = DECLINED
+	if strings.EqualFold(t.FromEmail, email) {
+		t.Status = CANCELLED
+	}
+
+	t.UpdatedAt = time.Now()
+	if _, err := tx.Exec(`UPDATE ticket_transfer SET status = $2, updated_at = $3 WHERE transfer_id = $1`,
+		t.TransferID, t.Status, t.UpdatedAt); err != nil {
+		return nil, fmt.Errorf("failed to update transfer: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit failed: %w", err)
+	}
+
+	logger.Log.Info(fmt.Sprintf("[cancel-transfer-uc] Transfer %s %s by %s", t.TransferID, strings.ToLower(t.Status), email))
+	return t, nil
+}
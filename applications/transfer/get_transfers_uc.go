@@ -0,0 +1,39 @@
+package transfer
+
+import (
+	"fmt"
+
+	"supra/db"
+)
+
+// GetMyTransfersUC lists transfers the user sent or received, newest first.
+func GetMyTransfersUC(email string) ([]*Transfer, error) {
+	return queryTransfers(`SELECT `+transferColumns+` FROM ticket_transfer
+		WHERE lower(from_email) = lower($1) OR lower(to_email) = lower($1)
+		ORDER BY created_at DESC`, email)
+}
+
+// GetBookingTransfersUC lists every transfer of a booking for admins.
+func GetBookingTransfersUC(bookingID string) ([]*Transfer, error) {
+	return queryTransfers(`SELECT `+transferColumns+` FROM ticket_transfer
+		WHERE booking_id = $1 OR new_booking_id = $1
+		ORDER BY created_at DESC`, bookingID)
+}
+
+func queryTransfers(query string, args ...interface{}) ([]*Transfer, error) {
+	rows, err := db.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("database query error: %w", err)
+	}
+	defer rows.Close()
+
+	transfers := make([]*Transfer, 0)
+	for rows.Next() {
+		t, err := scanTransfer(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning transfer row: %w", err)
+		}
+		transfers = append(transfers, t)
+	}
+	return transfers, rows.Err()
+}
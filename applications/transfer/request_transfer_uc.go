@@ -0,0 +1,155 @@
+package transfer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"supra/applications/auth"
+	"supra/applications/booking"
+	"supra/concert/application"
+	"supra/db"
+	"supra/logger"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type RequestTransferParams struct {
+	ToEmail        string   `json:"toEmail"`
+	ParticipantIDs []string `json:"participantIDs,omitempty"` // leave empty to transfer the whole booking
+}
+
+// RequestTransferUC offers tickets of fromEmail's booking to another email.
+func RequestTransferUC(bookingID, fromEmail string, payload []byte) (*Transfer, error) {
+	logger.Log.Info(fmt.Sprintf("[request-transfer-uc] Transfer requested for booking %s by %s", bookingID, fromEmail))
+
+	var p RequestTransferParams
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return nil, fmt.Errorf("invalid payload: %w", err)
+	}
+	addr, err := mail.ParseAddress(strings.TrimSpace(p.ToEmail))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRecipient, err)
+	}
+	toEmail := strings.ToLower(addr.Address)
+	if strings.EqualFold(toEmail, fromEmail) {
+		return nil, fmt.Errorf("%w: cannot transfer tickets to yourself", ErrInvalidRecipient)
+	}
+
+	bk, err := booking.GetBooking(bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(bk.BookingEmail, fromEmail) {
+		return nil, ErrNotOwner
+	}
+	if bk.BookingStatus != booking.APPROVED && bk.BookingStatus != booking.CONFIRMED {
+		return nil, fmt.Errorf("%w: status %s", booking.ErrNotTransferable, bk.BookingStatus)
+	}
+	if err := checkNoActiveRefund(bookingID); err != nil {
+		return nil, err
+	}
+
+	qty := bk.SeatQuantity
+	if len(p.ParticipantIDs) > 0 {
+		owned := make(map[string]bool, len(bk.ParticipantIDs))
+		for _, id := range bk.ParticipantIDs {
+			owned[id] = true
+		}
+		for _, id := range p.ParticipantIDs {
+			if !owned[id] {
+				return nil, fmt.Errorf("%w: %s", booking.ErrUnknownParticipant, id)
+			}
+		}
+		if len(p.ParticipantIDs) < len(bk.ParticipantIDs) {
+			qty = len(p.ParticipantIDs)
+		} else {
+			p.ParticipantIDs = nil
+		}
+	}
+
+	concert, err := application.NewGetConcertByIDUC(logger.Log).Invoke(bk.ConcertID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load concert: %w", err)
+	}
+	now := time.Now()
+	if err := concert.TransferPolicy.Check(now); err != nil {
+		logger.Log.Warn(fmt.Sprintf("[request-transfer-uc] Transfer refused for %s: %v", bookingID, err))
+		return nil, err
+	}
+
+	// The offer cannot outlive the concert's transfer cutoff.
+	expires := now.Add(AcceptTTL())
+	if d := concert.TransferPolicy.Deadline; d != nil && d.Before(expires) {
+		expires = *d
+	}
+
+	t := &Transfer{
+		TransferID:     uuid.NewString(),
+		BookingID:      bk.BookingID.String(),
+		FromEmail:      bk.BookingEmail,
+		ToEmail:        toEmail,
+		ParticipantIDs: p.ParticipantIDs,
+		Quantity:       qty,
+		Status:         PENDING,
+		ExpiresAt:      expires,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if err := expireStale(bookingID); err != nil {
+		return nil, err
+	}
+	participantsJSON, _ := json.Marshal(t.ParticipantIDs)
+	_, err = db.DB.Exec(`
+		INSERT INTO ticket_transfer (
+			transfer_id, booking_id, from_email, to_email, participant_ids, quantity, status,
+			expires_at, created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		t.TransferID, t.BookingID, t.FromEmail, t.ToEmail, participantsJSON, t.Quantity, t.Status,
+		t.ExpiresAt, t.CreatedAt, t.UpdatedAt)
+	if err != nil {
+		// The partial unique index allows one pending transfer per booking.
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return nil, ErrTransferExists
+		}
+		logger.Log.Error(fmt.Sprintf("[request-transfer-uc] Insert failed for booking %s: %v", bookingID, err))
+		return nil, fmt.Errorf("failed to save transfer: %w", err)
+	}
+	logger.Log.Info(fmt.Sprintf("[request-transfer-uc] ✅ Transfer %s of %d ticket(s) offered to %s", t.TransferID, t.Quantity, t.ToEmail))
+
+	go func() {
+		if err := auth.SendTransferOfferMail(t.ToEmail, t.FromEmail, t.BookingID, bk.SeatType, t.Quantity, acceptURL(t.TransferID), t.ExpiresAt); err != nil {
+			logger.Log.Error(fmt.Sprintf("[request-transfer-uc] ❌ Failed to send transfer offer %s: %v", t.TransferID, err))
+		}
+	}()
+	return t, nil
+}
+
+func checkNoActiveRefund(bookingID string) error {
+	var active bool
+	err := db.DB.QueryRow(`SELECT EXISTS (SELECT 1 FROM refund WHERE booking_id = $1 AND status IN ('REQUESTED', 'APPROVED'))`, bookingID).Scan(&active)
+	if err != nil {
+		return fmt.Errorf("failed to check refunds: %w", err)
+	}
+	if active {
+		return ErrRefundInProgress
+	}
+	return nil
+}
+
+// expireStale closes lapsed offers so the booking can be offered again.
+func expireStale(bookingID string) error {
+	_, err := db.DB.Exec(`
+		UPDATE ticket_transfer SET status = $2, updated_at = now()
+		WHERE booking_id = $1 AND status = 'PENDING' AND expires_at <= now()`, bookingID, EXPIRED)
+	if err != nil {
+		return fmt.Errorf("failed to expire transfers: %w", err)
+	}
+	return nil
+}
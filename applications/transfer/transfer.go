@@ -0,0 +1,87 @@
+package transfer
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Transfer is a booking owner's offer to hand tickets to another email. The
+// recipient accepts it after logging in with that email.
+type Transfer struct {
+	TransferID     string    `json:"transferID"`
+	BookingID      string    `json:"bookingID"`
+	NewBookingID   string    `json:"newBookingID,omitempty"` // recipient's booking once accepted
+	FromEmail      string    `json:"fromEmail"`
+	ToEmail        string    `json:"toEmail"`
+	ParticipantIDs []string  `json:"participantIDs,omitempty"` // empty = the whole booking
+	Quantity       int       `json:"quantity"`
+	Status         string    `json:"status"`
+	ExpiresAt      time.Time `json:"expiresAt"`
+	CreatedAt      time.Time `json:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
+
+const (
+	PENDING   = "PENDING"
+	ACCEPTED  = "ACCEPTED"
+	DECLINED  = "DECLINED"
+	CANCELLED = "CANCELLED"
+	EXPIRED   = "EXPIRED"
+)
+
+var (
+	ErrTransferNotFound = errors.New("transfer not found")
+	ErrTransferExists   = errors.New("a pending transfer already exists for this booking")
+	ErrTransferExpired  = errors.New("transfer offer has expired")
+	ErrNotPending       = errors.New("transfer is no longer pending")
+	ErrInvalidRecipient = errors.New("invalid transfer recipient")
+	ErrNotOwner         = errors.New("only the booking owner can transfer its tickets")
+	ErrRefundInProgress = errors.New("booking has an active refund and cannot be transferred")
+)
+
+// AcceptTTL reads TRANSFER_ACCEPT_TTL (a Go duration, default 48h).
+func AcceptTTL() time.Duration {
+	if d, err := time.ParseDuration(os.Getenv("TRANSFER_ACCEPT_TTL")); err == nil && d > 0 {
+		return d
+	}
+	return 48 * time.Hour
+}
+
+func acceptURL(transferID string) string {
+	base := os.Getenv("TRANSFER_ACCEPT_URL")
+	if base == "" {
+		base = "https://bkentertainments.vercel.app/transfers/accept"
+	}
+	return fmt.Sprintf("%s?transferID=%s", base, transferID)
+}
+
+const transferColumns = `
+	transfer_id, booking_id, COALESCE(new_booking_id::text, ''), from_email, to_email,
+	participant_ids, quantity, status, expires_at, created_at, updated_at`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanTransfer(row rowScanner) (*Transfer, error) {
+	t := &Transfer{}
+	var participantsJSON []byte
+	err := row.Scan(
+		&t.TransferID, &t.BookingID, &t.NewBookingID, &t.FromEmail, &t.ToEmail,
+		&participantsJSON, &t.Quantity, &t.Status, &t.ExpiresAt, &t.CreatedAt, &t.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrTransferNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(participantsJSON) > 0 && string(participantsJSON) != "null" {
+		json.Unmarshal(participantsJSON, &t.ParticipantIDs)
+	}
+	return t, nil
+}
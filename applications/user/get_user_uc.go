@@ -15,7 +15,7 @@ func GetUserByEmail(email string) (*User, error) {
 	logger.Log.Info(fmt.Sprintf("[user] Attempting to retrieve user by email: %s", email))
 
 	const selectSQL = `
-		SELECT user_id, email, password_hash, role, created_at
+		SELECT user_id, email, password_hash, role, created_at, last_login_at, disabled_at
 		FROM users
 		WHERE email = $1`
 
@@ -30,6 +30,8 @@ func GetUserByEmail(email string) (*User, error) {
 		&passwordHash, // Scan the stored hash
 		&u.Role,
 		&u.CreatedAt,
+		&u.LastLoginAt,
+		&u.DisabledAt,
 	)
 
 	if err != nil {
@@ -43,6 +45,7 @@ func GetUserByEmail(email string) (*User, error) {
 
 	// Set the stored hash to the User struct for verification
 	u.Password = string(passwordHash)
+	u.HasPassword = len(passwordHash) > 0
 
 	logger.Log.Info(fmt.Sprintf("[user] User %s found successfully. Role: %s.", email, u.Role))
 
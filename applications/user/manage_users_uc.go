@@ -0,0 +1,150 @@
+package user
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"supra/db"
+	"supra/logger"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrUserNotFound    = errors.New("user not found")
+	ErrLastAdmin       = errors.New("cannot remove the last active admin")
+	ErrAccountDisabled = errors.New("account is disabled")
+	ErrUserExists      = errors.New("an account with this email already exists")
+)
+
+const userColumns = `user_id, email, role, created_at, last_login_at, disabled_at, password_hash IS NOT NULL`
+
+func scanUser(row interface{ Scan(...interface{}) error }) (*User, error) {
+	u := &User{}
+	err := row.Scan(&u.UserID, &u.Email, &u.Role, &u.CreatedAt, &u.LastLoginAt, &u.DisabledAt, &u.HasPassword)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrUserNotFound
+	}
+	return u, err
+}
+
+// GetUserByID retrieves a user without their password hash.
+func GetUserByID(userID string) (*User, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+	u, err := scanUser(db.DB.QueryRow(`SELECT `+userColumns+` FROM users WHERE user_id = $1`, id))
+	if err != nil && !errors.Is(err, ErrUserNotFound) {
+		return nil, fmt.Errorf("failed to fetch user: %w", err)
+	}
+	return u, err
+}
+
+// ListUsers lists accounts, optionally only those with the given role,
+// most recently active first.
+func ListUsers(role string) ([]*User, error) {
+	rows, err := db.DB.Query(`
+		SELECT `+userColumns+` FROM users
+		WHERE ($1 = '' OR role = $1)
+		ORDER BY last_login_at DESC NULLS LAST, email`, role)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	defer rows.Close()
+
+	users := make([]*User, 0)
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, u)
+	}
+	return users, rows.Err()
+}
+
+// CreateUserWithRole creates an account with the given role. Existing
+// accounts are left untouched; their role only changes through SetRole.
+func CreateUserWithRole(email, role, invitedBy string) (*User, error) {
+	u, err := scanUser(db.DB.QueryRow(`
+		INSERT INTO users (user_id, email, role, created_at, invited_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING `+userColumns, uuid.New(), email, role, time.Now(), invitedBy))
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return nil, fmt.Errorf("%w: %s", ErrUserExists, email)
+		}
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+	logger.Log.Info(fmt.Sprintf("[user] Account %s created with role %s.", email, role))
+	return u, nil
+}
+
+// SetRole changes the role stored on the account.
+func SetRole(userID, role string) (*User, error) {
+	return updateUser(userID, `role = $2`, role)
+}
+
+// SetPasswordHash stores a bcrypt hash and clears any pending reset token.
+func SetPasswordHash(userID string, hash []byte) (*User, error) {
+	return updateUser(userID, `password_hash = $2, password_reset_hash = NULL, password_reset_expires_at = NULL`, hash)
+}
+
+// SetDisabled disables or re-enables an account.
+func SetDisabled(userID string, disabled bool) (*User, error) {
+	var at *time.Time
+	if disabled {
+		now := time.Now()
+		at = &now
+	}
+	return updateUser(userID, `disabled_at = $2`, at)
+}
+
+// SetPasswordResetToken stores the hash of a one-time password reset token.
+func SetPasswordResetToken(userID, tokenHash string, expiresAt time.Time) error {
+	_, err := updateUser(userID, `password_reset_hash = $2, password_reset_expires_at = $3`, tokenHash, expiresAt)
+	return err
+}
+
+// UserByResetToken returns the account a still-valid reset token belongs to.
+func UserByResetToken(tokenHash string) (*User, error) {
+	u, err := scanUser(db.DB.QueryRow(`
+		SELECT `+userColumns+` FROM users
+		WHERE password_reset_hash = $1 AND password_reset_expires_at > now()`, tokenHash))
+	if err != nil && !errors.Is(err, ErrUserNotFound) {
+		return nil, fmt.Errorf("failed to look up reset token: %w", err)
+	}
+	return u, err
+}
+
+// RecordLogin stamps the account's last successful login.
+func RecordLogin(userID uuid.UUID) {
+	if _, err := db.DB.Exec(`UPDATE users SET last_login_at = now() WHERE user_id = $1`, userID); err != nil {
+		logger.Log.Warn(fmt.Sprintf("[user] Failed to record login for %s: %v", userID, err))
+	}
+}
+
+// CountActiveAdmins returns how many enabled admin accounts exist.
+func CountActiveAdmins() (int, error) {
+	var n int
+	err := db.DB.QueryRow(`SELECT COUNT(*) FROM users WHERE role = $1 AND disabled_at IS NULL`, RoleAdmin).Scan(&n)
+	return n, err
+}
+
+func updateUser(userID, set string, args ...interface{}) (*User, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+	u, err := scanUser(db.DB.QueryRow(`UPDATE users SET `+set+` WHERE user_id = $1 RETURNING `+userColumns,
+		append([]interface{}{id}, args...)...))
+	if err != nil && !errors.Is(err, ErrUserNotFound) {
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+	return u, err
+}
@@ -0,0 +1,151 @@
+package user
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"supra/db"
+	"supra/logger"
+	"supra/validation"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrUnknownRole        = errors.New("invalid role")
+	ErrRoleAlreadyGranted = errors.New("role already assigned")
+	ErrRoleNotFound       = errors.New("role assignment not found")
+)
+
+// StaffRole grants a staff role to a user, either everywhere (empty
+// ConcertID) or for one concert only.
+type StaffRole struct {
+	AssignmentID uuid.UUID `json:"assignmentID"`
+	UserID       uuid.UUID `json:"userID"`
+	Email        string    `json:"email"`
+	Role         string    `json:"role"`
+	ConcertID    string    `json:"concertID,omitempty"`
+	GrantedBy    string    `json:"grantedBy"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+// AssignStaffRoleParams is the body of POST /admin/staff-roles.
+type AssignStaffRoleParams struct {
+	Email     string `json:"email" validate:"required,email"`
+	Role      string `json:"role" validate:"required"`
+	ConcertID string `json:"concertID,omitempty" validate:"uuid"`
+}
+
+// AssignStaffRole grants a staff role to an existing account.
+func AssignStaffRole(payload []byte, grantedBy string) (*StaffRole, error) {
+	var p AssignStaffRoleParams
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return nil, fmt.Errorf("invalid payload: %w", err)
+	}
+	if err := validation.Struct(&p); err != nil {
+		return nil, err
+	}
+	if !IsStaffRole(p.Role) {
+		return nil, fmt.Errorf("%w %q: must be one of %s", ErrUnknownRole, p.Role, strings.Join(StaffRoles, ", "))
+	}
+	u, err := GetUserByEmail(p.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	sr := &StaffRole{
+		AssignmentID: uuid.New(),
+		UserID:       u.UserID,
+		Email:        u.Email,
+		Role:         p.Role,
+		ConcertID:    p.ConcertID,
+		GrantedBy:    grantedBy,
+		CreatedAt:    time.Now(),
+	}
+	_, err = db.DB.Exec(`
+		INSERT INTO staff_role (assignment_id, user_id, role, concert_id, granted_by, created_at)
+		VALUES ($1, $2, $3, NULLIF($4, '')::uuid, $5, $6)`,
+		sr.AssignmentID, sr.UserID, sr.Role, sr.ConcertID, sr.GrantedBy, sr.CreatedAt)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return nil, ErrRoleAlreadyGranted
+		}
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			return nil, fmt.Errorf("concert %s not found", p.ConcertID)
+		}
+		return nil, fmt.Errorf("failed to assign role: %w", err)
+	}
+
+	logger.Log.Info(fmt.Sprintf("[user] %s granted role %s to %s (concert %q)", grantedBy, sr.Role, sr.Email, sr.ConcertID))
+	return sr, nil
+}
+
+// RevokeStaffRole removes a role assignment.
+func RevokeStaffRole(assignmentID, revokedBy string) error {
+	id, err := uuid.Parse(assignmentID)
+	if err != nil {
+		return fmt.Errorf("invalid assignment ID: %w", err)
+	}
+	res, err := db.DB.Exec(`DELETE FROM staff_role WHERE assignment_id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to revoke role: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrRoleNotFound
+	}
+	logger.Log.Info(fmt.Sprintf("[user] %s revoked role assignment %s", revokedBy, assignmentID))
+	return nil
+}
+
+// GetStaffRoles lists role assignments, optionally for one user or concert.
+func GetStaffRoles(userID, concertID string) ([]*StaffRole, error) {
+	rows, err := db.DB.Query(`
+		SELECT s.assignment_id, s.user_id, u.email, s.role, COALESCE(s.concert_id::text, ''), s.granted_by, s.created_at
+		FROM staff_role s
+		JOIN users u ON u.user_id = s.user_id
+		WHERE ($1 = '' OR s.user_id::text = $1)
+		  AND ($2 = '' OR s.concert_id::text = $2)
+		ORDER BY u.email, s.role`, userID, concertID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list staff roles: %w", err)
+	}
+	defer rows.Close()
+
+	roles := make([]*StaffRole, 0)
+	for rows.Next() {
+		sr := &StaffRole{}
+		if err := rows.Scan(&sr.AssignmentID, &sr.UserID, &sr.Email, &sr.Role, &sr.ConcertID, &sr.GrantedBy, &sr.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan staff role: %w", err)
+		}
+		roles = append(roles, sr)
+	}
+	return roles, rows.Err()
+}
+
+// GetUserStaffRoles returns the roles granted to a user, for permission checks.
+func GetUserStaffRoles(userID string) ([]*StaffRole, error) {
+	if _, err := uuid.Parse(userID); err != nil {
+		return nil, nil
+	}
+	rows, err := db.DB.Query(`
+		SELECT role, COALESCE(concert_id::text, '') FROM staff_role WHERE user_id = $1`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load staff roles: %w", err)
+	}
+	defer rows.Close()
+
+	roles := make([]*StaffRole, 0)
+	for rows.Next() {
+		sr := &StaffRole{}
+		if err := rows.Scan(&sr.Role, &sr.ConcertID); err != nil {
+			return nil, fmt.Errorf("failed to scan staff role: %w", err)
+		}
+		roles = append(roles, sr)
+	}
+	return roles, rows.Err()
+}
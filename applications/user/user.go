@@ -10,15 +10,39 @@ type User struct {
 	UserID    uuid.UUID `json:"userID"`
 	Email     string    `json:"email"`
 	Password  string    `json:"-"`    // Store hashed password, but never return it
-	Role      string    `json:"role"` // "admin" or "user"
+	Role      string    `json:"role"` // "admin", "user" or a global staff role
 	CreatedAt time.Time `json:"createdAt"`
+
+	LastLoginAt *time.Time `json:"lastLoginAt,omitempty"`
+	DisabledAt  *time.Time `json:"disabledAt,omitempty"` // disabled accounts cannot log in
+	HasPassword bool       `json:"hasPassword"`          // can use the password (admin) login
 }
 
 const (
 	RoleAdmin = "admin"
 	RoleUser  = "user"
+
+	// Staff roles. They can be granted globally or for a single concert; see
+	// staff_role.go and auth/permissions.go for what each one may do.
+	RoleOrganizer = "organizer"  // runs concerts: everything except managing staff
+	RoleVerifier  = "verifier"   // approves and rejects bookings
+	RoleDoorStaff = "door_staff" // checks tickets at the venue
+	RoleViewer    = "viewer"     // reads reports
 )
 
+// StaffRoles lists the roles that can be assigned through the admin API.
+var StaffRoles = []string{RoleOrganizer, RoleVerifier, RoleDoorStaff, RoleViewer}
+
+// IsStaffRole reports whether role is one of StaffRoles.
+func IsStaffRole(role string) bool {
+	for _, r := range StaffRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
 // LoginParams for incoming credentials
 type LoginParams struct {
 	Email    string `json:"email"`
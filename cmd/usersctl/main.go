@@ -0,0 +1,143 @@
+// Command usersctl manages admin and staff accounts from the shell, e.g. to
+// create the first admin:
+//
+//	go run ./cmd/usersctl invite -email ops@example.com -role admin
+//	go run ./cmd/usersctl set-password -email ops@example.com < password.txt
+//
+// It uses the same DATABASE_URL and mail settings as the API server.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"supra/applications/auth"
+	"supra/applications/user"
+	"supra/db"
+
+	"github.com/joho/godotenv"
+)
+
+// cliActor is recorded as the inviter and in the audit logs.
+const cliActor = "usersctl"
+
+const usage = `usage: usersctl <command> [flags]
+
+commands:
+  list           [-role ROLE]                      list accounts, most recent login first
+  invite         -email EMAIL -role ROLE [-concert ID]
+  set-role       -email EMAIL -role ROLE
+  set-password   -email EMAIL                      reads the new password from stdin
+  reset-password -email EMAIL                      emails a one-time reset link
+  disable        -email EMAIL
+  enable         -email EMAIL
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	_ = godotenv.Load()
+
+	cmd := os.Args[1]
+	fs := flag.NewFlagSet(cmd, flag.ExitOnError)
+	email := fs.String("email", "", "account email")
+	role := fs.String("role", "", "role: admin, user, "+strings.Join(user.StaffRoles, ", "))
+	concertID := fs.String("concert", "", "grant the staff role for this concert only")
+	_ = fs.Parse(os.Args[2:])
+
+	if err := db.InitDB(db.DSNFromEnv()); err != nil {
+		log.Fatalf("Database initialization failed: %v", err)
+	}
+	defer db.DB.Close()
+	if err := db.RunMigrations(); err != nil {
+		log.Fatalf("Database migration failed: %v", err)
+	}
+
+	if err := run(cmd, *email, *role, *concertID); err != nil {
+		log.Fatalf("%s: %v", cmd, err)
+	}
+}
+
+func run(cmd, email, role, concertID string) error {
+	if cmd == "list" {
+		return list(role)
+	}
+	if email == "" {
+		return fmt.Errorf("-email is required")
+	}
+	if cmd == "invite" {
+		payload, _ := json.Marshal(auth.InviteUserParams{Email: email, Role: role, ConcertID: concertID})
+		u, err := auth.InviteUser(payload, cliActor)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Invited %s (%s) as %s.\n", u.Email, u.UserID, role)
+		return nil
+	}
+
+	u, err := user.GetUserByEmail(email)
+	if err != nil {
+		return err
+	}
+	userID := u.UserID.String()
+	actor := auth.Principal{Email: cliActor}
+
+	switch cmd {
+	case "set-role":
+		payload, _ := json.Marshal(auth.SetRoleParams{Role: role})
+		if _, err := auth.SetRole(userID, payload, actor); err != nil {
+			return err
+		}
+	case "set-password":
+		fmt.Fprint(os.Stderr, "New password: ")
+		password, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && password == "" {
+			return fmt.Errorf("failed to read password: %w", err)
+		}
+		payload, _ := json.Marshal(auth.SetPasswordParams{Password: strings.TrimRight(password, "\r\n")})
+		if _, err := auth.SetPassword(userID, payload, cliActor); err != nil {
+			return err
+		}
+	case "reset-password":
+		if err := auth.RequestPasswordReset(userID, cliActor); err != nil {
+			return err
+		}
+	case "disable", "enable":
+		if _, err := auth.SetDisabled(userID, cmd == "disable", actor); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown command\n\n%s", usage)
+	}
+	fmt.Printf("%s: done for %s.\n", cmd, u.Email)
+	return nil
+}
+
+func list(role string) error {
+	users, err := user.ListUsers(role)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "EMAIL\tROLE\tPASSWORD\tLAST LOGIN\tSTATUS\tUSER ID")
+	for _, u := range users {
+		lastLogin, status := "never", "active"
+		if u.LastLoginAt != nil {
+			lastLogin = u.LastLoginAt.Format(time.RFC3339)
+		}
+		if u.DisabledAt != nil {
+			status = "disabled " + u.DisabledAt.Format("2006-01-02")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%t\t%s\t%s\t%s\n", u.Email, u.Role, u.HasPassword, lastLogin, status, u.UserID)
+	}
+	return w.Flush()
+}
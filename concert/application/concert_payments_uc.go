@@ -0,0 +1,230 @@
+package application
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"supra/concert/domain"
+	"supra/db"
+	"supra/logger"
+
+	"github.com/google/uuid"
+)
+
+// defaultConcertCurrency is used when a payment method is attached without
+// an explicit currency.
+func defaultConcertCurrency() string {
+	if c, err := domain.NormalizeCurrency(os.Getenv("PAYMENT_CURRENCY")); err == nil {
+		return c
+	}
+	return domain.CurrencyINR
+}
+
+type GetConcertPaymentsUC struct {
+	log *slog.Logger
+}
+
+func NewGetConcertPaymentsUC(log *slog.Logger) *GetConcertPaymentsUC {
+	return &GetConcertPaymentsUC{
+		log: log,
+	}
+}
+
+// Invoke lists the payment methods attached to a concert. The public listing
+// only returns enabled methods; admins also see disabled ones.
+func (uc *GetConcertPaymentsUC) Invoke(concertID string, includeDisabled bool) ([]*domain.ConcertPayment, error) {
+	logger.Log.Info(fmt.Sprintf("[get-concert-payments-uc] Listing payment methods for concert: %s", concertID))
+
+	id, err := uuid.Parse(concertID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid concert ID format: %w", err)
+	}
+
+	var exists bool
+	if err := db.DB.QueryRow(`SELECT EXISTS (SELECT 1 FROM concert WHERE concert_id = $1)`, id).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("database query error: %w", err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("concert with ID %s not found", concertID)
+	}
+
+	rows, err := db.DB.Query(`
+		SELECT cp.concert_id, cp.payment_id, p.payment_type, p.details, COALESCE(p.notes, ''), cp.enabled, cp.currency
+		FROM concert_payment cp
+		JOIN payment p ON p.payment_id = cp.payment_id
+		WHERE cp.concert_id = $1 AND (cp.enabled OR $2)
+		ORDER BY p.payment_type, cp.payment_id`, id, includeDisabled)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("[get-concert-payments-uc] Database query failed for %s: %v", concertID, err))
+		return nil, fmt.Errorf("database query error: %w", err)
+	}
+	defer rows.Close()
+
+	methods := make([]*domain.ConcertPayment, 0)
+	for rows.Next() {
+		cp := &domain.ConcertPayment{}
+		if err := rows.Scan(&cp.ConcertID, &cp.PaymentID, &cp.PaymentType, &cp.Details, &cp.Notes, &cp.Enabled, &cp.Currency); err != nil {
+			return nil, fmt.Errorf("error scanning payment method row: %w", err)
+		}
+		methods = append(methods, cp)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during row iteration: %w", err)
+	}
+
+	logger.Log.Info(fmt.Sprintf("[get-concert-payments-uc] Found %d payment methods for concert %s", len(methods), concertID))
+	return methods, nil
+}
+
+type SetConcertPaymentUC struct {
+	log *slog.Logger
+}
+
+func NewSetConcertPaymentUC(log *slog.Logger) *SetConcertPaymentUC {
+	return &SetConcertPaymentUC{
+		log: log,
+	}
+}
+
+type SetConcertPaymentParams struct {
+	Enabled  *bool  `json:"enabled,omitempty"`
+	Currency string `json:"currency,omitempty"`
+}
+
+// Invoke attaches a payment method to a concert, or updates its enabled flag
+// and currency if it is already attached.
+func (uc *SetConcertPaymentUC) Invoke(concertID, paymentID string, payload []byte) (*domain.ConcertPayment, error) {
+	logger.Log.Info(fmt.Sprintf("[set-concert-payment-uc] Setting payment %s on concert %s", paymentID, concertID))
+
+	cID, err := uuid.Parse(concertID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid concert ID format: %w", err)
+	}
+	pID, err := uuid.Parse(paymentID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid payment ID format: %w", err)
+	}
+
+	var p SetConcertPaymentParams
+	if len(payload) > 0 {
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return nil, fmt.Errorf("invalid payload: %w", err)
+		}
+	}
+
+	tx, err := db.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	cp := &domain.ConcertPayment{ConcertID: cID.String(), PaymentID: pID.String(), Enabled: true, Currency: defaultConcertCurrency()}
+	err = tx.QueryRow(`SELECT enabled, currency FROM concert_payment WHERE concert_id = $1 AND payment_id = $2 FOR UPDATE`, cID, pID).
+		Scan(&cp.Enabled, &cp.Currency)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("database query error: %w", err)
+	}
+	if p.Enabled != nil {
+		cp.Enabled = *p.Enabled
+	}
+	if p.Currency != "" {
+		if cp.Currency, err = domain.NormalizeCurrency(p.Currency); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.QueryRow(`SELECT payment_type, details, COALESCE(notes, '') FROM payment WHERE payment_id = $1`, pID).
+		Scan(&cp.PaymentType, &cp.Details, &cp.Notes); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("payment with ID %s not found", paymentID)
+		}
+		return nil, fmt.Errorf("database query error: %w", err)
+	}
+
+	var exists bool
+	if err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM concert WHERE concert_id = $1)`, cID).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("database query error: %w", err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("concert with ID %s not found", concertID)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO concert_payment (concert_id, payment_id, enabled, currency)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (concert_id, payment_id) DO UPDATE SET enabled = EXCLUDED.enabled, currency = EXCLUDED.currency`,
+		cID, pID, cp.Enabled, cp.Currency)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("[set-concert-payment-uc] Upsert failed for %s/%s: %v", concertID, paymentID, err))
+		return nil, fmt.Errorf("failed to update concert payment method: %w", err)
+	}
+	if err := syncConcertPaymentIDsTx(tx, cID); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	logger.Log.Info(fmt.Sprintf("[set-concert-payment-uc] Payment %s on concert %s: enabled=%t currency=%s", paymentID, concertID, cp.Enabled, cp.Currency))
+	return cp, nil
+}
+
+type RemoveConcertPaymentUC struct {
+	log *slog.Logger
+}
+
+func NewRemoveConcertPaymentUC(log *slog.Logger) *RemoveConcertPaymentUC {
+	return &RemoveConcertPaymentUC{
+		log: log,
+	}
+}
+
+// Invoke detaches a payment method from a concert.
+func (uc *RemoveConcertPaymentUC) Invoke(concertID, paymentID string) error {
+	logger.Log.Info(fmt.Sprintf("[remove-concert-payment-uc] Removing payment %s from concert %s", paymentID, concertID))
+
+	cID, err := uuid.Parse(concertID)
+	if err != nil {
+		return fmt.Errorf("invalid concert ID format: %w", err)
+	}
+	pID, err := uuid.Parse(paymentID)
+	if err != nil {
+		return fmt.Errorf("invalid payment ID format: %w", err)
+	}
+
+	tx, err := db.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`DELETE FROM concert_payment WHERE concert_id = $1 AND payment_id = $2`, cID, pID)
+	if err != nil {
+		return fmt.Errorf("database deletion error: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("payment %s not found on concert %s", paymentID, concertID)
+	}
+	if err := syncConcertPaymentIDsTx(tx, cID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// syncConcertPaymentIDsTx keeps the legacy concert.payment_ids column equal to
+// the enabled methods so older clients reading it see the same set.
+func syncConcertPaymentIDsTx(tx *sql.Tx, concertID uuid.UUID) error {
+	_, err := tx.Exec(`
+		UPDATE concert SET payment_ids = COALESCE(
+			(SELECT jsonb_agg(payment_id::text ORDER BY payment_id) FROM concert_payment WHERE concert_id = $1 AND enabled),
+			'[]'::jsonb)
+		WHERE concert_id = $1`, concertID)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("[concert-payments] Failed to sync payment_ids for %s: %v", concertID, err))
+		return fmt.Errorf("failed to sync concert payment IDs: %w", err)
+	}
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"supra/concert/domain"
 	"supra/db"
 	"supra/logger"
+	"supra/validation"
 
 	"github.com/google/uuid"
 )
@@ -23,12 +24,12 @@ func NewCreateConcertUC(log *slog.Logger) *CreateConcertUC {
 }
 
 type CreateConcertParams struct {
-	Title             string   `json:"title" validate:"required"`
-	Venue             string   `json:"venue" validate:"required"`
-	Timing            string   `json:"timing" validate:"required"`
-	SeatIDs           []string `json:"seatIDs"` // Incoming list of associated Seat IDs
-	PaymentDetailsIDs []string `json:"paymentDetailsIDs,omitempty"`
-	Description       string   `json:"description,omitempty"`
+	Title             string   `json:"title" validate:"required,max=200"`
+	Venue             string   `json:"venue" validate:"required,max=200"`
+	Timing            string   `json:"timing" validate:"required,max=100"`
+	SeatIDs           []string `json:"seatIDs" validate:"dive,uuid"` // Incoming list of associated Seat IDs
+	PaymentDetailsIDs []string `json:"paymentDetailsIDs,omitempty" validate:"dive,uuid"`
+	Description       string   `json:"description,omitempty" validate:"max=5000"`
 }
 
 // CreateConcert handles the creation of a new concert record in the database.
@@ -41,6 +42,10 @@ func (uc *CreateConcertUC) Invoke(payload []byte) (*domain.Concert, error) {
 		logger.Log.Error(fmt.Sprintf("[create-concert-uc] Failed to unmarshal payload: %v", err))
 		return nil, fmt.Errorf("failed to unmarshal payload: %w", err)
 	}
+	if err := validation.Struct(&p); err != nil {
+		logger.Log.Warn(fmt.Sprintf("[create-concert-uc] Invalid concert request: %v", err))
+		return nil, err
+	}
 
 	newID := uuid.New().String() // Consider using uuid.UUID type internally
 	logger.Log.Info(fmt.Sprintf("[create-concert-uc] Generated new ConcertID: %s for title: %s", newID, p.Title))
@@ -78,7 +83,13 @@ func (uc *CreateConcertUC) Invoke(payload []byte) (*domain.Concert, error) {
 
 	// ✨ 4. Update Execute arguments ✨
 	logger.Log.Info(fmt.Sprintf("[create-concert-uc] Inserting new concert record into database for ID: %s", newID))
-	_, err = db.DB.Exec(
+	tx, err := db.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(
 		insertSQL,
 		concert.ConcertID,
 		concert.Title,
@@ -93,6 +104,25 @@ func (uc *CreateConcertUC) Invoke(payload []byte) (*domain.Concert, error) {
 		return nil, fmt.Errorf("failed to insert concert into database: %w", err)
 	}
 
+	// Payment methods listed at creation are attached enabled, in the default currency.
+	for _, pid := range p.PaymentDetailsIDs {
+		if _, err := uuid.Parse(pid); err != nil {
+			return nil, fmt.Errorf("invalid payment ID format: %w", err)
+		}
+		_, err = tx.Exec(`
+			INSERT INTO concert_payment (concert_id, payment_id, enabled, currency)
+			VALUES ($1, $2, TRUE, $3)
+			ON CONFLICT (concert_id, payment_id) DO NOTHING`, newID, pid, defaultConcertCurrency())
+		if err != nil {
+			logger.Log.Error(fmt.Sprintf("[create-concert-uc] Failed to attach payment %s to %s: %v", pid, newID, err))
+			return nil, fmt.Errorf("failed to attach payment method %s: %w", pid, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	logger.Log.Info(fmt.Sprintf("[create-concert-uc] Concert %s created successfully. Venue: %s", newID, p.Venue))
 	// 5. Return the created Concert object
 	return concert, nil
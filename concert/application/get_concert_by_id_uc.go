@@ -35,7 +35,7 @@ func (uc *GetConcertByIDUC) Invoke(concertID string) (*domain.Concert, error) {
 
 	// 1. SELECT query includes payment_ids
 	const selectSQL = `
-		SELECT concert_id, title, venue, timing, seat_ids, payment_ids, description
+		SELECT concert_id, title, venue, timing, seat_ids, payment_ids, description, refund_policy, charges, transfer_policy, participant_edit_deadline
 		FROM concert
 		WHERE concert_id = $1`
 
@@ -44,7 +44,11 @@ func (uc *GetConcertByIDUC) Invoke(concertID string) (*domain.Concert, error) {
 
 	c := &domain.Concert{}
 	var seatIDsJSON []byte
-	var paymentIDsJSON []byte   // Variable for payment IDs JSONB
+	var paymentIDsJSON []byte // Variable for payment IDs JSONB
+	var refundPolicyJSON []byte
+	var chargesJSON []byte
+	var transferPolicyJSON []byte
+	var editDeadline sql.NullTime
 	var concertIDUUID uuid.UUID // Use UUID type for scanning
 
 	// 2. Scan arguments include paymentIDsJSON
@@ -56,6 +60,10 @@ func (uc *GetConcertByIDUC) Invoke(concertID string) (*domain.Concert, error) {
 		&seatIDsJSON,
 		&paymentIDsJSON, // Scan the new column
 		&c.Description,
+		&refundPolicyJSON,
+		&chargesJSON,
+		&transferPolicyJSON,
+		&editDeadline,
 	)
 
 	if err != nil {
@@ -87,6 +95,36 @@ func (uc *GetConcertByIDUC) Invoke(concertID string) (*domain.Concert, error) {
 		}
 	}
 
+	// 6. Unmarshal the refund policy (NULL means refunds are not offered)
+	if len(refundPolicyJSON) > 0 && string(refundPolicyJSON) != "null" {
+		c.RefundPolicy = &domain.RefundPolicy{}
+		if err := json.Unmarshal(refundPolicyJSON, c.RefundPolicy); err != nil {
+			logger.Log.Error(fmt.Sprintf("[get-concert-uc] Failed to unmarshal refund policy for %s: %v", concertID, err))
+			return nil, fmt.Errorf("failed to unmarshal refund policy from database: %w", err)
+		}
+	}
+
+	// 7. Unmarshal fees and taxes (NULL means none)
+	if len(chargesJSON) > 0 && string(chargesJSON) != "null" {
+		c.Charges = &domain.Charges{}
+		if err := json.Unmarshal(chargesJSON, c.Charges); err != nil {
+			logger.Log.Error(fmt.Sprintf("[get-concert-uc] Failed to unmarshal charges for %s: %v", concertID, err))
+			return nil, fmt.Errorf("failed to unmarshal charges from database: %w", err)
+		}
+	}
+
+	// 8. Unmarshal the transfer policy (NULL means transfers are not offered)
+	if len(transferPolicyJSON) > 0 && string(transferPolicyJSON) != "null" {
+		c.TransferPolicy = &domain.TransferPolicy{}
+		if err := json.Unmarshal(transferPolicyJSON, c.TransferPolicy); err != nil {
+			logger.Log.Error(fmt.Sprintf("[get-concert-uc] Failed to unmarshal transfer policy for %s: %v", concertID, err))
+			return nil, fmt.Errorf("failed to unmarshal transfer policy from database: %w", err)
+		}
+	}
+	if editDeadline.Valid {
+		c.ParticipantEditDeadline = &editDeadline.Time
+	}
+
 	logger.Log.Info(fmt.Sprintf("[get-concert-uc] Successfully retrieved concert: %s", concertID))
 	return c, nil
 }
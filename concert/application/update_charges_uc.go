@@ -0,0 +1,55 @@
+package application
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"supra/concert/domain"
+	"supra/db"
+	"supra/logger"
+
+	"github.com/google/uuid"
+)
+
+type UpdateChargesUC struct {
+	log *slog.Logger
+}
+
+func NewUpdateChargesUC(log *slog.Logger) *UpdateChargesUC {
+	return &UpdateChargesUC{
+		log: log,
+	}
+}
+
+// Invoke replaces the fees and taxes of a concert.
+func (uc *UpdateChargesUC) Invoke(concertID string, payload []byte) (*domain.Charges, error) {
+	logger.Log.Info(fmt.Sprintf("[update-charges-uc] Updating charges for concert: %s", concertID))
+
+	id, err := uuid.Parse(concertID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid concert ID format: %w", err)
+	}
+
+	var charges domain.Charges
+	if err := json.Unmarshal(payload, &charges); err != nil {
+		logger.Log.Error(fmt.Sprintf("[update-charges-uc] Failed to unmarshal payload: %v", err))
+		return nil, fmt.Errorf("invalid charges: %w", err)
+	}
+	if err := charges.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid charges: %w", err)
+	}
+
+	chargesJSON, _ := json.Marshal(charges)
+	res, err := db.DB.Exec(`UPDATE concert SET charges = $2 WHERE concert_id = $1`, id, chargesJSON)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("[update-charges-uc] Update failed for %s: %v", concertID, err))
+		return nil, fmt.Errorf("failed to update charges: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, fmt.Errorf("concert with ID %s not found", concertID)
+	}
+
+	logger.Log.Info(fmt.Sprintf("[update-charges-uc] Charges updated for %s (fee/ticket=%.2f, fee%%=%.2f, taxes=%d)", concertID, charges.FeePerTicket, charges.FeePercent, len(charges.Taxes)))
+	return &charges, nil
+}
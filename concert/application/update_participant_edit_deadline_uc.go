@@ -0,0 +1,55 @@
+package application
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"supra/db"
+	"supra/logger"
+
+	"github.com/google/uuid"
+)
+
+type UpdateParticipantEditDeadlineUC struct {
+	log *slog.Logger
+}
+
+func NewUpdateParticipantEditDeadlineUC(log *slog.Logger) *UpdateParticipantEditDeadlineUC {
+	return &UpdateParticipantEditDeadlineUC{
+		log: log,
+	}
+}
+
+type participantEditDeadlinePayload struct {
+	Deadline *time.Time `json:"deadline"` // null reopens edits without a cutoff
+}
+
+// Invoke sets (or clears) the cutoff for self-service participant changes.
+func (uc *UpdateParticipantEditDeadlineUC) Invoke(concertID string, payload []byte) (*time.Time, error) {
+	logger.Log.Info(fmt.Sprintf("[update-participant-edit-deadline-uc] Updating participant edit cutoff for concert: %s", concertID))
+
+	id, err := uuid.Parse(concertID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid concert ID format: %w", err)
+	}
+
+	var p participantEditDeadlinePayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		logger.Log.Error(fmt.Sprintf("[update-participant-edit-deadline-uc] Failed to unmarshal payload: %v", err))
+		return nil, fmt.Errorf("invalid participant edit deadline: %w", err)
+	}
+
+	res, err := db.DB.Exec(`UPDATE concert SET participant_edit_deadline = $2 WHERE concert_id = $1`, id, p.Deadline)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("[update-participant-edit-deadline-uc] Update failed for %s: %v", concertID, err))
+		return nil, fmt.Errorf("failed to update participant edit deadline: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, fmt.Errorf("concert with ID %s not found", concertID)
+	}
+
+	logger.Log.Info(fmt.Sprintf("[update-participant-edit-deadline-uc] Participant edit cutoff updated for %s", concertID))
+	return p.Deadline, nil
+}
@@ -0,0 +1,55 @@
+package application
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"supra/concert/domain"
+	"supra/db"
+	"supra/logger"
+
+	"github.com/google/uuid"
+)
+
+type UpdateRefundPolicyUC struct {
+	log *slog.Logger
+}
+
+func NewUpdateRefundPolicyUC(log *slog.Logger) *UpdateRefundPolicyUC {
+	return &UpdateRefundPolicyUC{
+		log: log,
+	}
+}
+
+// Invoke replaces the refund policy of a concert.
+func (uc *UpdateRefundPolicyUC) Invoke(concertID string, payload []byte) (*domain.RefundPolicy, error) {
+	logger.Log.Info(fmt.Sprintf("[update-refund-policy-uc] Updating refund policy for concert: %s", concertID))
+
+	id, err := uuid.Parse(concertID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid concert ID format: %w", err)
+	}
+
+	var policy domain.RefundPolicy
+	if err := json.Unmarshal(payload, &policy); err != nil {
+		logger.Log.Error(fmt.Sprintf("[update-refund-policy-uc] Failed to unmarshal payload: %v", err))
+		return nil, fmt.Errorf("invalid refund policy: %w", err)
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid refund policy: %w", err)
+	}
+
+	policyJSON, _ := json.Marshal(policy)
+	res, err := db.DB.Exec(`UPDATE concert SET refund_policy = $2 WHERE concert_id = $1`, id, policyJSON)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("[update-refund-policy-uc] Update failed for %s: %v", concertID, err))
+		return nil, fmt.Errorf("failed to update refund policy: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, fmt.Errorf("concert with ID %s not found", concertID)
+	}
+
+	logger.Log.Info(fmt.Sprintf("[update-refund-policy-uc] Refund policy updated for %s (allowed=%t, percent=%.0f)", concertID, policy.Allowed, policy.Percent))
+	return &policy, nil
+}
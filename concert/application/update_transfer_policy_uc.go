@@ -0,0 +1,52 @@
+package application
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"supra/concert/domain"
+	"supra/db"
+	"supra/logger"
+
+	"github.com/google/uuid"
+)
+
+type UpdateTransferPolicyUC struct {
+	log *slog.Logger
+}
+
+func NewUpdateTransferPolicyUC(log *slog.Logger) *UpdateTransferPolicyUC {
+	return &UpdateTransferPolicyUC{
+		log: log,
+	}
+}
+
+// Invoke replaces the transfer policy of a concert.
+func (uc *UpdateTransferPolicyUC) Invoke(concertID string, payload []byte) (*domain.TransferPolicy, error) {
+	logger.Log.Info(fmt.Sprintf("[update-transfer-policy-uc] Updating transfer policy for concert: %s", concertID))
+
+	id, err := uuid.Parse(concertID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid concert ID format: %w", err)
+	}
+
+	var policy domain.TransferPolicy
+	if err := json.Unmarshal(payload, &policy); err != nil {
+		logger.Log.Error(fmt.Sprintf("[update-transfer-policy-uc] Failed to unmarshal payload: %v", err))
+		return nil, fmt.Errorf("invalid transfer policy: %w", err)
+	}
+
+	policyJSON, _ := json.Marshal(policy)
+	res, err := db.DB.Exec(`UPDATE concert SET transfer_policy = $2 WHERE concert_id = $1`, id, policyJSON)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("[update-transfer-policy-uc] Update failed for %s: %v", concertID, err))
+		return nil, fmt.Errorf("failed to update transfer policy: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, fmt.Errorf("concert with ID %s not found", concertID)
+	}
+
+	logger.Log.Info(fmt.Sprintf("[update-transfer-policy-uc] Transfer policy updated for %s (allowed=%t)", concertID, policy.Allowed))
+	return &policy, nil
+}
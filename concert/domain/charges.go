@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+)
+
+// Charges are the booking fees and taxes added on top of ticket prices for a
+// concert, e.g. an 18% GST in India or 18% VAT in Georgia.
+type Charges struct {
+	FeePerTicket float64   `json:"feePerTicket,omitempty"` // flat booking fee per ticket
+	FeePercent   float64   `json:"feePercent,omitempty"`   // service fee as % of the discounted order
+	Taxes        []TaxRule `json:"taxes,omitempty"`
+}
+
+// TaxRule is a percentage tax on the discounted order, optionally also on fees.
+type TaxRule struct {
+	Name        string  `json:"name"` // e.g. GST, VAT
+	Rate        float64 `json:"rate"` // percent
+	ApplyToFees bool    `json:"applyToFees,omitempty"`
+}
+
+// ChargeLine is one itemized fee.
+type ChargeLine struct {
+	Name   string  `json:"name"`
+	Amount float64 `json:"amount"`
+}
+
+// TaxLine is one itemized tax with the base it was computed on.
+type TaxLine struct {
+	Name   string  `json:"name"`
+	Rate   float64 `json:"rate"`
+	Base   float64 `json:"base"`
+	Amount float64 `json:"amount"`
+}
+
+const (
+	BookingFee = "BOOKING_FEE"
+	ServiceFee = "SERVICE_FEE"
+)
+
+// Validate checks the values an admin submitted.
+func (c *Charges) Validate() error {
+	if c.FeePerTicket < 0 {
+		return fmt.Errorf("feePerTicket cannot be negative")
+	}
+	if c.FeePercent < 0 || c.FeePercent > 100 {
+		return fmt.Errorf("feePercent must be between 0 and 100, got %.2f", c.FeePercent)
+	}
+	for _, t := range c.Taxes {
+		if t.Name == "" {
+			return fmt.Errorf("tax name is required")
+		}
+		if t.Rate <= 0 || t.Rate > 100 {
+			return fmt.Errorf("tax %s rate must be in (0, 100], got %.2f", t.Name, t.Rate)
+		}
+	}
+	return nil
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// Apply itemizes fees and taxes for an order of qty tickets worth net after
+// discounts. A nil Charges adds nothing.
+func (c *Charges) Apply(net float64, qty int) (fees []ChargeLine, taxes []TaxLine) {
+	if c == nil {
+		return nil, nil
+	}
+	var feeTotal float64
+	if c.FeePerTicket > 0 {
+		amt := round2(c.FeePerTicket * float64(qty))
+		fees = append(fees, ChargeLine{Name: BookingFee, Amount: amt})
+		feeTotal += amt
+	}
+	if c.FeePercent > 0 {
+		amt := round2(net * c.FeePercent / 100)
+		fees = append(fees, ChargeLine{Name: ServiceFee, Amount: amt})
+		feeTotal += amt
+	}
+	for _, t := range c.Taxes {
+		base := net
+		if t.ApplyToFees {
+			base += feeTotal
+		}
+		taxes = append(taxes, TaxLine{Name: t.Name, Rate: t.Rate, Base: round2(base), Amount: round2(base * t.Rate / 100)})
+	}
+	return fees, taxes
+}
@@ -1,5 +1,7 @@
 package domain
 
+import "time"
+
 type Concert struct {
 	ConcertID   string   `json:"concertID" validate:"required"`
 	Title       string   `json:"title" validate:"required"`
@@ -9,4 +11,11 @@ type Concert struct {
 	PaymentIDs  []string `json:"paymentDetailsIDs" validate:"required"`
 	Description string   `json:"description,omitempty"`
 	Booking     bool     `json:"booking"`
+
+	RefundPolicy   *RefundPolicy   `json:"refundPolicy,omitempty"`
+	TransferPolicy *TransferPolicy `json:"transferPolicy,omitempty"`
+	Charges        *Charges        `json:"charges,omitempty"`
+
+	// ParticipantEditDeadline ends self-service participant changes.
+	ParticipantEditDeadline *time.Time `json:"participantEditDeadline,omitempty"`
 }
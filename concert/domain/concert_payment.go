@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrPaymentNotAllowed = errors.New("payment method is not available for this concert")
+
+// Seats are priced in these currencies; a payment method charges in one of them.
+const (
+	CurrencyINR = "INR"
+	CurrencyGEL = "GEL"
+)
+
+// ConcertPayment is a payment method attached to a concert. Disabled methods
+// stay attached (so existing bookings keep their details) but cannot be used
+// for new bookings.
+type ConcertPayment struct {
+	ConcertID   string `json:"concertID"`
+	PaymentID   string `json:"paymentID"`
+	PaymentType string `json:"paymentType"`
+	Details     string `json:"details"`
+	Notes       string `json:"notes,omitempty"`
+	Enabled     bool   `json:"enabled"`
+	Currency    string `json:"currency"`
+}
+
+// NormalizeCurrency upper-cases an ISO 4217 code and rejects currencies
+// seats have no price in.
+func NormalizeCurrency(c string) (string, error) {
+	c = strings.ToUpper(strings.TrimSpace(c))
+	if c != CurrencyINR && c != CurrencyGEL {
+		return "", fmt.Errorf("invalid currency %q: must be %s or %s", c, CurrencyINR, CurrencyGEL)
+	}
+	return c, nil
+}
@@ -0,0 +1,18 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrParticipantEditClosed = errors.New("participant changes are closed for this concert")
+
+// CheckParticipantEdit reports whether booking owners may still change their
+// participants. Without a deadline edits stay open.
+func (c *Concert) CheckParticipantEdit(now time.Time) error {
+	if c.ParticipantEditDeadline != nil && now.After(*c.ParticipantEditDeadline) {
+		return fmt.Errorf("%w: cutoff passed on %s", ErrParticipantEditClosed, c.ParticipantEditDeadline.Format("02 Jan 2006 15:04"))
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrRefundNotAllowed = errors.New("refunds are not allowed for this concert")
+
+// RefundPolicy controls user-initiated refunds for a concert. Admin-issued
+// refunds (e.g. a cancelled concert) are not limited by it.
+type RefundPolicy struct {
+	Allowed  bool       `json:"allowed"`
+	Percent  float64    `json:"percent"`            // share of the booking total refunded (0-100)
+	Deadline *time.Time `json:"deadline,omitempty"` // requests after this time are refused
+	Notes    string     `json:"notes,omitempty"`
+}
+
+// Validate checks the policy values an admin submitted.
+func (p *RefundPolicy) Validate() error {
+	if p.Percent < 0 || p.Percent > 100 {
+		return fmt.Errorf("refund percent must be between 0 and 100, got %.2f", p.Percent)
+	}
+	if p.Allowed && p.Percent == 0 {
+		return fmt.Errorf("refund percent must be greater than 0 when refunds are allowed")
+	}
+	return nil
+}
+
+// RefundAmount returns how much of total may be refunded at the given time.
+func (p *RefundPolicy) RefundAmount(total float64, now time.Time) (float64, error) {
+	if p == nil || !p.Allowed {
+		return 0, ErrRefundNotAllowed
+	}
+	if p.Deadline != nil && now.After(*p.Deadline) {
+		return 0, fmt.Errorf("%w: refund deadline passed on %s", ErrRefundNotAllowed, p.Deadline.Format("02 Jan 2006 15:04"))
+	}
+	return float64(int64(total*p.Percent+0.5)) / 100, nil
+}
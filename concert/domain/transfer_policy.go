@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrTransferNotAllowed = errors.New("ticket transfers are not allowed for this concert")
+
+// TransferPolicy controls whether booking owners may hand their tickets to
+// another email, and until when.
+type TransferPolicy struct {
+	Allowed  bool       `json:"allowed"`
+	Deadline *time.Time `json:"deadline,omitempty"` // transfers must be accepted before this time
+	Notes    string     `json:"notes,omitempty"`
+}
+
+// Check reports whether a transfer may be started or accepted at now.
+func (p *TransferPolicy) Check(now time.Time) error {
+	if p == nil || !p.Allowed {
+		return ErrTransferNotAllowed
+	}
+	if p.Deadline != nil && now.After(*p.Deadline) {
+		return fmt.Errorf("%w: transfer cutoff passed on %s", ErrTransferNotAllowed, p.Deadline.Format("02 Jan 2006 15:04"))
+	}
+	return nil
+}
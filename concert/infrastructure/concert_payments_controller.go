@@ -0,0 +1,106 @@
+package infrastructure
+
+import (
+	"io"
+	"log"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"supra/concert/application"
+
+	"github.com/labstack/echo/v4"
+)
+
+func concertPaymentErrorStatus(err error) int {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		return http.StatusNotFound
+	case strings.Contains(err.Error(), "invalid"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+type GetConcertPaymentsController struct {
+	log             *slog.Logger
+	uc              *application.GetConcertPaymentsUC
+	includeDisabled bool
+}
+
+// NewGetConcertPaymentsController serves the payment methods of a concert.
+// The public route passes includeDisabled=false; the admin route sees all.
+func NewGetConcertPaymentsController(log *slog.Logger, includeDisabled bool) *GetConcertPaymentsController {
+	return &GetConcertPaymentsController{
+		log:             log,
+		uc:              application.NewGetConcertPaymentsUC(log),
+		includeDisabled: includeDisabled,
+	}
+}
+
+// Invoke handles GET /concerts/:concertID/payments.
+func (c *GetConcertPaymentsController) Invoke(ctx echo.Context) error {
+	concertID := ctx.Param("concertID")
+
+	methods, err := c.uc.Invoke(concertID, c.includeDisabled)
+	if err != nil {
+		log.Printf("Error fetching payment methods for concert %s: %v", concertID, err)
+		return ctx.JSON(concertPaymentErrorStatus(err), map[string]string{"error": err.Error()})
+	}
+	return ctx.JSON(http.StatusOK, methods)
+}
+
+type SetConcertPaymentController struct {
+	log *slog.Logger
+	uc  *application.SetConcertPaymentUC
+}
+
+func NewSetConcertPaymentController(log *slog.Logger) *SetConcertPaymentController {
+	return &SetConcertPaymentController{
+		log: log,
+		uc:  application.NewSetConcertPaymentUC(log),
+	}
+}
+
+// Invoke handles PUT /admin/concerts/:concertID/payments/:paymentID.
+func (c *SetConcertPaymentController) Invoke(ctx echo.Context) error {
+	concertID := ctx.Param("concertID")
+	paymentID := ctx.Param("paymentID")
+
+	payload, err := io.ReadAll(ctx.Request().Body)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload."})
+	}
+
+	cp, err := c.uc.Invoke(concertID, paymentID, payload)
+	if err != nil {
+		log.Printf("Error setting payment %s on concert %s: %v", paymentID, concertID, err)
+		return ctx.JSON(concertPaymentErrorStatus(err), map[string]string{"error": err.Error()})
+	}
+	return ctx.JSON(http.StatusOK, cp)
+}
+
+type RemoveConcertPaymentController struct {
+	log *slog.Logger
+	uc  *application.RemoveConcertPaymentUC
+}
+
+func NewRemoveConcertPaymentController(log *slog.Logger) *RemoveConcertPaymentController {
+	return &RemoveConcertPaymentController{
+		log: log,
+		uc:  application.NewRemoveConcertPaymentUC(log),
+	}
+}
+
+// Invoke handles DELETE /admin/concerts/:concertID/payments/:paymentID.
+func (c *RemoveConcertPaymentController) Invoke(ctx echo.Context) error {
+	concertID := ctx.Param("concertID")
+	paymentID := ctx.Param("paymentID")
+
+	if err := c.uc.Invoke(concertID, paymentID); err != nil {
+		log.Printf("Error removing payment %s from concert %s: %v", paymentID, concertID, err)
+		return ctx.JSON(concertPaymentErrorStatus(err), map[string]string{"error": err.Error()})
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
@@ -6,6 +6,7 @@ import (
 	"log/slog"
 	"net/http"
 	"supra/concert/application"
+	"supra/validation"
 
 	"github.com/labstack/echo/v4"
 )
@@ -38,6 +39,9 @@ func (c *CreateConcertController) Invoke(ctx echo.Context) error {
 
 	// 3. Handle errors (e.g., unmarshal failure, database insertion error)
 	if err != nil {
+		if ve, ok := validation.As(err); ok {
+			return ctx.JSON(http.StatusBadRequest, validation.Response{Errors: ve})
+		}
 		log.Printf("Concert creation failed: %v", err)
 		return ctx.JSON(http.StatusInternalServerError, map[string]string{
 			"error": "Failed to create concert: " + err.Error(),
@@ -0,0 +1,50 @@
+package infrastructure
+
+import (
+	"io"
+	"log"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"supra/concert/application"
+
+	"github.com/labstack/echo/v4"
+)
+
+type UpdateChargesController struct {
+	log *slog.Logger
+	uc  *application.UpdateChargesUC
+}
+
+func NewUpdateChargesController(log *slog.Logger) *UpdateChargesController {
+	return &UpdateChargesController{
+		log: log,
+		uc:  application.NewUpdateChargesUC(log),
+	}
+}
+
+// Invoke handles PUT /admin/concerts/:concertID/charges.
+func (c *UpdateChargesController) Invoke(ctx echo.Context) error {
+	concertID := ctx.Param("concertID")
+
+	payload, err := io.ReadAll(ctx.Request().Body)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload."})
+	}
+
+	charges, err := c.uc.Invoke(concertID, payload)
+	if err != nil {
+		log.Printf("Charges update failed for %s: %v", concertID, err)
+		switch {
+		case strings.Contains(err.Error(), "not found"):
+			return ctx.JSON(http.StatusNotFound, map[string]string{"error": "Concert not found."})
+		case strings.Contains(err.Error(), "invalid"):
+			return ctx.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		default:
+			return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to update charges: " + err.Error()})
+		}
+	}
+
+	return ctx.JSON(http.StatusOK, charges)
+}
@@ -0,0 +1,50 @@
+package infrastructure
+
+import (
+	"io"
+	"log"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"supra/concert/application"
+
+	"github.com/labstack/echo/v4"
+)
+
+type UpdateRefundPolicyController struct {
+	log *slog.Logger
+	uc  *application.UpdateRefundPolicyUC
+}
+
+func NewUpdateRefundPolicyController(log *slog.Logger) *UpdateRefundPolicyController {
+	return &UpdateRefundPolicyController{
+		log: log,
+		uc:  application.NewUpdateRefundPolicyUC(log),
+	}
+}
+
+// Invoke handles PUT /admin/concerts/:concertID/refund-policy.
+func (c *UpdateRefundPolicyController) Invoke(ctx echo.Context) error {
+	concertID := ctx.Param("concertID")
+
+	payload, err := io.ReadAll(ctx.Request().Body)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload."})
+	}
+
+	policy, err := c.uc.Invoke(concertID, payload)
+	if err != nil {
+		log.Printf("Refund policy update failed for %s: %v", concertID, err)
+		switch {
+		case strings.Contains(err.Error(), "not found"):
+			return ctx.JSON(http.StatusNotFound, map[string]string{"error": "Concert not found."})
+		case strings.Contains(err.Error(), "invalid"):
+			return ctx.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		default:
+			return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to update refund policy: " + err.Error()})
+		}
+	}
+
+	return ctx.JSON(http.StatusOK, policy)
+}
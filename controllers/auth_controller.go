@@ -1,9 +1,11 @@
 package controllers
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 
 	"supra/applications/auth"
@@ -33,14 +35,14 @@ func LoginHandler(c echo.Context) error {
 	if params.Password != "" {
 		logger.Log.Info(fmt.Sprintf("[auth] Attempting Admin login for email: %s", params.Email))
 
-		token, role, err := auth.LoginAdmin(params.Email, params.Password)
+		pair, err := auth.LoginAdmin(params.Email, params.Password)
 		if err != nil {
 			logger.Log.Warn(fmt.Sprintf("[auth] Admin login failed for %s: %v", params.Email, err))
 			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Invalid credentials or user not found"})
 		}
 
-		logger.Log.Info(fmt.Sprintf("[auth] Admin login successful for %s. Role: %s", params.Email, role))
-		return c.JSON(http.StatusOK, LoginResponse{Token: token, Role: role})
+		logger.Log.Info(fmt.Sprintf("[auth] Admin login successful for %s. Role: %s", params.Email, pair.Role))
+		return c.JSON(http.StatusOK, pair)
 	}
 
 	logger.Log.Info(fmt.Sprintf("[testSelvan] featureflag: %s", os.Getenv("OTP_ENABLED")))
@@ -52,11 +54,18 @@ func LoginHandler(c echo.Context) error {
 	// 2. REGULAR USER LOGIN: Email-only -> Start OTP flow
 	logger.Log.Info(fmt.Sprintf("[auth] Initiating OTP flow for regular user: %s", params.Email))
 
-	token, role, err := auth.RequestUserOTP(params.Email)
+	token, role, err := auth.RequestUserOTP(params.Email, c.RealIP())
 	if err != nil {
 		// Log the failure to initiate OTP (e.g., mail server failure, user creation failure)
 		logger.Log.Error(fmt.Sprintf("[auth] Failed to initiate OTP for %s: %v", params.Email, err))
 
+		if errors.Is(err, user.ErrAccountDisabled) {
+			return c.JSON(http.StatusForbidden, map[string]string{"error": "This account has been disabled."})
+		}
+		if ok, resp := otpThrottled(c, err); ok {
+			return resp
+		}
+
 		if strings.Contains(err.Error(), "user not found") {
 			// Although RequestUserOTP should handle creation, we log the severe error anyway.
 			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to initiate OTP process: " + err.Error()})
@@ -91,12 +100,79 @@ func VerifyOTPHandler(c echo.Context) error {
 
 	logger.Log.Info(fmt.Sprintf("[auth] Attempting OTP verification for email: %s", params.Email))
 
-	token, role, err := auth.VerifyOTP(params.Email, params.Code)
+	pair, err := auth.VerifyOTP(params.Email, params.Code, c.RealIP())
 	if err != nil {
 		logger.Log.Warn(fmt.Sprintf("[auth] OTP verification failed for %s: %v", params.Email, err))
+		if ok, resp := otpThrottled(c, err); ok {
+			return resp
+		}
 		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Invalid or expired OTP."})
 	}
 
-	logger.Log.Info(fmt.Sprintf("[auth] OTP verified successfully for %s. JWT issued. Role: %s", params.Email, role))
-	return c.JSON(http.StatusOK, LoginResponse{Token: token, Role: role})
+	logger.Log.Info(fmt.Sprintf("[auth] OTP verified successfully for %s. JWT issued. Role: %s", params.Email, pair.Role))
+	return c.JSON(http.StatusOK, pair)
+}
+
+// otpThrottled answers 429 with a Retry-After header when err is an OTP
+// lockout or rate limit.
+func otpThrottled(c echo.Context, err error) (bool, error) {
+	var te *auth.OTPThrottleError
+	if !errors.As(err, &te) {
+		return false, nil
+	}
+	c.Response().Header().Set("Retry-After", strconv.Itoa(int(te.RetryAfter.Seconds())+1))
+	return true, c.JSON(http.StatusTooManyRequests, map[string]string{"error": "Too many attempts: " + te.Error()})
+}
+
+type refreshTokenParams struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
+// RefreshTokenHandler handles POST /refresh-token
+// It exchanges a refresh token for a new access token and refresh token.
+func RefreshTokenHandler(c echo.Context) error {
+	params := new(refreshTokenParams)
+	if err := c.Bind(params); err != nil || params.RefreshToken == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "refreshToken is required"})
+	}
+
+	pair, err := auth.RefreshSession(params.RefreshToken)
+	if err != nil {
+		logger.Log.Warn(fmt.Sprintf("[auth] Token refresh failed: %v", err))
+		switch {
+		case errors.Is(err, auth.ErrInvalidRefreshToken), errors.Is(err, auth.ErrRefreshTokenReused):
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": err.Error()})
+		case errors.Is(err, user.ErrAccountDisabled):
+			return c.JSON(http.StatusForbidden, map[string]string{"error": "This account has been disabled."})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to refresh session."})
+		}
+	}
+	return c.JSON(http.StatusOK, pair)
+}
+
+// LogoutHandler handles POST /logout
+// It revokes the session of the given refresh token. The current access token
+// stays valid until it expires (JWT_ACCESS_TTL).
+func LogoutHandler(c echo.Context) error {
+	params := new(refreshTokenParams)
+	if err := c.Bind(params); err != nil || params.RefreshToken == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "refreshToken is required"})
+	}
+	if err := auth.Logout(params.RefreshToken); err != nil {
+		logger.Log.Error(fmt.Sprintf("[auth] Logout failed: %v", err))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to log out."})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// LogoutAllHandler handles POST /api/v1/logout-all
+// It signs the caller out of every session.
+func LogoutAllHandler(c echo.Context) error {
+	n, err := auth.RevokeAllSessions(auth.PrincipalFrom(c).UserID)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("[auth] Revoke-all failed: %v", err))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to revoke sessions."})
+	}
+	return c.JSON(http.StatusOK, map[string]int64{"revoked": n})
 }
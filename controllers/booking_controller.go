@@ -8,7 +8,9 @@ import (
 	"net/http"
 	"strings"
 
+	"supra/applications/auth"
 	"supra/applications/booking"
+	"supra/concert/domain"
 	"supra/logger"
 
 	"github.com/labstack/echo/v4"
@@ -24,16 +26,23 @@ func BookNowController(c echo.Context) error {
 		})
 	}
 
-	newBooking, err := booking.BookNow(payload)
+	newBooking, err := booking.BookNow(auth.PrincipalFrom(c), payload)
 
 	if err != nil {
+		if ok, resp := validationFailed(c, err); ok {
+			return resp
+		}
 		log.Printf("Booking failed: %v", err)
 
-		// Check for specific business logic error (Not enough seats)
-		if errors.Is(err, booking.ErrNotEnoughSeats) {
+		// Check for specific business logic error (not enough seats, or no price set)
+		if errors.Is(err, booking.ErrNotEnoughSeats) || errors.Is(err, booking.ErrNoPrice) {
 			return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()}) // 409 Conflict
 		}
 
+		if status, ok := receiptErrorStatus(err); ok {
+			return c.JSON(status, map[string]string{"error": err.Error()})
+		}
+
 		// Check for CANCELLED prefix from the use case error handling
 		if strings.HasPrefix(err.Error(), booking.CANCELLED) {
 			// Unmarshal, validation, or general use case failure
@@ -59,6 +68,9 @@ func UpdateBookingController(c echo.Context) error {
 	updatedBooking, err := booking.UpdateBooking(bookingID, payload)
 
 	if err != nil {
+		if ok, resp := validationFailed(c, err); ok {
+			return resp
+		}
 		log.Printf("Error updating booking %s: %v", bookingID, err)
 
 		// Check for Not Found or Invalid ID
@@ -81,7 +93,7 @@ func UpdateBookingController(c echo.Context) error {
 func GetBookingController(c echo.Context) error {
 	bookingID := c.Param("bookingID")
 
-	bk, err := booking.GetBooking(bookingID) // Non-transactional read
+	bk, err := booking.GetBookingDetailsUC(bookingID) // Non-transactional read
 
 	if err != nil {
 		log.Printf("Error fetching booking %s: %v", bookingID, err)
@@ -112,7 +124,7 @@ func GetAllBookingsController(c echo.Context) error {
 	logger.Log.Info(fmt.Sprintf("[booking] Fetching booking history for user: %s", userEmail))
 
 	// 2. Call the use case, passing the user's email for filtering
-	bookingsList, err := booking.GetAllBookings(userEmail)
+	bookingsList, err := booking.GetAllBookings(auth.PrincipalFrom(c))
 
 	if err != nil {
 		logger.Log.Error(fmt.Sprintf("[booking] Error fetching booking history for %s: %v", userEmail, err))
@@ -180,6 +192,9 @@ func UpdateBookingDetailsController(c echo.Context) error {
 	}
 
 	if err != nil {
+		if ok, resp := validationFailed(c, err); ok {
+			return resp
+		}
 		logger.Log.Error(fmt.Sprintf("[booking] Failed to update receipt for booking %s: %v", bookingID, err))
 
 		switch {
@@ -187,9 +202,14 @@ func UpdateBookingDetailsController(c echo.Context) error {
 			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid booking ID."})
 		case strings.Contains(err.Error(), "invalid base64"):
 			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid receipt image format."})
+		case errors.Is(err, booking.ErrReuploadNotAllowed):
+			return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
 		case strings.Contains(err.Error(), "not found"):
 			return c.JSON(http.StatusNotFound, map[string]string{"error": "Booking not found."})
 		default:
+			if status, ok := receiptErrorStatus(err); ok {
+				return c.JSON(status, map[string]string{"error": err.Error()})
+			}
 			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Receipt update failed: " + err.Error()})
 		}
 	}
@@ -198,6 +218,205 @@ func UpdateBookingDetailsController(c echo.Context) error {
 	return c.JSON(http.StatusOK, updatedBooking)
 }
 
+// EditParticipantsController handles PUT /bookings/:bookingID/participants
+// Body: {"add": [...], "update": [{"userID": ..., "name": ...}], "remove": [userID, ...]}
+func EditParticipantsController(c echo.Context) error {
+	bookingID := c.Param("bookingID")
+	userEmail, ok := c.Get("userEmail").(string)
+	if !ok || userEmail == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User email not found in token."})
+	}
+
+	payload, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload."})
+	}
+
+	bk, err := booking.EditParticipantsUC(bookingID, userEmail, payload)
+	if err != nil {
+		if ok, resp := validationFailed(c, err); ok {
+			return resp
+		}
+		logger.Log.Error(fmt.Sprintf("[booking] Participant edit failed for %s: %v", bookingID, err))
+		switch {
+		case errors.Is(err, booking.ErrNotBookingOwner), errors.Is(err, domain.ErrParticipantEditClosed):
+			return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+		case errors.Is(err, booking.ErrParticipantAttended):
+			return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+		case strings.Contains(err.Error(), "not found"):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Booking not found."})
+		case errors.Is(err, booking.ErrParticipantCount), errors.Is(err, booking.ErrUnknownParticipant),
+			strings.Contains(err.Error(), "invalid"):
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Participant update failed: " + err.Error()})
+		}
+	}
+	return c.JSON(http.StatusOK, bk)
+}
+
+// GetBookingHistoryController handles GET /bookings/:bookingID/history
+func GetBookingHistoryController(c echo.Context) error {
+	events, err := booking.GetBookingHistoryUC(c.Param("bookingID"))
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid") {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch booking history: " + err.Error()})
+	}
+	return c.JSON(http.StatusOK, events)
+}
+
+// BookNowUploadController handles POST /bookings/upload (multipart/form-data).
+// The "booking" field carries the booking JSON and the "receipt" field the file.
+func BookNowUploadController(c echo.Context) error {
+	receipt, err := readReceiptFile(c)
+	if err != nil {
+		logger.Log.Warn(fmt.Sprintf("[booking] Receipt upload rejected: %v", err))
+		if status, ok := receiptErrorStatus(err); ok {
+			return c.JSON(status, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	payload := c.FormValue("booking")
+	if payload == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Missing booking details."})
+	}
+
+	newBooking, err := booking.BookNowWithReceipt(auth.PrincipalFrom(c), []byte(payload), receipt)
+	if err != nil {
+		if ok, resp := validationFailed(c, err); ok {
+			return resp
+		}
+		logger.Log.Error(fmt.Sprintf("[booking] Booking failed: %v", err))
+		if errors.Is(err, booking.ErrNotEnoughSeats) {
+			return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+		}
+		if status, ok := receiptErrorStatus(err); ok {
+			return c.JSON(status, map[string]string{"error": err.Error()})
+		}
+		if strings.HasPrefix(err.Error(), booking.CANCELLED) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Internal booking failure: " + err.Error()})
+	}
+
+	logger.Log.Info(fmt.Sprintf("[booking] Booking %s created via upload. Status: %s", newBooking.BookingID, newBooking.BookingStatus))
+	return c.JSON(http.StatusCreated, newBooking)
+}
+
+// UploadBookingReceiptController handles PUT /bookings/:bookingID/receipt (multipart/form-data).
+func UploadBookingReceiptController(c echo.Context) error {
+	bookingID := c.Param("bookingID")
+
+	receipt, err := readReceiptFile(c)
+	if err != nil {
+		logger.Log.Warn(fmt.Sprintf("[booking] Receipt upload rejected for %s: %v", bookingID, err))
+		if status, ok := receiptErrorStatus(err); ok {
+			return c.JSON(status, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	updatedBooking, err := booking.UploadBookingReceiptUC(bookingID, receipt)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("[booking] Failed to upload receipt for booking %s: %v", bookingID, err))
+
+		switch {
+		case strings.Contains(err.Error(), "invalid booking ID"):
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid booking ID."})
+		case errors.Is(err, booking.ErrReuploadNotAllowed):
+			return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+		case strings.Contains(err.Error(), "not found"):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Booking not found."})
+		default:
+			if status, ok := receiptErrorStatus(err); ok {
+				return c.JSON(status, map[string]string{"error": err.Error()})
+			}
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Receipt upload failed: " + err.Error()})
+		}
+	}
+
+	logger.Log.Info(fmt.Sprintf("[booking] Receipt file uploaded successfully for booking %s.", bookingID))
+	return c.JSON(http.StatusOK, updatedBooking)
+}
+
+// GetBookingReceiptFileController streams the receipt (GET /bookings/:bookingID/receipt/file)
+// or its thumbnail (GET /bookings/:bookingID/receipt/thumbnail) with its real content type.
+func GetBookingReceiptFileController(c echo.Context) error {
+	bookingID := c.Param("bookingID")
+	thumbnail := strings.HasSuffix(c.Path(), "/thumbnail")
+
+	data, contentType, err := booking.GetBookingReceiptFileUC(bookingID, thumbnail)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("[booking] Failed to fetch receipt file for %s: %v", bookingID, err))
+		switch {
+		case strings.Contains(err.Error(), "invalid booking ID"):
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid booking ID."})
+		case errors.Is(err, booking.ErrReceiptNotFound), strings.Contains(err.Error(), "not found"):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Receipt not found."})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch receipt."})
+		}
+	}
+
+	filename := "receipt-" + bookingID + booking.ReceiptExtension(contentType)
+	if thumbnail {
+		filename = "receipt-" + bookingID + "-thumb.jpg"
+	}
+	c.Response().Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=%q", filename))
+	c.Response().Header().Set("X-Content-Type-Options", "nosniff")
+	return c.Blob(http.StatusOK, contentType, data)
+}
+
+// readReceiptFile reads the "receipt" file of a multipart request, enforcing the upload limit.
+func readReceiptFile(c echo.Context) ([]byte, error) {
+	limit := booking.MaxReceiptBytes()
+	// Leave headroom for the other form fields and multipart boundaries.
+	c.Request().Body = http.MaxBytesReader(c.Response(), c.Request().Body, limit+1<<20)
+
+	fh, err := c.FormFile("receipt")
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return nil, booking.ErrReceiptTooLarge
+		}
+		return nil, fmt.Errorf("missing receipt file: %w", err)
+	}
+	if fh.Size > limit {
+		return nil, booking.ErrReceiptTooLarge
+	}
+
+	f, err := fh.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open receipt file: %w", err)
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(io.LimitReader(f, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read receipt file: %w", err)
+	}
+	if int64(len(data)) > limit {
+		return nil, booking.ErrReceiptTooLarge
+	}
+	return data, nil
+}
+
+// receiptErrorStatus maps receipt validation errors to HTTP status codes.
+func receiptErrorStatus(err error) (int, bool) {
+	switch {
+	case errors.Is(err, booking.ErrReceiptTooLarge):
+		return http.StatusRequestEntityTooLarge, true
+	case errors.Is(err, booking.ErrUnsupportedReceiptType):
+		return http.StatusUnsupportedMediaType, true
+	case errors.Is(err, booking.ErrEmptyReceipt):
+		return http.StatusBadRequest, true
+	}
+	return 0, false
+}
+
 func GetBookingReceiptController(c echo.Context) error {
 	bookingID := c.Param("bookingID")
 
@@ -275,6 +494,7 @@ func VerifyBookingController(c echo.Context) error {
 	}
 
 	var payload struct {
+		Code   string `json:"code"`
 		Reason string `json:"reason"`
 	}
 	_ = c.Bind(&payload) // optional; only relevant for rejection
@@ -286,18 +506,26 @@ func VerifyBookingController(c echo.Context) error {
 		bk, err := booking.ApproveBookingUC(bookingID)
 		if err != nil {
 			logger.Log.Error(fmt.Sprintf("[booking-controller] Approval failed for %s: %v", bookingID, err))
+			if errors.Is(err, booking.ErrNotApprovable) || errors.Is(err, booking.ErrNotEnoughSeats) {
+				return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+			}
+			if errors.Is(err, booking.ErrBookingNotFound) {
+				return c.JSON(http.StatusNotFound, map[string]string{"error": "Booking not found."})
+			}
 			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to approve booking"})
 		}
 		return c.JSON(http.StatusOK, bk)
 
 	case "reject":
-		reason := strings.TrimSpace(payload.Reason)
-		if reason == "" {
-			reason = "Rejected by admin"
-		}
-		bk, err := booking.RejectBookingUC(bookingID, reason)
+		bk, err := booking.RejectBookingUC(bookingID, payload.Code, payload.Reason)
 		if err != nil {
 			logger.Log.Error(fmt.Sprintf("[booking-controller] Rejection failed for %s: %v", bookingID, err))
+			if errors.Is(err, booking.ErrInvalidRejectionCode) {
+				return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+			}
+			if errors.Is(err, booking.ErrNotRejectable) {
+				return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+			}
 			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to reject booking"})
 		}
 		return c.JSON(http.StatusOK, bk)
@@ -308,12 +536,55 @@ func VerifyBookingController(c echo.Context) error {
 	}
 }
 
+// GetRejectionReasonsController handles GET /bookings/rejection-reasons
+// It lists the structured rejection codes and their re-upload guidance.
+func GetRejectionReasonsController(c echo.Context) error {
+	return c.JSON(http.StatusOK, booking.GetAllRejectionReasons())
+}
+
+// GetAllParicipantsByBookingIDIDController handles GET /bookings/participants-details/:bookingID
+// This is where the ticket QR points. Staff allowed to check tickets in for
+// the concert and the booking owner see full participant details; anyone else only sees masked names.
 func GetAllParicipantsByBookingIDIDController(c echo.Context) error {
-	// 1. Get the user's email from the context (set by JWTAuthMiddleware)
 	bookingID := c.Param("bookingID")
-	logger.Log.Info(fmt.Sprintf("[booking] Fetching booking history for bookingID: %s", bookingID))
+	viewer := auth.PrincipalFrom(c)
+
+	// 1. Reject QR codes from tickets that were reissued (e.g. after a transfer)
+	if err := booking.CheckTicketCode(bookingID, c.QueryParam("code")); err != nil {
+		logger.Log.Warn(fmt.Sprintf("[ticket-access] ip=%s viewer=%q booking=%s denied: %v", c.RealIP(), viewer.Email, bookingID, err))
+		switch {
+		case errors.Is(err, booking.ErrTicketReissued):
+			return c.JSON(http.StatusGone, map[string]string{"error": err.Error()})
+		case errors.Is(err, booking.ErrBookingNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Booking not found."})
+		case errors.Is(err, booking.ErrInvalidBookingID):
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to verify ticket."})
+		}
+	}
+
+	// 2. Decide how much the caller may see
+	full := false
+	if viewer.UserID != "" {
+		if concertID, err := booking.ConcertIDOf(bookingID); err == nil {
+			full, _ = auth.HasPermission(viewer, auth.PermCheckIn, concertID)
+		}
+		if !full {
+			full = booking.CheckBookingOwner(bookingID, viewer) == nil
+		}
+	}
+	logger.Log.Info(fmt.Sprintf("[ticket-access] ip=%s viewer=%q role=%q booking=%s full=%t", c.RealIP(), viewer.Email, viewer.Role, bookingID, full))
+
+	if !full {
+		masked, err := booking.GetMaskedParticipantsByBookingID(bookingID)
+		if err != nil {
+			logger.Log.Error(fmt.Sprintf("[booking] Error fetching masked participants for %s: %v", bookingID, err))
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to retrieve participants data."})
+		}
+		return c.JSON(http.StatusOK, masked)
+	}
 
-	// 2. Call the use case, passing the user's email for filtering
 	participants, err := booking.GetAllParticipantByBookingID(bookingID)
 	if err != nil {
 		logger.Log.Error(fmt.Sprintf("[booking] Error fetching participants history for %s: %v", bookingID, err))
@@ -321,10 +592,6 @@ func GetAllParicipantsByBookingIDIDController(c echo.Context) error {
 			"error": "Failed to retrieve paticipants data: " + err.Error(),
 		})
 	}
-
-	logger.Log.Info(fmt.Sprintf("[booking] Successfully retrieved %d participants for %s.", len(participants), bookingID))
-
-	// 3. Return the filtered list
 	return c.JSON(http.StatusOK, participants)
 }
 
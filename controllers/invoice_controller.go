@@ -0,0 +1,60 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"supra/applications/booking"
+	"supra/applications/invoice"
+
+	"github.com/labstack/echo/v4"
+)
+
+// GetBookingInvoiceController handles GET /bookings/:bookingID/invoice
+// The invoice is issued on first request if approval did not already issue it.
+func GetBookingInvoiceController(c echo.Context) error {
+	bookingID := c.Param("bookingID")
+
+	pdfBytes, inv, err := booking.GetBookingInvoiceUC(bookingID)
+	if err != nil {
+		log.Printf("Invoice failed for booking %s: %v", bookingID, err)
+		switch {
+		case errors.Is(err, booking.ErrNotInvoiceable):
+			return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+		case strings.Contains(err.Error(), "not found"):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Booking not found."})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to generate invoice: " + err.Error()})
+		}
+	}
+
+	c.Response().Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=invoice-%s.pdf", inv.Number))
+	return c.Blob(http.StatusOK, "application/pdf", pdfBytes)
+}
+
+// GetCreditNoteController handles GET /refunds/:refundID/credit-note
+func GetCreditNoteController(c echo.Context) error {
+	refundID := c.Param("refundID")
+
+	cn, err := invoice.GetCreditNoteUC(refundID)
+	if err != nil {
+		switch {
+		case errors.Is(err, invoice.ErrInvoiceNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Credit note not found."})
+		case strings.Contains(err.Error(), "invalid"):
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch credit note: " + err.Error()})
+		}
+	}
+
+	pdfBytes, err := booking.InvoicePDF(cn)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	c.Response().Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=credit-note-%s.pdf", cn.Number))
+	return c.Blob(http.StatusOK, "application/pdf", pdfBytes)
+}
@@ -27,6 +27,9 @@ func AddParticipantController(c echo.Context) error {
 
 	// 3. Handle errors
 	if err != nil {
+		if ok, resp := validationFailed(c, err); ok {
+			return resp
+		}
 		log.Printf("Participant creation failed: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{
 			"error": "Failed to create participant: " + err.Error(),
@@ -73,6 +76,9 @@ func UpdateParticipantController(c echo.Context) error {
 
 	p, err := participant.UpdateParticipant(userID, payload)
 	if err != nil {
+		if ok, resp := validationFailed(c, err); ok {
+			return resp
+		}
 		if strings.Contains(err.Error(), "not found") {
 			return c.JSON(http.StatusNotFound, map[string]string{"error": "Participant not found."})
 		}
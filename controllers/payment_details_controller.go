@@ -18,6 +18,9 @@ func AddPaymentController(c echo.Context) error {
 	}
 	newPayment, err := paymentdetails.AddPayment(payload)
 	if err != nil {
+		if ok, resp := validationFailed(c, err); ok {
+			return resp
+		}
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to record payment: " + err.Error()})
 	}
 	return c.JSON(http.StatusCreated, newPayment)
@@ -54,6 +57,9 @@ func UpdatePaymentController(c echo.Context) error {
 	}
 	p, err := paymentdetails.UpdatePayment(paymentID, payload)
 	if err != nil {
+		if ok, resp := validationFailed(c, err); ok {
+			return resp
+		}
 		if strings.Contains(err.Error(), "not found") {
 			return c.JSON(http.StatusNotFound, map[string]string{"error": "Payment not found."})
 		}
@@ -0,0 +1,92 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"supra/applications/booking"
+	"supra/logger"
+	"supra/payments"
+
+	"github.com/labstack/echo/v4"
+)
+
+// CreatePaymentIntentController handles POST /bookings/:bookingID/payment-intent
+func CreatePaymentIntentController(c echo.Context) error {
+	bookingID := c.Param("bookingID")
+
+	payload, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload."})
+	}
+
+	intent, err := booking.CreatePaymentIntentUC(bookingID, payload)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("[payments] Failed to create payment intent for %s: %v", bookingID, err))
+		switch {
+		case errors.Is(err, payments.ErrUnknownProvider):
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		case errors.Is(err, booking.ErrBookingNotPayable):
+			return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+		case strings.Contains(err.Error(), "not found"):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Booking not found."})
+		case strings.Contains(err.Error(), "invalid"):
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		default:
+			return c.JSON(http.StatusBadGateway, map[string]string{"error": "Payment provider error: " + err.Error()})
+		}
+	}
+
+	return c.JSON(http.StatusCreated, intent)
+}
+
+// PaymentWebhookController handles POST /webhooks/payments/:provider
+// The raw body is needed as-is for signature verification.
+func PaymentWebhookController(c echo.Context) error {
+	provider := c.Param("provider")
+
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload."})
+	}
+
+	if err := booking.HandlePaymentWebhookUC(provider, c.Request().Header, body); err != nil {
+		switch {
+		case errors.Is(err, payments.ErrIgnoredEvent):
+			// Acknowledge so the gateway stops retrying events we do not act on.
+			return c.NoContent(http.StatusOK)
+		case errors.Is(err, payments.ErrInvalidSignature):
+			logger.Log.Warn(fmt.Sprintf("[payments] Rejected %s webhook: %v", provider, err))
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Invalid signature."})
+		case errors.Is(err, payments.ErrUnknownProvider):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+		case errors.Is(err, booking.ErrUnknownIntent):
+			logger.Log.Warn(fmt.Sprintf("[payments] %s webhook for unknown intent: %v", provider, err))
+			return c.NoContent(http.StatusOK)
+		default:
+			// 5xx lets the gateway retry later.
+			logger.Log.Error(fmt.Sprintf("[payments] Failed to process %s webhook: %v", provider, err))
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Webhook processing failed."})
+		}
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+// FakeCompletePaymentController handles POST /payment-intents/:intentID/fake-complete?outcome=fail
+// It is only routed when the fake provider is enabled.
+func FakeCompletePaymentController(c echo.Context) error {
+	intentID := c.Param("intentID")
+	succeed := c.QueryParam("outcome") != "fail"
+
+	if err := booking.SimulateFakePaymentUC(intentID, succeed); err != nil {
+		if errors.Is(err, booking.ErrUnknownIntent) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, map[string]string{"message": "Fake payment processed."})
+}
@@ -0,0 +1,94 @@
+package controllers
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"supra/applications/booking"
+	"supra/applications/promotion"
+	"supra/applications/seat"
+	"supra/concert/domain"
+
+	"github.com/labstack/echo/v4"
+)
+
+// QuoteBookingController handles POST /bookings/quote
+// It returns the itemized price (tier, promo, fees, taxes) for a prospective booking.
+func QuoteBookingController(c echo.Context) error {
+	userEmail, ok := c.Get("userEmail").(string)
+	if !ok || userEmail == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User email not found in token."})
+	}
+
+	payload, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload."})
+	}
+
+	quote, err := booking.QuoteBookingUC(userEmail, payload)
+	if err != nil {
+		if ok, resp := validationFailed(c, err); ok {
+			return resp
+		}
+		log.Printf("Quote failed: %v", err)
+		switch {
+		case errors.Is(err, booking.ErrNoPrice):
+			return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+		case strings.Contains(err.Error(), "not found"):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+		case strings.Contains(err.Error(), "invalid"),
+			strings.HasPrefix(err.Error(), "promo code"),
+			errors.Is(err, seat.ErrHoldExpired), errors.Is(err, seat.ErrHoldMismatch),
+			errors.Is(err, promotion.ErrPromoNotFound), errors.Is(err, domain.ErrPaymentNotAllowed):
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to price booking: " + err.Error()})
+		}
+	}
+	return c.JSON(http.StatusOK, quote)
+}
+
+// TaxReportController handles GET /admin/reports/tax?from=2025-01-01&to=2025-04-01&concertID=...&currency=GEL
+// Dates are inclusive start / exclusive end, as YYYY-MM-DD or RFC3339. The
+// report covers one currency, INR unless another is given.
+func TaxReportController(c echo.Context) error {
+	from, err := parseReportDate(c.QueryParam("from"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid 'from' date: " + err.Error()})
+	}
+	to, err := parseReportDate(c.QueryParam("to"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid 'to' date: " + err.Error()})
+	}
+
+	currency := domain.CurrencyINR
+	if v := c.QueryParam("currency"); v != "" {
+		if currency, err = domain.NormalizeCurrency(v); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+	}
+
+	report, err := booking.GetTaxReportUC(c.QueryParam("concertID"), currency, from, to)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to build tax report: " + err.Error()})
+	}
+	return c.JSON(http.StatusOK, report)
+}
+
+func parseReportDate(v string) (*time.Time, error) {
+	if v == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse("2006-01-02", v); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
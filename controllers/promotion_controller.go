@@ -0,0 +1,85 @@
+package controllers
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"supra/applications/promotion"
+
+	"github.com/labstack/echo/v4"
+)
+
+func promotionErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, promotion.ErrPromoNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, promotion.ErrPromoExists):
+		return http.StatusConflict
+	case strings.Contains(err.Error(), "invalid"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// CreatePromotionController handles POST /admin/promotions
+func CreatePromotionController(c echo.Context) error {
+	payload, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload."})
+	}
+
+	p, err := promotion.CreatePromotionUC(payload)
+	if err != nil {
+		log.Printf("Promotion creation failed: %v", err)
+		return c.JSON(promotionErrorStatus(err), map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusCreated, p)
+}
+
+// GetPromotionsController handles GET /admin/promotions
+func GetPromotionsController(c echo.Context) error {
+	promos, err := promotion.GetPromotionsUC()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch promotions: " + err.Error()})
+	}
+	return c.JSON(http.StatusOK, promos)
+}
+
+// UpdatePromotionController handles PUT /admin/promotions/:code
+func UpdatePromotionController(c echo.Context) error {
+	code := c.Param("code")
+	payload, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload."})
+	}
+
+	p, err := promotion.UpdatePromotionUC(code, payload)
+	if err != nil {
+		log.Printf("Promotion update failed for %s: %v", code, err)
+		return c.JSON(promotionErrorStatus(err), map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, p)
+}
+
+// DeactivatePromotionController handles DELETE /admin/promotions/:code
+func DeactivatePromotionController(c echo.Context) error {
+	code := c.Param("code")
+	if err := promotion.DeactivatePromotionUC(code); err != nil {
+		log.Printf("Promotion deactivation failed for %s: %v", code, err)
+		return c.JSON(promotionErrorStatus(err), map[string]string{"error": err.Error()})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetPromotionStatsController handles GET /admin/promotions/stats
+func GetPromotionStatsController(c echo.Context) error {
+	stats, err := promotion.GetPromotionStatsUC()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to build promotion stats: " + err.Error()})
+	}
+	return c.JSON(http.StatusOK, stats)
+}
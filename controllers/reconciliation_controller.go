@@ -0,0 +1,51 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"supra/applications/reconciliation"
+	"supra/logger"
+
+	"github.com/labstack/echo/v4"
+)
+
+const maxStatementBytes = 20 << 20 // 20 MB
+
+// ReconcileStatementController handles POST /admin/reconciliation/statements
+// (multipart: "statement" file, optional "autoApply" and "threshold" fields).
+func ReconcileStatementController(c echo.Context) error {
+	c.Request().Body = http.MaxBytesReader(c.Response(), c.Request().Body, maxStatementBytes)
+
+	fh, err := c.FormFile("statement")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Missing statement file."})
+	}
+	f, err := fh.Open()
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Failed to open statement file."})
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Failed to read statement file."})
+	}
+
+	autoApply, _ := strconv.ParseBool(c.FormValue("autoApply"))
+	threshold, _ := strconv.ParseFloat(c.FormValue("threshold"), 64)
+
+	report, err := reconciliation.ReconcileStatementUC(fh.Filename, data, autoApply, threshold)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("[reconciliation] Statement %q failed: %v", fh.Filename, err))
+		if errors.Is(err, reconciliation.ErrUnknownFormat) {
+			return c.JSON(http.StatusUnsupportedMediaType, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusUnprocessableEntity, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, report)
+}
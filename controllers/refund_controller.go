@@ -0,0 +1,113 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"supra/applications/refund"
+	"supra/concert/domain"
+	"supra/logger"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RequestRefundController handles POST /bookings/:bookingID/refunds
+func RequestRefundController(c echo.Context) error {
+	bookingID := c.Param("bookingID")
+
+	payload, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload."})
+	}
+
+	r, err := refund.RequestRefundUC(bookingID, payload)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("[refund] Refund request failed for %s: %v", bookingID, err))
+		switch {
+		case errors.Is(err, refund.ErrRefundExists), errors.Is(err, refund.ErrNotRefundable):
+			return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+		case errors.Is(err, domain.ErrRefundNotAllowed):
+			return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+		case strings.Contains(err.Error(), "not found"):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Booking not found."})
+		case errors.Is(err, refund.ErrInvalidMethod), strings.Contains(err.Error(), "invalid"), strings.Contains(err.Error(), "required"):
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Refund request failed: " + err.Error()})
+		}
+	}
+
+	return c.JSON(http.StatusCreated, r)
+}
+
+// GetBookingRefundsController handles GET /bookings/:bookingID/refunds
+func GetBookingRefundsController(c echo.Context) error {
+	bookingID := c.Param("bookingID")
+
+	refunds, err := refund.GetBookingRefundsUC(bookingID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch refunds: " + err.Error()})
+	}
+	return c.JSON(http.StatusOK, refunds)
+}
+
+// GetRefundsAdminController handles GET /admin/refunds?status=REQUESTED
+func GetRefundsAdminController(c echo.Context) error {
+	refunds, err := refund.GetRefundsUC(strings.ToUpper(c.QueryParam("status")))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch refunds: " + err.Error()})
+	}
+	return c.JSON(http.StatusOK, refunds)
+}
+
+// ProcessRefundController handles PATCH /admin/refunds/:refundID?action=approve|deny|paid
+func ProcessRefundController(c echo.Context) error {
+	refundID := c.Param("refundID")
+	action := strings.ToLower(c.QueryParam("action"))
+
+	payload, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload."})
+	}
+
+	r, err := refund.ProcessRefundUC(refundID, action, payload)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("[refund] %s failed for refund %s: %v", action, refundID, err))
+		switch {
+		case errors.Is(err, refund.ErrRefundNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Refund not found."})
+		case errors.Is(err, refund.ErrInvalidTransition):
+			return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+		case strings.Contains(err.Error(), "invalid"), strings.Contains(err.Error(), "amount"):
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Refund update failed: " + err.Error()})
+		}
+	}
+
+	return c.JSON(http.StatusOK, r)
+}
+
+// RefundConcertController handles POST /admin/concerts/:concertID/refunds
+// It refunds every paid booking of a cancelled concert in full.
+func RefundConcertController(c echo.Context) error {
+	concertID := c.Param("concertID")
+
+	var payload struct {
+		Note string `json:"note"`
+	}
+	_ = c.Bind(&payload)
+
+	refunds, err := refund.RefundConcertUC(concertID, payload.Note)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("[refund] Concert refund failed for %s: %v", concertID, err))
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"error":  "Concert refund incomplete: " + err.Error(),
+			"issued": refunds,
+		})
+	}
+	return c.JSON(http.StatusOK, refunds)
+}
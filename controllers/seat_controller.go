@@ -23,6 +23,9 @@ func AddSeatHandler(c echo.Context) error {
 
 	st, err := seat.AddSeat(payload)
 	if err != nil {
+		if ok, resp := validationFailed(c, err); ok {
+			return resp
+		}
 		log.Println("Seat creation failed:", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{
 			"error": "Failed to create seat: " + err.Error(),
@@ -139,6 +142,9 @@ func UpdateSeatController(c echo.Context) error {
 
 	// 3. Handle errors
 	if err != nil {
+		if ok, resp := validationFailed(c, err); ok {
+			return resp
+		}
 		log.Printf("Error updating seat %s: %v", seatID, err)
 
 		// Check for specific use case errors:
@@ -0,0 +1,104 @@
+package controllers
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"supra/applications/seat"
+
+	"github.com/labstack/echo/v4"
+)
+
+// GetPriceTiersController handles GET /admin/seats/:seatID/tiers
+func GetPriceTiersController(c echo.Context) error {
+	seatID := c.Param("seatID")
+
+	tiers, err := seat.GetPriceTiers(seatID)
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid") {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch price tiers: " + err.Error()})
+	}
+	return c.JSON(http.StatusOK, tiers)
+}
+
+// SetPriceTiersController handles PUT /admin/seats/:seatID/tiers (replaces every tier)
+func SetPriceTiersController(c echo.Context) error {
+	seatID := c.Param("seatID")
+	payload, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload."})
+	}
+
+	tiers, err := seat.SetPriceTiers(seatID, payload)
+	if err != nil {
+		if ok, resp := validationFailed(c, err); ok {
+			return resp
+		}
+		log.Printf("Error setting price tiers for seat %s: %v", seatID, err)
+		switch {
+		case strings.Contains(err.Error(), "not found"):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Seat not found."})
+		case strings.Contains(err.Error(), "invalid"):
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to set price tiers: " + err.Error()})
+		}
+	}
+	return c.JSON(http.StatusOK, tiers)
+}
+
+// CreateSeatHoldController handles POST /seats/:seatID/holds
+// The hold belongs to the logged-in user and locks the current tier price.
+func CreateSeatHoldController(c echo.Context) error {
+	seatID := c.Param("seatID")
+	userEmail, ok := c.Get("userEmail").(string)
+	if !ok || userEmail == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User email not found in token."})
+	}
+
+	payload, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload."})
+	}
+
+	h, err := seat.CreateHold(seatID, userEmail, payload)
+	if err != nil {
+		if ok, resp := validationFailed(c, err); ok {
+			return resp
+		}
+		log.Printf("Seat hold failed for %s: %v", seatID, err)
+		switch {
+		case errors.Is(err, seat.ErrSeatsUnavailable):
+			return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+		case strings.Contains(err.Error(), "not found"):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Seat not found."})
+		case strings.Contains(err.Error(), "invalid"):
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to hold seats: " + err.Error()})
+		}
+	}
+	return c.JSON(http.StatusCreated, h)
+}
+
+// ReleaseSeatHoldController handles DELETE /seats/holds/:holdID
+func ReleaseSeatHoldController(c echo.Context) error {
+	holdID := c.Param("holdID")
+	userEmail, ok := c.Get("userEmail").(string)
+	if !ok || userEmail == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User email not found in token."})
+	}
+
+	if err := seat.ReleaseHold(holdID, userEmail); err != nil {
+		if errors.Is(err, seat.ErrHoldNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to release hold: " + err.Error()})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
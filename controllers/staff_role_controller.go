@@ -0,0 +1,106 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"supra/applications/auth"
+	"supra/applications/booking"
+	"supra/applications/user"
+	"supra/logger"
+
+	"github.com/labstack/echo/v4"
+)
+
+// GetRolesController handles GET /admin/roles
+// It lists the assignable staff roles and what each may do.
+func GetRolesController(c echo.Context) error {
+	return c.JSON(http.StatusOK, auth.RolePermissions())
+}
+
+// GetStaffRolesController handles GET /admin/staff-roles?userID=&concertID=
+func GetStaffRolesController(c echo.Context) error {
+	roles, err := user.GetStaffRoles(c.QueryParam("userID"), c.QueryParam("concertID"))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch staff roles: " + err.Error()})
+	}
+	return c.JSON(http.StatusOK, roles)
+}
+
+// AssignStaffRoleController handles POST /admin/staff-roles
+// Body: {"email": "...", "role": "verifier", "concertID": "..."} (concertID optional)
+func AssignStaffRoleController(c echo.Context) error {
+	payload, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload."})
+	}
+
+	sr, err := user.AssignStaffRole(payload, auth.PrincipalFrom(c).Email)
+	if err != nil {
+		if ok, resp := validationFailed(c, err); ok {
+			return resp
+		}
+		logger.Log.Warn(fmt.Sprintf("[staff-roles] Role assignment failed: %v", err))
+		switch {
+		case errors.Is(err, user.ErrRoleAlreadyGranted):
+			return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+		case strings.Contains(err.Error(), "not found"):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+		case errors.Is(err, user.ErrUnknownRole), strings.Contains(err.Error(), "invalid"):
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to assign role: " + err.Error()})
+		}
+	}
+	return c.JSON(http.StatusCreated, sr)
+}
+
+// RevokeStaffRoleController handles DELETE /admin/staff-roles/:assignmentID
+func RevokeStaffRoleController(c echo.Context) error {
+	err := user.RevokeStaffRole(c.Param("assignmentID"), auth.PrincipalFrom(c).Email)
+	if err != nil {
+		switch {
+		case errors.Is(err, user.ErrRoleNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+		case strings.Contains(err.Error(), "invalid"):
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to revoke role: " + err.Error()})
+		}
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// CheckInController handles POST /admin/bookings/:bookingID/check-in
+// Body: {"code": "<ticket code from the QR>", "participantIDs": [...]} (both optional)
+func CheckInController(c echo.Context) error {
+	bookingID := c.Param("bookingID")
+	payload, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload."})
+	}
+
+	checkedIn, err := booking.CheckInUC(bookingID, auth.PrincipalFrom(c).Email, payload)
+	if err != nil {
+		if ok, resp := validationFailed(c, err); ok {
+			return resp
+		}
+		logger.Log.Warn(fmt.Sprintf("[check-in] Check-in failed for %s: %v", bookingID, err))
+		switch {
+		case errors.Is(err, booking.ErrTicketReissued):
+			return c.JSON(http.StatusGone, map[string]string{"error": err.Error()})
+		case errors.Is(err, booking.ErrNotCheckInable):
+			return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+		case strings.Contains(err.Error(), "not found"):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Booking not found."})
+		case errors.Is(err, booking.ErrUnknownParticipant), strings.Contains(err.Error(), "invalid"):
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Check-in failed: " + err.Error()})
+		}
+	}
+	return c.JSON(http.StatusOK, checkedIn)
+}
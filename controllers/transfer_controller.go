@@ -0,0 +1,113 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"supra/applications/booking"
+	"supra/applications/transfer"
+	"supra/concert/domain"
+	"supra/logger"
+
+	"github.com/labstack/echo/v4"
+)
+
+// transferErrorStatus maps transfer use case errors to HTTP statuses.
+func transferErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, transfer.ErrNotOwner), errors.Is(err, domain.ErrTransferNotAllowed):
+		return http.StatusForbidden
+	case errors.Is(err, transfer.ErrTransferExpired):
+		return http.StatusGone
+	case errors.Is(err, transfer.ErrTransferExists), errors.Is(err, transfer.ErrNotPending),
+		errors.Is(err, transfer.ErrRefundInProgress), errors.Is(err, booking.ErrNotTransferable):
+		return http.StatusConflict
+	case strings.Contains(err.Error(), "not found"):
+		return http.StatusNotFound
+	case errors.Is(err, transfer.ErrInvalidRecipient), errors.Is(err, booking.ErrUnknownParticipant),
+		strings.Contains(err.Error(), "invalid"):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// RequestTransferController handles POST /bookings/:bookingID/transfers
+// Body: {"toEmail": "...", "participantIDs": [...]} — omit participantIDs to transfer the whole booking.
+func RequestTransferController(c echo.Context) error {
+	bookingID := c.Param("bookingID")
+	userEmail, ok := c.Get("userEmail").(string)
+	if !ok || userEmail == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User email not found in token."})
+	}
+
+	payload, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload."})
+	}
+
+	t, err := transfer.RequestTransferUC(bookingID, userEmail, payload)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("[transfer] Transfer request failed for %s: %v", bookingID, err))
+		return c.JSON(transferErrorStatus(err), map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusCreated, t)
+}
+
+// GetMyTransfersController handles GET /transfers (sent and received)
+func GetMyTransfersController(c echo.Context) error {
+	userEmail, ok := c.Get("userEmail").(string)
+	if !ok || userEmail == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User email not found in token."})
+	}
+
+	transfers, err := transfer.GetMyTransfersUC(userEmail)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch transfers: " + err.Error()})
+	}
+	return c.JSON(http.StatusOK, transfers)
+}
+
+// AcceptTransferController handles POST /transfers/:transferID/accept
+func AcceptTransferController(c echo.Context) error {
+	transferID := c.Param("transferID")
+	userEmail, ok := c.Get("userEmail").(string)
+	if !ok || userEmail == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User email not found in token."})
+	}
+
+	t, err := transfer.AcceptTransferUC(transferID, userEmail)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("[transfer] Accepting transfer %s failed: %v", transferID, err))
+		return c.JSON(transferErrorStatus(err), map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, t)
+}
+
+// CancelTransferController handles DELETE /transfers/:transferID
+// The sender cancels a pending transfer; the recipient declines it.
+func CancelTransferController(c echo.Context) error {
+	transferID := c.Param("transferID")
+	userEmail, ok := c.Get("userEmail").(string)
+	if !ok || userEmail == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User email not found in token."})
+	}
+
+	t, err := transfer.CancelTransferUC(transferID, userEmail)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("[transfer] Cancelling transfer %s failed: %v", transferID, err))
+		return c.JSON(transferErrorStatus(err), map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, t)
+}
+
+// GetBookingTransfersController handles GET /admin/transfers?bookingID=...
+func GetBookingTransfersController(c echo.Context) error {
+	transfers, err := transfer.GetBookingTransfersUC(c.QueryParam("bookingID"))
+	if err != nil {
+		return c.JSON(transferErrorStatus(err), map[string]string{"error": "Failed to fetch transfers: " + err.Error()})
+	}
+	return c.JSON(http.StatusOK, transfers)
+}
@@ -0,0 +1,140 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"supra/applications/auth"
+	"supra/applications/user"
+	"supra/logger"
+
+	"github.com/labstack/echo/v4"
+)
+
+// userAdminError maps user management errors to a response.
+func userAdminError(c echo.Context, action string, err error) error {
+	if ok, resp := validationFailed(c, err); ok {
+		return resp
+	}
+	logger.Log.Warn(fmt.Sprintf("[users] %s failed: %v", action, err))
+	switch {
+	case errors.Is(err, user.ErrUserNotFound), strings.Contains(err.Error(), "not found"):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, user.ErrLastAdmin), errors.Is(err, user.ErrRoleAlreadyGranted), errors.Is(err, user.ErrUserExists):
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	case errors.Is(err, auth.ErrSelfLockout), errors.Is(err, auth.ErrPasswordNotAllowed),
+		errors.Is(err, user.ErrUnknownRole), strings.Contains(err.Error(), "invalid"):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	default:
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": action + " failed: " + err.Error()})
+	}
+}
+
+// ListUsersController handles GET /admin/users?role=
+// Users are listed most recently logged in first.
+func ListUsersController(c echo.Context) error {
+	users, err := user.ListUsers(c.QueryParam("role"))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to list users: " + err.Error()})
+	}
+	return c.JSON(http.StatusOK, users)
+}
+
+// InviteUserController handles POST /admin/users
+// Body: {"email": "...", "role": "admin|user|organizer|...", "concertID": "..."} (concertID optional)
+func InviteUserController(c echo.Context) error {
+	payload, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload."})
+	}
+	u, err := auth.InviteUser(payload, auth.PrincipalFrom(c).Email)
+	if err != nil {
+		return userAdminError(c, "Invite", err)
+	}
+	return c.JSON(http.StatusCreated, u)
+}
+
+// SetUserRoleController handles PUT /admin/users/:userID/role
+// Body: {"role": "..."}
+func SetUserRoleController(c echo.Context) error {
+	payload, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload."})
+	}
+	u, err := auth.SetRole(c.Param("userID"), payload, auth.PrincipalFrom(c))
+	if err != nil {
+		return userAdminError(c, "Role change", err)
+	}
+	return c.JSON(http.StatusOK, u)
+}
+
+// SetUserPasswordController handles PUT /admin/users/:userID/password
+// Body: {"password": "..."}
+func SetUserPasswordController(c echo.Context) error {
+	payload, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload."})
+	}
+	u, err := auth.SetPassword(c.Param("userID"), payload, auth.PrincipalFrom(c).Email)
+	if err != nil {
+		return userAdminError(c, "Password change", err)
+	}
+	return c.JSON(http.StatusOK, u)
+}
+
+// RequestPasswordResetController handles POST /admin/users/:userID/password-reset
+// It emails the user a one-time link to choose a new password.
+func RequestPasswordResetController(c echo.Context) error {
+	if err := auth.RequestPasswordReset(c.Param("userID"), auth.PrincipalFrom(c).Email); err != nil {
+		return userAdminError(c, "Password reset", err)
+	}
+	return c.JSON(http.StatusAccepted, map[string]string{"message": "Password reset link sent."})
+}
+
+// SetUserDisabledController handles POST /admin/users/:userID/disable and /enable
+func SetUserDisabledController(disabled bool) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		u, err := auth.SetDisabled(c.Param("userID"), disabled, auth.PrincipalFrom(c))
+		if err != nil {
+			return userAdminError(c, "Account update", err)
+		}
+		return c.JSON(http.StatusOK, u)
+	}
+}
+
+// RevokeUserSessionsController handles POST /admin/users/:userID/revoke-sessions
+func RevokeUserSessionsController(c echo.Context) error {
+	n, err := auth.RevokeAllSessions(c.Param("userID"))
+	if err != nil {
+		return userAdminError(c, "Session revocation", err)
+	}
+	logger.Log.Info(fmt.Sprintf("[users] %s revoked all sessions of %s", auth.PrincipalFrom(c).Email, c.Param("userID")))
+	return c.JSON(http.StatusOK, map[string]int64{"revoked": n})
+}
+
+// ResetPasswordController handles POST /password/reset (public)
+// Body: {"token": "<from the emailed link>", "password": "..."}
+func ResetPasswordController(c echo.Context) error {
+	payload, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload."})
+	}
+	if err := auth.ResetPassword(payload); err != nil {
+		if ok, resp := validationFailed(c, err); ok {
+			return resp
+		}
+		logger.Log.Warn(fmt.Sprintf("[users] Password reset failed: %v", err))
+		switch {
+		case errors.Is(err, auth.ErrInvalidResetToken), errors.Is(err, user.ErrAccountDisabled):
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": auth.ErrInvalidResetToken.Error()})
+		case strings.Contains(err.Error(), "invalid"):
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Password reset failed."})
+		}
+	}
+	return c.JSON(http.StatusOK, map[string]string{"message": "Password updated. You can now log in."})
+}
@@ -0,0 +1,20 @@
+package controllers
+
+import (
+	"net/http"
+
+	"supra/validation"
+
+	"github.com/labstack/echo/v4"
+)
+
+// validationFailed writes the field-level errors of a request that failed
+// validation, as {"errors":[{"field":...,"message":...}]}. It reports false
+// for any other error so the caller can map it as usual.
+func validationFailed(c echo.Context, err error) (bool, error) {
+	ve, ok := validation.As(err)
+	if !ok {
+		return false, nil
+	}
+	return true, c.JSON(http.StatusBadRequest, validation.Response{Errors: ve})
+}
@@ -0,0 +1,115 @@
+package controllers
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"supra/applications/seat"
+
+	"github.com/labstack/echo/v4"
+)
+
+// JoinWaitlistController handles POST /seats/:seatID/waitlist
+func JoinWaitlistController(c echo.Context) error {
+	seatID := c.Param("seatID")
+	userEmail, ok := c.Get("userEmail").(string)
+	if !ok || userEmail == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User email not found in token."})
+	}
+
+	payload, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload."})
+	}
+
+	entry, err := seat.JoinWaitlist(seatID, userEmail, payload)
+	if err != nil {
+		if ok, resp := validationFailed(c, err); ok {
+			return resp
+		}
+		log.Printf("Waitlist join failed for %s: %v", seatID, err)
+		switch {
+		case errors.Is(err, seat.ErrSeatsStillAvailable), errors.Is(err, seat.ErrAlreadyWaitlisted):
+			return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+		case strings.Contains(err.Error(), "not found"):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Seat not found."})
+		case strings.Contains(err.Error(), "invalid"):
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to join waitlist: " + err.Error()})
+		}
+	}
+	return c.JSON(http.StatusCreated, entry)
+}
+
+// GetMyWaitlistController handles GET /waitlist
+func GetMyWaitlistController(c echo.Context) error {
+	userEmail, ok := c.Get("userEmail").(string)
+	if !ok || userEmail == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User email not found in token."})
+	}
+
+	entries, err := seat.GetMyWaitlist(userEmail)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch waitlist: " + err.Error()})
+	}
+	return c.JSON(http.StatusOK, entries)
+}
+
+// LeaveWaitlistController handles DELETE /waitlist/:entryID
+func LeaveWaitlistController(c echo.Context) error {
+	entryID := c.Param("entryID")
+	userEmail, ok := c.Get("userEmail").(string)
+	if !ok || userEmail == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User email not found in token."})
+	}
+
+	if err := seat.LeaveWaitlist(entryID, userEmail); err != nil {
+		if errors.Is(err, seat.ErrOfferNotFound) || strings.Contains(err.Error(), "invalid") {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": seat.ErrOfferNotFound.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to leave waitlist: " + err.Error()})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ClaimWaitlistOfferController handles POST /waitlist/claim/:token
+// A valid, unexpired offer becomes a seat hold for the logged-in user.
+func ClaimWaitlistOfferController(c echo.Context) error {
+	token := c.Param("token")
+	userEmail, ok := c.Get("userEmail").(string)
+	if !ok || userEmail == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User email not found in token."})
+	}
+
+	h, err := seat.ClaimWaitlistOffer(token, userEmail)
+	if err != nil {
+		log.Printf("Waitlist claim failed: %v", err)
+		switch {
+		case errors.Is(err, seat.ErrOfferNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+		case errors.Is(err, seat.ErrOfferExpired):
+			return c.JSON(http.StatusGone, map[string]string{"error": err.Error()})
+		case errors.Is(err, seat.ErrSeatsUnavailable):
+			return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to claim offer: " + err.Error()})
+		}
+	}
+	return c.JSON(http.StatusCreated, h)
+}
+
+// GetSeatWaitlistController handles GET /admin/seats/:seatID/waitlist
+func GetSeatWaitlistController(c echo.Context) error {
+	entries, err := seat.GetSeatWaitlist(c.Param("seatID"))
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid") {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch waitlist: " + err.Error()})
+	}
+	return c.JSON(http.StatusOK, entries)
+}
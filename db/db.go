@@ -4,6 +4,8 @@ import (
 	"database/sql"
 	"fmt"
 	"net/url"
+	"os"
+	"strings"
 
 	"supra/logger"
 
@@ -12,6 +14,24 @@ import (
 
 var DB *sql.DB
 
+// DSNFromEnv returns the connection string from DATABASE_URL, falling back to
+// a local Postgres. Hosted URLs get sslmode=require unless they set sslmode.
+func DSNFromEnv() string {
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn == "" {
+		logger.Log.Warn("[db] DATABASE_URL not set. Falling back to local Postgres.")
+		return "user=postgres password=postgres dbname=postgres sslmode=disable"
+	}
+	if !strings.Contains(dsn, "sslmode=") {
+		sep := "?"
+		if strings.Contains(dsn, "?") {
+			sep = "&"
+		}
+		dsn = dsn + sep + "sslmode=require"
+	}
+	return dsn
+}
+
 // InitDB opens the database connection and assigns it to the global DB variable.
 func InitDB(connStr string) error {
 	var err error
@@ -0,0 +1,100 @@
+// Package dbtest replaces db.DB with an in-memory fake for tests that run
+// without Postgres. Every query is answered by a Handler, which returns the
+// fixture rows for it.
+package dbtest
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"supra/db"
+)
+
+// Rows is the result of one query.
+type Rows struct {
+	Columns []string
+	Values  [][]driver.Value
+}
+
+// Row is a single-row result.
+func Row(columns []string, values ...driver.Value) *Rows {
+	return &Rows{Columns: columns, Values: [][]driver.Value{values}}
+}
+
+// NoRows is an empty result, which QueryRow reports as sql.ErrNoRows.
+func NoRows(columns ...string) *Rows {
+	return &Rows{Columns: columns}
+}
+
+// Handler answers a query. Arguments arrive as driver values, so UUIDs are
+// strings.
+type Handler func(query string, args []driver.Value) (*Rows, error)
+
+// Use points db.DB at a fake database served by h until the test ends.
+func Use(t testing.TB, h Handler) {
+	t.Helper()
+	prev := db.DB
+	db.DB = sql.OpenDB(connector{h})
+	t.Cleanup(func() {
+		_ = db.DB.Close()
+		db.DB = prev
+	})
+}
+
+type connector struct{ h Handler }
+
+func (c connector) Connect(context.Context) (driver.Conn, error) { return &conn{c.h}, nil }
+func (c connector) Driver() driver.Driver                        { return fakeDriver{} }
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) {
+	return nil, errors.New("dbtest: use dbtest.Use")
+}
+
+type conn struct{ h Handler }
+
+func (c *conn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("dbtest: prepared statements are not supported")
+}
+func (c *conn) Close() error { return nil }
+func (c *conn) Begin() (driver.Tx, error) {
+	return nil, errors.New("dbtest: transactions are not supported")
+}
+
+func (c *conn) QueryContext(_ context.Context, query string, named []driver.NamedValue) (driver.Rows, error) {
+	args := make([]driver.Value, len(named))
+	for i, nv := range named {
+		args[i] = nv.Value
+	}
+	r, err := c.h(query, args)
+	if err != nil {
+		return nil, err
+	}
+	if r == nil {
+		return nil, fmt.Errorf("dbtest: unexpected query: %s", query)
+	}
+	return &rows{r: r}, nil
+}
+
+type rows struct {
+	r *Rows
+	i int
+}
+
+func (r *rows) Columns() []string { return r.r.Columns }
+func (r *rows) Close() error      { return nil }
+
+func (r *rows) Next(dest []driver.Value) error {
+	if r.i >= len(r.r.Values) {
+		return io.EOF
+	}
+	copy(dest, r.r.Values[r.i])
+	r.i++
+	return nil
+}
@@ -0,0 +1,12 @@
+package dbtest
+
+// IDs shared by the fixtures of the policy tests.
+const (
+	AliceID = "6f1c2a34-0b6e-4c39-9a51-1d2e3f405161"
+	BobID   = "7a2d3b45-1c7f-4d4a-8b62-2e3f40516272"
+	GuestID = "8b3e4c56-2d80-4e5b-9c73-3f4051627383"
+	AdminID = "9c4f5d67-3e91-4f6c-8d84-405162738494"
+
+	AliceBooking = "0d1e2f30-4152-4637-8899-aabbccddeeff"
+	GuestBooking = "1e2f3041-5263-4748-99aa-bbccddeeff00"
+)
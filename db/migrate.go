@@ -81,6 +81,316 @@ const AlterBookingTableSQL = `
 ALTER TABLE booking ADD COLUMN IF NOT EXISTS user_notes TEXT;
 `
 
+const AlterBookingRejectionSQL = `
+ALTER TABLE booking ADD COLUMN IF NOT EXISTS rejection_code TEXT;
+ALTER TABLE booking ADD COLUMN IF NOT EXISTS rejection_reason TEXT;
+`
+
+const AlterBookingReceiptStoreSQL = `
+ALTER TABLE booking ADD COLUMN IF NOT EXISTS receipt_key TEXT;
+ALTER TABLE booking ADD COLUMN IF NOT EXISTS receipt_hash TEXT;
+`
+
+const AlterBookingReceiptContentSQL = `
+ALTER TABLE booking ADD COLUMN IF NOT EXISTS receipt_content_type TEXT;
+ALTER TABLE booking ADD COLUMN IF NOT EXISTS receipt_thumb_key TEXT;
+`
+
+const AlterBookingReceiptFingerprintSQL = `
+ALTER TABLE booking ADD COLUMN IF NOT EXISTS receipt_phash BIGINT;
+ALTER TABLE booking ADD COLUMN IF NOT EXISTS duplicate_of UUID;
+ALTER TABLE booking ADD COLUMN IF NOT EXISTS duplicate_match TEXT;
+CREATE INDEX IF NOT EXISTS idx_booking_receipt_hash ON booking (receipt_hash);
+`
+
+const AlterBookingReceiptOCRSQL = `
+ALTER TABLE booking ADD COLUMN IF NOT EXISTS ocr_status TEXT;
+ALTER TABLE booking ADD COLUMN IF NOT EXISTS ocr_amount REAL;
+ALTER TABLE booking ADD COLUMN IF NOT EXISTS ocr_date TEXT;
+ALTER TABLE booking ADD COLUMN IF NOT EXISTS ocr_reference TEXT;
+`
+
+const createPaymentIntentTableSQL = `
+CREATE TABLE IF NOT EXISTS payment_intent (
+    intent_id TEXT PRIMARY KEY,
+    booking_id UUID NOT NULL REFERENCES booking(booking_id),
+    provider TEXT NOT NULL,
+    amount BIGINT NOT NULL,             -- minor units (paise/cents)
+    currency TEXT NOT NULL,
+    status TEXT NOT NULL,
+    provider_payment_id TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE
+);
+CREATE INDEX IF NOT EXISTS idx_payment_intent_booking ON payment_intent (booking_id);
+`
+
+const AlterBookingPaymentReferenceSQL = `
+ALTER TABLE booking ADD COLUMN IF NOT EXISTS payment_reference TEXT;
+UPDATE booking
+SET payment_reference = 'BK' || upper(substr(replace(booking_id::text, '-', ''), 1, 12))
+WHERE payment_reference IS NULL;
+CREATE INDEX IF NOT EXISTS idx_booking_payment_reference ON booking (payment_reference);
+`
+
+const createRefundTableSQL = `
+CREATE TABLE IF NOT EXISTS refund (
+    refund_id UUID PRIMARY KEY,
+    booking_id UUID NOT NULL REFERENCES booking(booking_id),
+    concert_id TEXT,
+    email TEXT NOT NULL,
+    amount REAL NOT NULL,
+    method TEXT NOT NULL,
+    payout_to TEXT,
+    status TEXT NOT NULL,               -- REQUESTED, APPROVED, PAID, DENIED
+    reason TEXT,
+    admin_note TEXT,
+    reference TEXT,
+    requested_by TEXT NOT NULL,         -- USER or ADMIN
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_refund_active_booking
+    ON refund (booking_id) WHERE status IN ('REQUESTED', 'APPROVED', 'PAID');
+`
+
+const AlterConcertRefundPolicySQL = `
+ALTER TABLE concert ADD COLUMN IF NOT EXISTS refund_policy JSONB;
+`
+
+// concert_payment scopes payment methods to concerts. The backfill copies the
+// legacy concert.payment_ids JSON array, skipping IDs that no longer exist.
+const createConcertPaymentTableSQL = `
+CREATE TABLE IF NOT EXISTS concert_payment (
+    concert_id UUID NOT NULL REFERENCES concert(concert_id) ON DELETE CASCADE,
+    payment_id UUID NOT NULL REFERENCES payment(payment_id) ON DELETE CASCADE,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    currency TEXT NOT NULL DEFAULT 'INR',
+    PRIMARY KEY (concert_id, payment_id)
+);
+INSERT INTO concert_payment (concert_id, payment_id)
+SELECT c.concert_id, p.payment_id
+FROM concert c
+CROSS JOIN LATERAL jsonb_array_elements_text(
+    CASE WHEN jsonb_typeof(c.payment_ids) = 'array' THEN c.payment_ids ELSE '[]'::jsonb END
+) AS ids(pid)
+JOIN payment p ON p.payment_id::text = ids.pid
+ON CONFLICT DO NOTHING;
+`
+
+const createPromotionTablesSQL = `
+CREATE TABLE IF NOT EXISTS promotion (
+    code TEXT PRIMARY KEY,              -- stored upper-case
+    description TEXT,
+    discount_type TEXT NOT NULL,        -- PERCENT or FIXED
+    value REAL NOT NULL,
+    valid_from TIMESTAMP WITH TIME ZONE,
+    valid_until TIMESTAMP WITH TIME ZONE,
+    max_uses INT NOT NULL DEFAULT 0,    -- 0 = unlimited
+    max_uses_per_email INT NOT NULL DEFAULT 0,
+    concert_ids JSONB,                  -- empty = every concert
+    seat_types JSONB,                   -- empty = every seat type
+    min_quantity INT NOT NULL DEFAULT 0,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+CREATE TABLE IF NOT EXISTS promotion_redemption (
+    booking_id UUID PRIMARY KEY REFERENCES booking(booking_id),
+    code TEXT NOT NULL REFERENCES promotion(code),
+    email TEXT NOT NULL,
+    discount REAL NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_promotion_redemption_code ON promotion_redemption (code);
+ALTER TABLE booking ADD COLUMN IF NOT EXISTS price_breakdown JSONB;
+`
+
+const createSeatPricingTablesSQL = `
+CREATE TABLE IF NOT EXISTS seat_price_tier (
+    tier_id UUID PRIMARY KEY,
+    seat_id UUID NOT NULL REFERENCES seat(seat_id) ON DELETE CASCADE,
+    name TEXT NOT NULL,                 -- e.g. EARLY_BIRD, REGULAR, DOOR
+    price_inr REAL NOT NULL,
+    price_gel REAL NOT NULL,
+    starts_at TIMESTAMP WITH TIME ZONE,
+    ends_at TIMESTAMP WITH TIME ZONE,
+    max_tickets INT NOT NULL DEFAULT 0, -- 0 = no ticket cap
+    position INT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_seat_price_tier_seat ON seat_price_tier (seat_id, position);
+CREATE TABLE IF NOT EXISTS seat_hold (
+    hold_id UUID PRIMARY KEY,
+    seat_id UUID NOT NULL REFERENCES seat(seat_id) ON DELETE CASCADE,
+    email TEXT NOT NULL,
+    quantity INT NOT NULL,
+    tier TEXT NOT NULL,
+    price_inr REAL NOT NULL,            -- unit price locked at hold time
+    price_gel REAL NOT NULL,
+    status TEXT NOT NULL,               -- ACTIVE, CONSUMED, RELEASED
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_seat_hold_active ON seat_hold (seat_id) WHERE status = 'ACTIVE';
+`
+
+const AlterConcertChargesSQL = `
+ALTER TABLE concert ADD COLUMN IF NOT EXISTS charges JSONB;
+`
+
+const createInvoiceTablesSQL = `
+ALTER TABLE booking ADD COLUMN IF NOT EXISTS billing JSONB;
+CREATE TABLE IF NOT EXISTS invoice_series (
+    series TEXT PRIMARY KEY,            -- organizer + year, e.g. BK-2025, BK-CN-2025
+    last_number INT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS invoice (
+    document_id UUID PRIMARY KEY,
+    number TEXT NOT NULL UNIQUE,
+    kind TEXT NOT NULL,                 -- INVOICE or CREDIT_NOTE
+    booking_id UUID NOT NULL REFERENCES booking(booking_id),
+    refund_id UUID UNIQUE REFERENCES refund(refund_id),
+    related_number TEXT,
+    issued_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    seller JSONB NOT NULL,
+    buyer JSONB NOT NULL,
+    lines JSONB NOT NULL,
+    total REAL NOT NULL,
+    currency TEXT NOT NULL,
+    note TEXT
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_invoice_booking ON invoice (booking_id) WHERE kind = 'INVOICE';
+`
+
+const createSeatWaitlistTableSQL = `
+CREATE TABLE IF NOT EXISTS seat_waitlist (
+    entry_id UUID PRIMARY KEY,
+    seat_id UUID NOT NULL REFERENCES seat(seat_id) ON DELETE CASCADE,
+    email TEXT NOT NULL,
+    quantity INT NOT NULL,
+    status TEXT NOT NULL,               -- WAITING, OFFERED, CLAIMED, EXPIRED, LEFT
+    claim_token TEXT UNIQUE,
+    offer_expires_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_seat_waitlist_queue ON seat_waitlist (seat_id, status, created_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_seat_waitlist_active_email ON seat_waitlist (seat_id, lower(email))
+    WHERE status IN ('WAITING', 'OFFERED');
+`
+
+const createTicketTransferTableSQL = `
+ALTER TABLE concert ADD COLUMN IF NOT EXISTS transfer_policy JSONB;
+ALTER TABLE booking ADD COLUMN IF NOT EXISTS ticket_code TEXT;       -- NULL until tickets are reissued
+ALTER TABLE booking ADD COLUMN IF NOT EXISTS transferred_from UUID REFERENCES booking(booking_id);
+CREATE TABLE IF NOT EXISTS ticket_transfer (
+    transfer_id UUID PRIMARY KEY,
+    booking_id UUID NOT NULL REFERENCES booking(booking_id),
+    new_booking_id UUID REFERENCES booking(booking_id),
+    from_email TEXT NOT NULL,
+    to_email TEXT NOT NULL,
+    participant_ids JSONB,              -- NULL/empty = whole booking
+    quantity INT NOT NULL,
+    status TEXT NOT NULL,               -- PENDING, ACCEPTED, DECLINED, CANCELLED, EXPIRED
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ticket_transfer_pending ON ticket_transfer (booking_id) WHERE status = 'PENDING';
+CREATE INDEX IF NOT EXISTS idx_ticket_transfer_to_email ON ticket_transfer (lower(to_email));
+`
+
+const createBookingEventTableSQL = `
+ALTER TABLE concert ADD COLUMN IF NOT EXISTS participant_edit_deadline TIMESTAMP WITH TIME ZONE;
+CREATE TABLE IF NOT EXISTS booking_event (
+    event_id UUID PRIMARY KEY,
+    booking_id UUID NOT NULL REFERENCES booking(booking_id) ON DELETE CASCADE,
+    actor TEXT NOT NULL,
+    action TEXT NOT NULL,               -- PARTICIPANTS_UPDATED, TRANSFERRED_OUT, TRANSFERRED_IN
+    details JSONB,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_booking_event_booking ON booking_event (booking_id, created_at);
+`
+
+const linkBookingOwnersSQL = `
+ALTER TABLE booking ADD COLUMN IF NOT EXISTS user_id UUID REFERENCES users(user_id);
+ALTER TABLE booking ADD COLUMN IF NOT EXISTS contact_email TEXT;
+-- Link historical bookings to the account with the same email. Bookings whose
+-- email has no account yet are claimed when that address first signs in.
+UPDATE booking b SET user_id = u.user_id
+FROM users u
+WHERE b.user_id IS NULL AND LOWER(u.email) = LOWER(b.booking_email);
+CREATE INDEX IF NOT EXISTS idx_booking_user ON booking (user_id);
+`
+
+const createStaffRoleTableSQL = `
+CREATE TABLE IF NOT EXISTS staff_role (
+    assignment_id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    role TEXT NOT NULL,                 -- organizer, verifier, door_staff, viewer
+    concert_id UUID REFERENCES concert(concert_id) ON DELETE CASCADE, -- NULL = all concerts
+    granted_by TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_staff_role_unique
+    ON staff_role (user_id, role, COALESCE(concert_id, '00000000-0000-0000-0000-000000000000'::uuid));
+`
+
+const alterUserManagementSQL = `
+ALTER TABLE users ADD COLUMN IF NOT EXISTS last_login_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS disabled_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS invited_by TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS password_reset_hash TEXT;      -- sha256 of the emailed token
+ALTER TABLE users ADD COLUMN IF NOT EXISTS password_reset_expires_at TIMESTAMP WITH TIME ZONE;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_password_reset ON users (password_reset_hash);
+`
+
+const createRefreshTokenTableSQL = `
+CREATE TABLE IF NOT EXISTS refresh_token (
+    token_hash TEXT PRIMARY KEY,        -- sha256 of the token; the token itself is never stored
+    session_id UUID NOT NULL,           -- all tokens rotated from one login
+    user_id UUID NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,   -- set once exchanged; reuse revokes the session
+    revoked_at TIMESTAMP WITH TIME ZONE
+);
+CREATE INDEX IF NOT EXISTS idx_refresh_token_session ON refresh_token (session_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_token_user ON refresh_token (user_id);
+`
+
+const hardenOTPSQL = `
+-- Codes are stored as bcrypt hashes, one per email, with a guess counter.
+ALTER TABLE otp_codes ADD COLUMN IF NOT EXISTS code_hash TEXT;
+ALTER TABLE otp_codes ADD COLUMN IF NOT EXISTS attempts INT NOT NULL DEFAULT 0;
+ALTER TABLE otp_codes ADD COLUMN IF NOT EXISTS sent_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now();
+DELETE FROM otp_codes WHERE code_hash IS NULL; -- outstanding plaintext codes; users request a new one
+ALTER TABLE otp_codes DROP COLUMN IF EXISTS code;
+ALTER TABLE otp_codes ALTER COLUMN code_hash SET NOT NULL;
+CREATE TABLE IF NOT EXISTS otp_throttle (
+    throttle_key TEXT PRIMARY KEY,      -- email:<address> or ip:<address>
+    sends INT NOT NULL DEFAULT 0,
+    failures INT NOT NULL DEFAULT 0,
+    window_started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    locked_until TIMESTAMP WITH TIME ZONE
+);
+`
+
+const AlterBookingReceiptPHashIndexSQL = `
+CREATE INDEX IF NOT EXISTS idx_booking_receipt_phash_recent ON booking (created_at) WHERE receipt_phash IS NOT NULL;
+`
+
+const AlterBookingSeatsReservedSQL = `
+-- Bookings made before availability was decremented on create never took
+-- their seats, so cancelling or rejecting them must not give any back.
+ALTER TABLE booking ADD COLUMN IF NOT EXISTS seats_reserved BOOLEAN NOT NULL DEFAULT FALSE;
+`
+
+const AlterBookingCurrencySQL = `
+-- The currency a booking was priced and is paid in. Bookings made before
+-- payment methods had a currency were all INR.
+ALTER TABLE booking ADD COLUMN IF NOT EXISTS currency TEXT NOT NULL DEFAULT 'INR';
+`
+
 const AlterConcertTableSQL = `
 ALTER TABLE concert ADD COLUMN IF NOT EXISTS booking BOOLEAN DEFAULT FALSE;
 `
@@ -106,6 +416,31 @@ func RunMigrations() error {
 		{Name: "Bookings", SQL: createBookingTableSQL},
 		{Name: "AlterBookings", SQL: AlterBookingTableSQL},
 		{Name: "AlterConcerts", SQL: AlterConcertTableSQL},
+		{Name: "AlterBookingsRejection", SQL: AlterBookingRejectionSQL},
+		{Name: "AlterBookingsReceiptStore", SQL: AlterBookingReceiptStoreSQL},
+		{Name: "AlterBookingsReceiptContent", SQL: AlterBookingReceiptContentSQL},
+		{Name: "AlterBookingsReceiptFingerprint", SQL: AlterBookingReceiptFingerprintSQL},
+		{Name: "AlterBookingsReceiptOCR", SQL: AlterBookingReceiptOCRSQL},
+		{Name: "PaymentIntents", SQL: createPaymentIntentTableSQL},
+		{Name: "AlterBookingsPaymentReference", SQL: AlterBookingPaymentReferenceSQL},
+		{Name: "Refunds", SQL: createRefundTableSQL},
+		{Name: "AlterConcertsRefundPolicy", SQL: AlterConcertRefundPolicySQL},
+		{Name: "ConcertPayments", SQL: createConcertPaymentTableSQL},
+		{Name: "Promotions", SQL: createPromotionTablesSQL},
+		{Name: "SeatPricing", SQL: createSeatPricingTablesSQL},
+		{Name: "AlterConcertsCharges", SQL: AlterConcertChargesSQL},
+		{Name: "Invoices", SQL: createInvoiceTablesSQL},
+		{Name: "SeatWaitlist", SQL: createSeatWaitlistTableSQL},
+		{Name: "TicketTransfers", SQL: createTicketTransferTableSQL},
+		{Name: "BookingHistory", SQL: createBookingEventTableSQL},
+		{Name: "BookingOwners", SQL: linkBookingOwnersSQL},
+		{Name: "StaffRoles", SQL: createStaffRoleTableSQL},
+		{Name: "UserManagement", SQL: alterUserManagementSQL},
+		{Name: "RefreshTokens", SQL: createRefreshTokenTableSQL},
+		{Name: "OTPHardening", SQL: hardenOTPSQL},
+		{Name: "AlterBookingsReceiptPHashIndex", SQL: AlterBookingReceiptPHashIndexSQL},
+		{Name: "AlterBookingsSeatsReserved", SQL: AlterBookingSeatsReservedSQL},
+		{Name: "AlterBookingsCurrency", SQL: AlterBookingCurrencySQL},
 	}
 
 	logger.Log.Info("[db] Starting database migrations...")
@@ -3,18 +3,28 @@ package main
 import (
 	"fmt"
 	"log"
+	"net"
+	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"supra/applications/auth"
+	"supra/applications/booking"
+	"supra/applications/refund"
+	"supra/applications/seat"
 	"supra/concert/infrastructure"
 	"supra/controllers"
 	"supra/db"
 	"supra/logger" // Your logging package
+	"supra/ocr"
+	"supra/payments"
+	"supra/storage"
 	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"golang.org/x/time/rate"
 )
 
 func main() {
@@ -23,6 +33,7 @@ func main() {
 	}
 
 	e := echo.New()
+	e.IPExtractor = clientIPExtractor()
 
 	// --- INITIAL STARTUP LOGGING ---
 	logger.Log.Info("[main] program started")
@@ -42,21 +53,7 @@ func main() {
 	}))
 
 	// --- DATABASE CONNECTION ---
-	dsn := os.Getenv("DATABASE_URL")
-	if dsn == "" {
-		// local fallback (what you had)
-		dsn = "user=postgres password=postgres dbname=postgres sslmode=disable"
-		logger.Log.Warn("[main] DATABASE_URL not set. Falling back to local Postgres.")
-	} else {
-		// Ensure sslmode=require for Neon (safeguard if the copied URL lacked it)
-		if !strings.Contains(dsn, "sslmode=") {
-			sep := "?"
-			if strings.Contains(dsn, "?") {
-				sep = "&"
-			}
-			dsn = dsn + sep + "sslmode=require"
-		}
-	}
+	dsn := db.DSNFromEnv()
 
 	logger.Log.Info("[main] Attempting to connect to PostgreSQL...")
 	if err := db.InitDB(dsn); err != nil {
@@ -82,77 +79,204 @@ func main() {
 	}
 	logger.Log.Info("[main] Database migrations completed successfully.")
 
+	// --- JWT SIGNING KEYS ---
+	if err := auth.InitJWTKeys(); err != nil {
+		logger.Log.Error(fmt.Sprintf("[main] JWT key configuration failed: %v", err))
+		log.Fatalf("JWT key initialization failed: %v", err)
+	}
+
+	// --- RECEIPT STORAGE ---
+	logger.Log.Info("[main] Configuring receipt blob store...")
+	if err := storage.InitReceiptStore(); err != nil {
+		logger.Log.Error(fmt.Sprintf("[main] Receipt store configuration failed: %v", err))
+		log.Fatalf("Receipt store initialization failed: %v", err)
+	}
+	if _, err := booking.MigrateReceiptsToStore(); err != nil {
+		// Not fatal: unmigrated receipts are still served from the booking table.
+		logger.Log.Error(fmt.Sprintf("[main] Receipt blob migration incomplete: %v", err))
+	}
+
+	// --- RECEIPT OCR (optional) ---
+	if err := ocr.InitReceiptOCR(); err != nil {
+		// OCR only assists admins, so a misconfigured engine must not stop the API.
+		logger.Log.Error(fmt.Sprintf("[main] Receipt OCR disabled: %v", err))
+	}
+	booking.StartReceiptOCRWorker()
+	seat.StartWaitlistWorker()
+
+	// --- PAYMENT GATEWAYS ---
+	payments.InitProviders()
+
 	// --- 1. PUBLIC ROUTES (No Auth Required) ---
 	logger.Log.Info("[router] Registering public authentication and read-only routes.")
 
 	// Authentication/Login routes
 	e.POST("/login", controllers.LoginHandler)
 	e.POST("/verify-otp", controllers.VerifyOTPHandler)
+	e.POST("/refresh-token", controllers.RefreshTokenHandler)
+	e.POST("/logout", controllers.LogoutHandler)
+
+	// Payment gateway webhooks (authenticated by signature, not JWT)
+	e.POST("/webhooks/payments/:provider", controllers.PaymentWebhookController)
 
 	// --- 2. PROTECTED GROUP (Requires Valid JWT Token) ---
 	logger.Log.Info("[router] Configuring '/api/v1' protected group (JWT Required).")
 
 	r := e.Group("/api/v1")
 	r.Use(auth.JWTAuthMiddleware)
+	r.POST("/logout-all", controllers.LogoutAllHandler)
+
+	// Ownership policies: customers may only reach their own bookings,
+	// participants and refunds. Admins bypass these checks, and staff who may
+	// view a concert's bookings can read (not change) its bookings.
+	ownsBooking := auth.OwnerOrAdmin("bookingID", booking.CheckBookingOwner)
+	viewsBooking := auth.OwnerOrPermitted("bookingID", booking.CheckBookingOwner, auth.PermViewBookings, booking.ConcertIDOf)
+	ownsParticipant := auth.OwnerOrAdmin("userID", booking.CheckParticipantOwner)
+	ownsRefund := auth.OwnerOrAdmin("refundID", refund.CheckRefundOwner)
 	noAuth := e.Group("/api/v1")
 
 	// Booking Routes (Making a booking, viewing history)
 	r.POST("/bookings", controllers.BookNowController)
+	r.POST("/bookings/upload", controllers.BookNowUploadController)
+	r.POST("/bookings/quote", controllers.QuoteBookingController)
 	// we'll create a new api to list user specific history not all booking
 
-	// --- 3. ADMIN-ONLY GROUP (Requires JWT + Admin Role) ---
-	logger.Log.Warn("[router] Configuring '/api/v1/admin' group (Admin Role Required).")
+	// --- 3. STAFF GROUP (Requires JWT + a staff role; each route checks its permission) ---
+	logger.Log.Warn("[router] Configuring '/api/v1/admin' group (Staff Role Required).")
 
 	admin := r.Group("/admin")
-	admin.Use(auth.AdminOnlyMiddleware)
+	admin.Use(auth.StaffOnlyMiddleware)
+	bookingScope := func(perm auth.Permission) echo.MiddlewareFunc {
+		return auth.RequirePermissionFor(perm, "bookingID", booking.ConcertIDOf)
+	}
+	participantScope := auth.RequirePermissionFor(auth.PermManageBookings, "userID", booking.ParticipantConcertIDOf)
 
 	// --- ADMIN CRUD ROUTES ---
 
 	// Seats
 	noAuth.GET("/seats", controllers.GetAllSeatsHandler)
 	noAuth.GET("/seats/:seatID", controllers.GetSeatHandler)
-	admin.POST("/seats", controllers.AddSeatHandler)
-	admin.PUT("/seats/:seatID", controllers.UpdateSeatController)
-	admin.DELETE("/seats/:seatID", controllers.DeleteSeatHandler)
+	admin.POST("/seats", controllers.AddSeatHandler, auth.RequirePermission(auth.PermManageSeats))
+	admin.PUT("/seats/:seatID", controllers.UpdateSeatController, auth.RequirePermission(auth.PermManageSeats))
+	admin.DELETE("/seats/:seatID", controllers.DeleteSeatHandler, auth.RequirePermission(auth.PermManageSeats))
+	admin.GET("/seats/:seatID/tiers", controllers.GetPriceTiersController, auth.RequirePermission(auth.PermManageSeats))
+	admin.PUT("/seats/:seatID/tiers", controllers.SetPriceTiersController, auth.RequirePermission(auth.PermManageSeats))
+	r.POST("/seats/:seatID/holds", controllers.CreateSeatHoldController)
+	r.DELETE("/seats/holds/:holdID", controllers.ReleaseSeatHoldController)
+	r.POST("/seats/:seatID/waitlist", controllers.JoinWaitlistController)
+	r.GET("/waitlist", controllers.GetMyWaitlistController)
+	r.DELETE("/waitlist/:entryID", controllers.LeaveWaitlistController)
+	r.POST("/waitlist/claim/:token", controllers.ClaimWaitlistOfferController)
+	admin.GET("/seats/:seatID/waitlist", controllers.GetSeatWaitlistController, auth.RequirePermission(auth.PermManageSeats))
 	logger.Log.Info("[router] Admin: Seats CRUD configured.")
 
 	// Concerts
 	noAuth.GET("/concerts", infrastructure.NewGetAllConcertsController(logger.Log).Invoke)
 	noAuth.GET("/concerts/:concertID", infrastructure.NewGetConcertByIDController(logger.Log).Invoke)
-	admin.POST("/concerts", infrastructure.NewCreateConcertController(logger.Log).Invoke)
+	admin.POST("/concerts", infrastructure.NewCreateConcertController(logger.Log).Invoke, auth.RequirePermission(auth.PermManageConcerts))
 	// admin.PUT("/concerts/:concertID", controllers.UpdateConcertController)
-	admin.DELETE("/concerts/:concertID", infrastructure.NewDeleteConcertController(logger.Log).Invoke)
+	admin.DELETE("/concerts/:concertID", infrastructure.NewDeleteConcertController(logger.Log).Invoke, auth.RequirePermission(auth.PermManageConcerts))
+	admin.PUT("/concerts/:concertID/refund-policy", infrastructure.NewUpdateRefundPolicyController(logger.Log).Invoke, auth.RequirePermission(auth.PermManageConcerts))
+	admin.PUT("/concerts/:concertID/transfer-policy", infrastructure.NewUpdateTransferPolicyController(logger.Log).Invoke, auth.RequirePermission(auth.PermManageConcerts))
+	admin.PUT("/concerts/:concertID/participant-edit-deadline", infrastructure.NewUpdateParticipantEditDeadlineController(logger.Log).Invoke, auth.RequirePermission(auth.PermManageConcerts))
+	admin.PUT("/concerts/:concertID/charges", infrastructure.NewUpdateChargesController(logger.Log).Invoke, auth.RequirePermission(auth.PermManageConcerts))
+	noAuth.GET("/concerts/:concertID/payments", infrastructure.NewGetConcertPaymentsController(logger.Log, false).Invoke)
+	admin.GET("/concerts/:concertID/payments", infrastructure.NewGetConcertPaymentsController(logger.Log, true).Invoke, auth.RequirePermission(auth.PermManageConcerts))
+	admin.PUT("/concerts/:concertID/payments/:paymentID", infrastructure.NewSetConcertPaymentController(logger.Log).Invoke, auth.RequirePermission(auth.PermManageConcerts))
+	admin.DELETE("/concerts/:concertID/payments/:paymentID", infrastructure.NewRemoveConcertPaymentController(logger.Log).Invoke, auth.RequirePermission(auth.PermManageConcerts))
 	logger.Log.Info("[router] Admin: Concerts CRUD configured.")
 
 	// Participants
-	r.GET("/participants", controllers.GetAllParticipantsController)
-	r.GET("/participants/:userID", controllers.GetParticipantController)
-	admin.POST("/participants", controllers.AddParticipantController)
-	admin.PUT("/participants/:userID", controllers.UpdateParticipantController)
-	admin.DELETE("/participants/:userID", controllers.DeleteParticipantController)
+	r.GET("/participants", controllers.GetAllParticipantsController, auth.RequirePermission(auth.PermManageBookings))
+	r.GET("/participants/:userID", controllers.GetParticipantController, ownsParticipant)
+	admin.POST("/participants", controllers.AddParticipantController, auth.RequirePermission(auth.PermManageBookings))
+	admin.PUT("/participants/:userID", controllers.UpdateParticipantController, participantScope)
+	admin.DELETE("/participants/:userID", controllers.DeleteParticipantController, participantScope)
 	logger.Log.Info("[router] Admin: Participants CRUD configured.")
 
-	// Payments
-	noAuth.GET("/payments", controllers.GetAllPaymentsController)
-	noAuth.GET("/payments/:paymentID", controllers.GetPaymentController)
-	admin.POST("/payments", controllers.AddPaymentController)
-	admin.PUT("/payments/:paymentID", controllers.UpdatePaymentController)
-	admin.DELETE("/payments/:paymentID", controllers.DeletePaymentController)
+	// Payments (the full catalogue is admin-only; clients use /concerts/:concertID/payments)
+	admin.GET("/payments", controllers.GetAllPaymentsController, auth.RequirePermission(auth.PermManagePayments))
+	admin.GET("/payments/:paymentID", controllers.GetPaymentController, auth.RequirePermission(auth.PermManagePayments))
+	admin.POST("/payments", controllers.AddPaymentController, auth.RequirePermission(auth.PermManagePayments))
+	admin.PUT("/payments/:paymentID", controllers.UpdatePaymentController, auth.RequirePermission(auth.PermManagePayments))
+	admin.DELETE("/payments/:paymentID", controllers.DeletePaymentController, auth.RequirePermission(auth.PermManagePayments))
 	logger.Log.Info("[router] Admin: Payments CRUD configured.")
 
+	// Online payments
+	r.POST("/bookings/:bookingID/payment-intent", controllers.CreatePaymentIntentController, ownsBooking)
+	if _, err := payments.Get("fake"); err == nil {
+		noAuth.POST("/payment-intents/:intentID/fake-complete", controllers.FakeCompletePaymentController)
+		logger.Log.Warn("[router] Fake payment provider enabled; do not use in production.")
+	}
+
 	// Booking Update/Delete
 	r.GET("/bookings", controllers.GetAllBookingsController)
-	r.GET("/bookings/:bookingID", controllers.GetBookingController)
-	r.GET("/bookings/:bookingID/eticket", controllers.GetETicketController)
-	r.PATCH("/bookings/:bookingID/:resourceType", controllers.UpdateBookingDetailsController)
-	r.GET("/bookings/:bookingID/receipt", controllers.GetBookingReceiptController)
-	noAuth.GET("/bookings/participants-details/:bookingID", controllers.GetAllParicipantsByBookingIDIDController)
+	noAuth.GET("/bookings/rejection-reasons", controllers.GetRejectionReasonsController)
+	r.GET("/bookings/:bookingID", controllers.GetBookingController, viewsBooking)
+	r.GET("/bookings/:bookingID/eticket", controllers.GetETicketController, viewsBooking)
+	r.GET("/bookings/:bookingID/invoice", controllers.GetBookingInvoiceController, viewsBooking)
+	r.GET("/refunds/:refundID/credit-note", controllers.GetCreditNoteController, ownsRefund)
+	r.PATCH("/bookings/:bookingID/:resourceType", controllers.UpdateBookingDetailsController, ownsBooking)
+	r.GET("/bookings/:bookingID/receipt", controllers.GetBookingReceiptController, viewsBooking)
+	r.PUT("/bookings/:bookingID/receipt", controllers.UploadBookingReceiptController, ownsBooking)
+	r.PUT("/bookings/:bookingID/participants", controllers.EditParticipantsController, ownsBooking)
+	r.GET("/bookings/:bookingID/history", controllers.GetBookingHistoryController, viewsBooking)
+	r.GET("/bookings/:bookingID/receipt/file", controllers.GetBookingReceiptFileController, viewsBooking)
+	r.GET("/bookings/:bookingID/receipt/thumbnail", controllers.GetBookingReceiptFileController, viewsBooking)
+	noAuth.GET("/bookings/participants-details/:bookingID", controllers.GetAllParicipantsByBookingIDIDController,
+		ticketQRRateLimiter(), auth.OptionalJWTMiddleware)
 	// admin.PATCH("/bookings/participants-details/:bookingID", controllers.)
-	admin.PUT("/bookings/:bookingID", controllers.UpdateBookingController)
-	admin.DELETE("/bookings/:bookingID", controllers.DeleteBookingController)
-	admin.GET("/bookings", controllers.GetAllBookingsAdminController)
-	admin.GET("/bookings/:concertID/:status", controllers.GetAllBookingsByConcertIDController)
-	admin.PATCH("/bookings/:bookingID/verify", controllers.VerifyBookingController)
+	admin.PUT("/bookings/:bookingID", controllers.UpdateBookingController, bookingScope(auth.PermManageBookings))
+	admin.DELETE("/bookings/:bookingID", controllers.DeleteBookingController, bookingScope(auth.PermManageBookings))
+	admin.GET("/bookings", controllers.GetAllBookingsAdminController, auth.RequirePermission(auth.PermViewBookings))
+	admin.GET("/bookings/:concertID/:status", controllers.GetAllBookingsByConcertIDController, auth.RequirePermission(auth.PermViewBookings))
+	admin.POST("/bookings/:bookingID/check-in", controllers.CheckInController, bookingScope(auth.PermCheckIn))
+	admin.PATCH("/bookings/:bookingID/verify", controllers.VerifyBookingController, bookingScope(auth.PermVerifyBookings))
+	admin.POST("/reconciliation/statements", controllers.ReconcileStatementController, auth.RequirePermission(auth.PermVerifyBookings))
+	admin.GET("/reports/tax", controllers.TaxReportController, auth.RequirePermission(auth.PermViewReports))
+
+	// Promotions
+	admin.POST("/promotions", controllers.CreatePromotionController, auth.RequirePermission(auth.PermManagePromotions))
+	admin.GET("/promotions", controllers.GetPromotionsController, auth.RequirePermission(auth.PermManagePromotions))
+	admin.GET("/promotions/stats", controllers.GetPromotionStatsController, auth.RequirePermission(auth.PermManagePromotions))
+	admin.PUT("/promotions/:code", controllers.UpdatePromotionController, auth.RequirePermission(auth.PermManagePromotions))
+	admin.DELETE("/promotions/:code", controllers.DeactivatePromotionController, auth.RequirePermission(auth.PermManagePromotions))
+	logger.Log.Info("[router] Admin: Promotions configured.")
+
+	// Refunds
+	r.POST("/bookings/:bookingID/refunds", controllers.RequestRefundController, ownsBooking)
+	r.GET("/bookings/:bookingID/refunds", controllers.GetBookingRefundsController, viewsBooking)
+	admin.GET("/refunds", controllers.GetRefundsAdminController, auth.RequirePermission(auth.PermManageRefunds))
+	admin.PATCH("/refunds/:refundID", controllers.ProcessRefundController, auth.RequirePermissionFor(auth.PermManageRefunds, "refundID", refund.ConcertIDOf))
+	admin.POST("/concerts/:concertID/refunds", controllers.RefundConcertController, auth.RequirePermission(auth.PermManageRefunds))
+	logger.Log.Info("[router] Refund workflow configured.")
+
+	// Ticket transfers
+	r.POST("/bookings/:bookingID/transfers", controllers.RequestTransferController, ownsBooking)
+	r.GET("/transfers", controllers.GetMyTransfersController)
+	r.POST("/transfers/:transferID/accept", controllers.AcceptTransferController)
+	r.DELETE("/transfers/:transferID", controllers.CancelTransferController)
+	admin.GET("/transfers", controllers.GetBookingTransfersController, auth.RequirePermission(auth.PermViewBookings))
+	logger.Log.Info("[router] Ticket transfers configured.")
+
+	// Staff roles
+	admin.GET("/roles", controllers.GetRolesController, auth.RequirePermission(auth.PermManageStaff))
+	admin.GET("/staff-roles", controllers.GetStaffRolesController, auth.RequirePermission(auth.PermManageStaff))
+	admin.POST("/staff-roles", controllers.AssignStaffRoleController, auth.RequirePermission(auth.PermManageStaff))
+	admin.DELETE("/staff-roles/:assignmentID", controllers.RevokeStaffRoleController, auth.RequirePermission(auth.PermManageStaff))
+	logger.Log.Info("[router] Admin: Staff roles configured.")
+
+	// User accounts
+	admin.GET("/users", controllers.ListUsersController, auth.RequirePermission(auth.PermManageUsers))
+	admin.POST("/users", controllers.InviteUserController, auth.RequirePermission(auth.PermManageUsers))
+	admin.PUT("/users/:userID/role", controllers.SetUserRoleController, auth.RequirePermission(auth.PermManageUsers))
+	admin.PUT("/users/:userID/password", controllers.SetUserPasswordController, auth.RequirePermission(auth.PermManageUsers))
+	admin.POST("/users/:userID/password-reset", controllers.RequestPasswordResetController, auth.RequirePermission(auth.PermManageUsers))
+	admin.POST("/users/:userID/disable", controllers.SetUserDisabledController(true), auth.RequirePermission(auth.PermManageUsers))
+	admin.POST("/users/:userID/enable", controllers.SetUserDisabledController(false), auth.RequirePermission(auth.PermManageUsers))
+	admin.POST("/users/:userID/revoke-sessions", controllers.RevokeUserSessionsController, auth.RequirePermission(auth.PermManageUsers))
+	e.POST("/password/reset", controllers.ResetPasswordController)
+	logger.Log.Info("[router] Admin: User management configured.")
 
 	logger.Log.Info("[router] Admin: Booking Update/Delete configured.")
 
@@ -160,3 +284,53 @@ func main() {
 	log.Println("Starting Echo server on http://localhost:8080")
 	e.Logger.Fatal(e.Start(":8080"))
 }
+
+// clientIPExtractor decides where c.RealIP() comes from. By default it is
+// the peer address, so clients cannot pick their own IP with headers. Behind
+// a reverse proxy set TRUSTED_PROXIES to its comma-separated CIDR ranges and
+// X-Forwarded-For is read, trusting only hops from those ranges.
+func clientIPExtractor() echo.IPExtractor {
+	raw := os.Getenv("TRUSTED_PROXIES")
+	if strings.TrimSpace(raw) == "" {
+		return echo.ExtractIPDirect()
+	}
+	var opts []echo.TrustOption
+	for _, cidr := range strings.Split(raw, ",") {
+		cidr = strings.TrimSpace(cidr)
+		if cidr == "" {
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			log.Fatalf("Invalid TRUSTED_PROXIES entry %q: %v", cidr, err)
+		}
+		opts = append(opts, echo.TrustIPRange(ipNet))
+	}
+	logger.Log.Info(fmt.Sprintf("[main] Reading client IPs from X-Forwarded-For behind %d trusted proxy range(s)", len(opts)))
+	return echo.ExtractIPFromXFFHeader(opts...)
+}
+
+// ticketQRRateLimiter limits lookups on the public ticket QR endpoint per
+// client IP (TICKET_QR_RATE_PER_MIN, default 30) to slow down booking ID
+// enumeration.
+func ticketQRRateLimiter() echo.MiddlewareFunc {
+	perMinute := 30
+	if v, err := strconv.Atoi(os.Getenv("TICKET_QR_RATE_PER_MIN")); err == nil && v > 0 {
+		perMinute = v
+	}
+	store := middleware.NewRateLimiterMemoryStoreWithConfig(middleware.RateLimiterMemoryStoreConfig{
+		Rate:      rate.Limit(float64(perMinute) / 60),
+		Burst:     perMinute,
+		ExpiresIn: 10 * time.Minute,
+	})
+	return middleware.RateLimiterWithConfig(middleware.RateLimiterConfig{
+		Store: store,
+		IdentifierExtractor: func(c echo.Context) (string, error) {
+			return c.RealIP(), nil
+		},
+		DenyHandler: func(c echo.Context, identifier string, err error) error {
+			logger.Log.Warn(fmt.Sprintf("[ticket-access] Rate limit hit by %s on %s", identifier, c.Request().URL.Path))
+			return c.JSON(http.StatusTooManyRequests, map[string]string{"error": "Too many requests. Please try again shortly."})
+		},
+	})
+}
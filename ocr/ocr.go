@@ -0,0 +1,55 @@
+package ocr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"supra/logger"
+)
+
+// ErrUnsupported is returned when an engine cannot read the given content type.
+var ErrUnsupported = errors.New("content type not supported by OCR engine")
+
+// Engine turns a receipt image into plain text.
+type Engine interface {
+	Name() string
+	Text(ctx context.Context, data []byte, contentType string) (string, error)
+}
+
+// Receipts is the engine used for booking receipts; nil when OCR is disabled.
+var Receipts Engine
+
+// InitReceiptOCR configures the receipt OCR engine from the environment.
+//
+//	OCR_ENGINE unset or "off" disables OCR.
+//	OCR_ENGINE=tesseract runs the tesseract binary (TESSERACT_PATH, default "tesseract").
+//	OCR_ENGINE=stub returns OCR_STUB_TEXT for every receipt (development and tests).
+func InitReceiptOCR() error {
+	kind := strings.ToLower(strings.TrimSpace(os.Getenv("OCR_ENGINE")))
+
+	switch kind {
+	case "", "off", "none":
+		Receipts = nil
+		logger.Log.Info("[ocr] Receipt OCR disabled.")
+
+	case "tesseract":
+		engine, err := NewTesseract(os.Getenv("TESSERACT_PATH"))
+		if err != nil {
+			return err
+		}
+		Receipts = engine
+		logger.Log.Info(fmt.Sprintf("[ocr] Receipt OCR: tesseract at %s", engine.path))
+
+	case "stub":
+		Receipts = &Stub{Output: os.Getenv("OCR_STUB_TEXT")}
+		logger.Log.Info("[ocr] Receipt OCR: stub engine")
+
+	default:
+		return fmt.Errorf("unknown OCR_ENGINE %q (expected tesseract, stub or off)", kind)
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package ocr
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ReceiptFields are the payment details recognised in a receipt's text.
+type ReceiptFields struct {
+	Amount    float64
+	HasAmount bool
+	Date      string // YYYY-MM-DD when recognised, otherwise the raw text
+	Reference string // UPI reference / UTR / transaction ID
+}
+
+var (
+	currencyAmountRe = regexp.MustCompile(`(?i)(?:₹|rs\.?|inr)\s*([0-9][0-9,]*(?:\.[0-9]{1,2})?)`)
+	labelAmountRe    = regexp.MustCompile(`(?i)(?:amount|paid|total)\s*[:\-]?\s*([0-9][0-9,]*(?:\.[0-9]{1,2})?)`)
+
+	referenceRe = regexp.MustCompile(`(?i)(?:upi\s*(?:ref(?:erence)?|transaction)\s*(?:no\.?|id|number)?|utr\s*(?:no\.?|number)?|transaction\s*id|txn\s*id|ref(?:erence)?\s*(?:no\.?|number))\s*[:#\-]?\s*([A-Z0-9]{8,35})`)
+	bareUTRRe   = regexp.MustCompile(`\b\d{12}\b`)
+
+	dateRes = []*regexp.Regexp{
+		regexp.MustCompile(`\b\d{4}-\d{2}-\d{2}\b`),
+		regexp.MustCompile(`\b\d{1,2}[/\-.]\d{1,2}[/\-.]\d{2,4}\b`),
+		regexp.MustCompile(`(?i)\b\d{1,2}\s+(?:jan|feb|mar|apr|may|jun|jul|aug|sep|oct|nov|dec)[a-z]*,?\s+\d{4}\b`),
+		regexp.MustCompile(`(?i)\b(?:jan|feb|mar|apr|may|jun|jul|aug|sep|oct|nov|dec)[a-z]*\s+\d{1,2},?\s+\d{4}\b`),
+	}
+	dateLayouts = []string{
+		"2006-01-02", "02/01/2006", "2/1/2006", "02-01-2006", "02.01.2006", "02/01/06",
+		"2 Jan 2006", "2 January 2006", "2 Jan, 2006", "Jan 2, 2006", "January 2, 2006", "Jan 2 2006",
+	}
+)
+
+// ParseReceipt extracts amount, date and transaction reference from OCR text.
+func ParseReceipt(text string) ReceiptFields {
+	var f ReceiptFields
+
+	if m := currencyAmountRe.FindStringSubmatch(text); m != nil {
+		f.Amount, f.HasAmount = parseAmount(m[1])
+	} else if m := labelAmountRe.FindStringSubmatch(text); m != nil {
+		f.Amount, f.HasAmount = parseAmount(m[1])
+	}
+
+	if m := referenceRe.FindStringSubmatch(text); m != nil {
+		f.Reference = strings.ToUpper(m[1])
+	} else if m := bareUTRRe.FindString(text); m != "" {
+		f.Reference = m
+	}
+
+	for _, re := range dateRes {
+		if m := re.FindString(text); m != "" {
+			f.Date = normalizeDate(m)
+			break
+		}
+	}
+
+	return f
+}
+
+func parseAmount(s string) (float64, bool) {
+	v, err := strconv.ParseFloat(strings.ReplaceAll(s, ",", ""), 64)
+	if err != nil || v <= 0 {
+		return 0, false
+	}
+	return v, true
+}
+
+func normalizeDate(s string) string {
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.Format("2006-01-02")
+		}
+	}
+	return s
+}
@@ -0,0 +1,15 @@
+package ocr
+
+import "context"
+
+// Stub returns fixed text, so the OCR flow can be exercised without tesseract.
+type Stub struct {
+	Output string
+	Err    error
+}
+
+func (s *Stub) Name() string { return "stub" }
+
+func (s *Stub) Text(ctx context.Context, data []byte, contentType string) (string, error) {
+	return s.Output, s.Err
+}
@@ -0,0 +1,45 @@
+package ocr
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Tesseract shells out to the tesseract CLI, feeding the image on stdin.
+type Tesseract struct {
+	path string
+	lang string
+}
+
+// NewTesseract verifies the binary is available. An empty path means "tesseract" on PATH.
+func NewTesseract(path string) (*Tesseract, error) {
+	if path == "" {
+		path = "tesseract"
+	}
+	resolved, err := exec.LookPath(path)
+	if err != nil {
+		return nil, fmt.Errorf("tesseract binary not found: %w", err)
+	}
+	return &Tesseract{path: resolved, lang: "eng"}, nil
+}
+
+func (t *Tesseract) Name() string { return "tesseract" }
+
+func (t *Tesseract) Text(ctx context.Context, data []byte, contentType string) (string, error) {
+	if !strings.HasPrefix(contentType, "image/") {
+		return "", fmt.Errorf("%w: %s", ErrUnsupported, contentType)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, t.path, "stdin", "stdout", "-l", t.lang, "--psm", "6")
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("tesseract failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
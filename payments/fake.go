@@ -0,0 +1,82 @@
+package payments
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+const fakeSignatureHeader = "X-Fake-Signature"
+
+// Fake is an offline provider: intents are local IDs and webhooks are signed
+// with a shared secret, so the whole flow can be exercised without a gateway.
+type Fake struct {
+	secret string
+}
+
+func NewFake(secret string) *Fake {
+	if secret == "" {
+		secret = "fake-webhook-secret"
+	}
+	return &Fake{secret: secret}
+}
+
+func (f *Fake) Name() string { return "fake" }
+
+func (f *Fake) CreateIntent(ctx context.Context, p IntentParams) (*Intent, error) {
+	id := "fake_" + uuid.NewString()
+	return &Intent{
+		ID:          id,
+		Provider:    f.Name(),
+		Amount:      ToMinor(p.Amount),
+		Currency:    p.Currency,
+		CheckoutURL: fmt.Sprintf("/api/v1/payment-intents/%s/fake-complete", id),
+	}, nil
+}
+
+type fakeWebhook struct {
+	Type      string `json:"type"`
+	IntentID  string `json:"intentID"`
+	PaymentID string `json:"paymentID"`
+	Amount    int64  `json:"amount"`
+	Currency  string `json:"currency"`
+}
+
+// Simulate builds a signed webhook for an intent, as the real gateway would send it.
+func (f *Fake) Simulate(ev Event) (http.Header, []byte) {
+	if ev.PaymentID == "" {
+		ev.PaymentID = "fakepay_" + uuid.NewString()
+	}
+	body, _ := json.Marshal(fakeWebhook{
+		Type: ev.Type, IntentID: ev.IntentID, PaymentID: ev.PaymentID, Amount: ev.Amount, Currency: ev.Currency,
+	})
+	header := http.Header{}
+	header.Set(fakeSignatureHeader, f.sign(body))
+	return header, body
+}
+
+func (f *Fake) ParseWebhook(header http.Header, body []byte) (*Event, error) {
+	if !hmac.Equal([]byte(f.sign(body)), []byte(header.Get(fakeSignatureHeader))) {
+		return nil, ErrInvalidSignature
+	}
+	var wh fakeWebhook
+	if err := json.Unmarshal(body, &wh); err != nil {
+		return nil, fmt.Errorf("fake webhook decode failed: %w", err)
+	}
+	if wh.Type != EventSucceeded && wh.Type != EventFailed {
+		return nil, fmt.Errorf("%w: %s", ErrIgnoredEvent, wh.Type)
+	}
+	return &Event{Type: wh.Type, IntentID: wh.IntentID, PaymentID: wh.PaymentID, Amount: wh.Amount, Currency: wh.Currency}, nil
+}
+
+func (f *Fake) sign(body []byte) string {
+	mac := hmac.New(sha256.New, []byte(f.secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
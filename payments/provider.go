@@ -0,0 +1,114 @@
+package payments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"supra/logger"
+)
+
+var (
+	ErrUnknownProvider  = errors.New("unknown payment provider")
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	ErrIgnoredEvent     = errors.New("webhook event ignored")
+)
+
+const (
+	EventSucceeded = "SUCCEEDED"
+	EventFailed    = "FAILED"
+)
+
+// IntentParams describes the payment a booking needs.
+type IntentParams struct {
+	BookingID string
+	Amount    float64 // major units (e.g. rupees)
+	Currency  string  // ISO code, e.g. INR
+	Email     string
+}
+
+// Intent is the provider-side payment object the client completes checkout against.
+type Intent struct {
+	ID           string `json:"intentID"`
+	Provider     string `json:"provider"`
+	Amount       int64  `json:"amount"` // minor units (e.g. paise)
+	Currency     string `json:"currency"`
+	ClientSecret string `json:"clientSecret,omitempty"` // Stripe
+	PublicKey    string `json:"publicKey,omitempty"`    // Razorpay key_id for Checkout
+	CheckoutURL  string `json:"checkoutURL,omitempty"`  // fake provider
+}
+
+// Event is a verified webhook notification.
+type Event struct {
+	Type      string // EventSucceeded or EventFailed
+	IntentID  string
+	PaymentID string
+	Amount    int64
+	Currency  string
+}
+
+// Provider is implemented by each payment gateway.
+type Provider interface {
+	Name() string
+	CreateIntent(ctx context.Context, p IntentParams) (*Intent, error)
+	// ParseWebhook verifies the signature and decodes the event. Events the
+	// integration does not act on return ErrIgnoredEvent.
+	ParseWebhook(header http.Header, body []byte) (*Event, error)
+}
+
+var providers = map[string]Provider{}
+
+// Register makes a provider available under its name.
+func Register(p Provider) {
+	providers[p.Name()] = p
+}
+
+// Get returns a registered provider; an empty name selects PAYMENT_PROVIDER.
+func Get(name string) (Provider, error) {
+	if name == "" {
+		name = os.Getenv("PAYMENT_PROVIDER")
+	}
+	p, ok := providers[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownProvider, name)
+	}
+	return p, nil
+}
+
+// Enabled lists the registered provider names.
+func Enabled() []string {
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// InitProviders registers every gateway whose credentials are present.
+//
+//	Razorpay: RAZORPAY_KEY_ID, RAZORPAY_KEY_SECRET, RAZORPAY_WEBHOOK_SECRET
+//	Stripe:   STRIPE_SECRET_KEY, STRIPE_WEBHOOK_SECRET
+//	Fake:     PAYMENTS_FAKE=true (offline development only)
+func InitProviders() {
+	if id, secret := os.Getenv("RAZORPAY_KEY_ID"), os.Getenv("RAZORPAY_KEY_SECRET"); id != "" && secret != "" {
+		Register(NewRazorpay(id, secret, os.Getenv("RAZORPAY_WEBHOOK_SECRET")))
+	}
+	if key := os.Getenv("STRIPE_SECRET_KEY"); key != "" {
+		Register(NewStripe(key, os.Getenv("STRIPE_WEBHOOK_SECRET")))
+	}
+	if strings.EqualFold(os.Getenv("PAYMENTS_FAKE"), "true") {
+		Register(NewFake(os.Getenv("PAYMENTS_FAKE_SECRET")))
+	}
+	logger.Log.Info(fmt.Sprintf("[payments] Enabled providers: %v", Enabled()))
+}
+
+// ToMinor converts a major-unit amount to the provider's minor units.
+func ToMinor(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
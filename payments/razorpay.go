@@ -0,0 +1,112 @@
+package payments
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const razorpayAPI = "https://api.razorpay.com/v1"
+
+// Razorpay creates Orders and verifies X-Razorpay-Signature webhooks.
+type Razorpay struct {
+	keyID         string
+	keySecret     string
+	webhookSecret string
+	client        *http.Client
+}
+
+func NewRazorpay(keyID, keySecret, webhookSecret string) *Razorpay {
+	return &Razorpay{
+		keyID:         keyID,
+		keySecret:     keySecret,
+		webhookSecret: webhookSecret,
+		client:        &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+func (r *Razorpay) Name() string { return "razorpay" }
+
+func (r *Razorpay) CreateIntent(ctx context.Context, p IntentParams) (*Intent, error) {
+	body, _ := json.Marshal(map[string]interface{}{
+		"amount":   ToMinor(p.Amount),
+		"currency": p.Currency,
+		"receipt":  p.BookingID,
+		"notes":    map[string]string{"booking_id": p.BookingID},
+	})
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, razorpayAPI+"/orders", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(r.keyID, r.keySecret)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("razorpay order request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	raw, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("razorpay order request failed: %s: %s", resp.Status, raw)
+	}
+
+	var order struct {
+		ID       string `json:"id"`
+		Amount   int64  `json:"amount"`
+		Currency string `json:"currency"`
+	}
+	if err := json.Unmarshal(raw, &order); err != nil {
+		return nil, fmt.Errorf("razorpay order decode failed: %w", err)
+	}
+	return &Intent{ID: order.ID, Provider: r.Name(), Amount: order.Amount, Currency: order.Currency, PublicKey: r.keyID}, nil
+}
+
+func (r *Razorpay) ParseWebhook(header http.Header, body []byte) (*Event, error) {
+	if r.webhookSecret == "" {
+		return nil, fmt.Errorf("%w: razorpay webhook secret not configured", ErrInvalidSignature)
+	}
+	mac := hmac.New(sha256.New, []byte(r.webhookSecret))
+	mac.Write(body)
+	expected := hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(header.Get("X-Razorpay-Signature"))) {
+		return nil, ErrInvalidSignature
+	}
+
+	var payload struct {
+		Event   string `json:"event"`
+		Payload struct {
+			Payment struct {
+				Entity struct {
+					ID       string `json:"id"`
+					OrderID  string `json:"order_id"`
+					Amount   int64  `json:"amount"`
+					Currency string `json:"currency"`
+				} `json:"entity"`
+			} `json:"payment"`
+		} `json:"payload"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("razorpay webhook decode failed: %w", err)
+	}
+
+	pay := payload.Payload.Payment.Entity
+	ev := &Event{IntentID: pay.OrderID, PaymentID: pay.ID, Amount: pay.Amount, Currency: pay.Currency}
+	switch payload.Event {
+	case "payment.captured", "order.paid":
+		ev.Type = EventSucceeded
+	case "payment.failed":
+		ev.Type = EventFailed
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrIgnoredEvent, payload.Event)
+	}
+	return ev, nil
+}
@@ -0,0 +1,149 @@
+package payments
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	stripeAPI              = "https://api.stripe.com/v1"
+	stripeSignatureMaxSkew = 5 * time.Minute
+)
+
+// Stripe creates PaymentIntents and verifies Stripe-Signature webhooks.
+type Stripe struct {
+	secretKey     string
+	webhookSecret string
+	client        *http.Client
+}
+
+func NewStripe(secretKey, webhookSecret string) *Stripe {
+	return &Stripe{
+		secretKey:     secretKey,
+		webhookSecret: webhookSecret,
+		client:        &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+func (s *Stripe) Name() string { return "stripe" }
+
+func (s *Stripe) CreateIntent(ctx context.Context, p IntentParams) (*Intent, error) {
+	form := url.Values{}
+	form.Set("amount", strconv.FormatInt(ToMinor(p.Amount), 10))
+	form.Set("currency", strings.ToLower(p.Currency))
+	form.Set("metadata[booking_id]", p.BookingID)
+	if p.Email != "" {
+		form.Set("receipt_email", p.Email)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, stripeAPI+"/payment_intents", strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+s.secretKey)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Idempotency-Key", "booking-"+p.BookingID+"-"+form.Get("amount"))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("stripe payment intent request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	raw, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("stripe payment intent request failed: %s: %s", resp.Status, raw)
+	}
+
+	var pi struct {
+		ID           string `json:"id"`
+		Amount       int64  `json:"amount"`
+		Currency     string `json:"currency"`
+		ClientSecret string `json:"client_secret"`
+	}
+	if err := json.Unmarshal(raw, &pi); err != nil {
+		return nil, fmt.Errorf("stripe payment intent decode failed: %w", err)
+	}
+	return &Intent{ID: pi.ID, Provider: s.Name(), Amount: pi.Amount, Currency: strings.ToUpper(pi.Currency), ClientSecret: pi.ClientSecret}, nil
+}
+
+func (s *Stripe) ParseWebhook(header http.Header, body []byte) (*Event, error) {
+	if s.webhookSecret == "" {
+		return nil, fmt.Errorf("%w: stripe webhook secret not configured", ErrInvalidSignature)
+	}
+	if err := s.verify(header.Get("Stripe-Signature"), body); err != nil {
+		return nil, err
+	}
+
+	var payload struct {
+		Type string `json:"type"`
+		Data struct {
+			Object struct {
+				ID           string `json:"id"`
+				Amount       int64  `json:"amount"`
+				Currency     string `json:"currency"`
+				LatestCharge string `json:"latest_charge"`
+			} `json:"object"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("stripe webhook decode failed: %w", err)
+	}
+
+	obj := payload.Data.Object
+	ev := &Event{IntentID: obj.ID, PaymentID: obj.LatestCharge, Amount: obj.Amount, Currency: strings.ToUpper(obj.Currency)}
+	switch payload.Type {
+	case "payment_intent.succeeded":
+		ev.Type = EventSucceeded
+	case "payment_intent.payment_failed":
+		ev.Type = EventFailed
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrIgnoredEvent, payload.Type)
+	}
+	return ev, nil
+}
+
+// verify checks a "t=<ts>,v1=<sig>" header against HMAC-SHA256("<ts>.<body>").
+func (s *Stripe) verify(sigHeader string, body []byte) error {
+	var ts string
+	var sigs []string
+	for _, part := range strings.Split(sigHeader, ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch k {
+		case "t":
+			ts = v
+		case "v1":
+			sigs = append(sigs, v)
+		}
+	}
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil || len(sigs) == 0 {
+		return ErrInvalidSignature
+	}
+	if skew := time.Since(time.Unix(unix, 0)); skew > stripeSignatureMaxSkew || skew < -stripeSignatureMaxSkew {
+		return fmt.Errorf("%w: timestamp outside tolerance", ErrInvalidSignature)
+	}
+
+	mac := hmac.New(sha256.New, []byte(s.webhookSecret))
+	mac.Write([]byte(ts + "."))
+	mac.Write(body)
+	expected := hex.EncodeToString(mac.Sum(nil))
+	for _, sig := range sigs {
+		if hmac.Equal([]byte(expected), []byte(sig)) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"supra/logger"
+)
+
+// ErrNotFound is returned when a blob does not exist in the store.
+var ErrNotFound = errors.New("blob not found")
+
+// BlobStore is a minimal key/value store for binary objects such as receipts.
+type BlobStore interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// Receipts is the global store used for booking receipts.
+var Receipts BlobStore
+
+// InitReceiptStore configures the global receipt store from the environment.
+//
+//	RECEIPT_STORE=local (default) uses RECEIPT_STORE_DIR (default "data/receipts").
+//	RECEIPT_STORE=s3 uses S3_ENDPOINT, S3_REGION, S3_BUCKET, S3_ACCESS_KEY_ID and
+//	S3_SECRET_ACCESS_KEY, which also works against MinIO for local testing.
+func InitReceiptStore() error {
+	kind := strings.ToLower(strings.TrimSpace(os.Getenv("RECEIPT_STORE")))
+
+	switch kind {
+	case "", "local":
+		dir := os.Getenv("RECEIPT_STORE_DIR")
+		if dir == "" {
+			dir = "data/receipts"
+		}
+		store, err := NewLocalStore(dir)
+		if err != nil {
+			return err
+		}
+		Receipts = store
+		logger.Log.Info(fmt.Sprintf("[storage] Receipt store: local filesystem at %s", dir))
+
+	case "s3", "minio":
+		store, err := NewS3Store(S3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Region:    os.Getenv("S3_REGION"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY_ID"),
+			SecretKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+		})
+		if err != nil {
+			return err
+		}
+		Receipts = store
+		logger.Log.Info(fmt.Sprintf("[storage] Receipt store: S3-compatible bucket %s at %s", store.cfg.Bucket, store.cfg.Endpoint))
+
+	default:
+		return fmt.Errorf("unknown RECEIPT_STORE %q (expected local or s3)", kind)
+	}
+
+	return nil
+}
@@ -0,0 +1,262 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// FieldError describes one invalid request field, named as in the JSON body.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Errors collects every field error of a request.
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	parts := make([]string, len(e))
+	for i, fe := range e {
+		parts[i] = fe.Field + ": " + fe.Message
+	}
+	return "invalid request: " + strings.Join(parts, "; ")
+}
+
+// Add appends a field error, for rules that span several fields.
+func (e *Errors) Add(field, format string, args ...interface{}) {
+	*e = append(*e, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// Err returns the errors as an error, or nil when there are none.
+func (e Errors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Response is the body returned for a request that failed validation.
+type Response struct {
+	Errors Errors `json:"errors"`
+}
+
+// As extracts validation errors from a (possibly wrapped) error.
+func As(err error) (Errors, bool) {
+	var ve Errors
+	if errors.As(err, &ve) {
+		return ve, true
+	}
+	return nil, false
+}
+
+// Struct validates v against its `validate` tags and returns Errors or nil.
+func Struct(v interface{}) error {
+	return Check(v).Err()
+}
+
+// Check validates v against its `validate` tags. Supported rules:
+//
+//	required      non-zero value (non-empty string/slice, non-nil pointer)
+//	email         a single email address
+//	uuid          a UUID string
+//	min=N, max=N  length for strings and slices, value for numbers
+//	gt=N, gte=N   numeric bounds
+//	oneof=A B C   one of the listed values
+//	dive          rules after it apply to each element of a slice
+//
+// Nested structs and slices of structs are validated too, with field names
+// such as "participants[1].waNum". Empty optional fields skip their rules.
+func Check(v interface{}) Errors {
+	var errs Errors
+	checkStruct(reflect.ValueOf(v), "", &errs)
+	return errs
+}
+
+func checkStruct(rv reflect.Value, prefix string, errs *Errors) {
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return
+	}
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		fv := rv.Field(i)
+		if sf.Anonymous {
+			checkStruct(fv, prefix, errs)
+			continue
+		}
+		name := fieldName(sf)
+		if name == "-" {
+			continue
+		}
+		path := prefix + name
+		checkField(fv, path, sf.Tag.Get("validate"), errs)
+		descend(fv, path, errs)
+	}
+}
+
+func descend(fv reflect.Value, path string, errs *Errors) {
+	switch fv.Kind() {
+	case reflect.Ptr, reflect.Struct:
+		if fv.Kind() == reflect.Ptr && fv.IsNil() {
+			return
+		}
+		if reflect.Indirect(fv).Kind() == reflect.Struct && reflect.Indirect(fv).Type().PkgPath() != "time" {
+			checkStruct(fv, path+".", errs)
+		}
+	case reflect.Slice, reflect.Array:
+		for j := 0; j < fv.Len(); j++ {
+			el := fv.Index(j)
+			elPath := fmt.Sprintf("%s[%d]", path, j)
+			if el.Kind() == reflect.Ptr && el.IsNil() {
+				*errs = append(*errs, FieldError{Field: elPath, Message: "must not be null"})
+				continue
+			}
+			if reflect.Indirect(el).Kind() == reflect.Struct {
+				checkStruct(el, elPath+".", errs)
+			}
+		}
+	}
+}
+
+func fieldName(sf reflect.StructField) string {
+	name := strings.Split(sf.Tag.Get("json"), ",")[0]
+	if name == "" {
+		return sf.Name
+	}
+	return name
+}
+
+func checkField(fv reflect.Value, path, tag string, errs *Errors) {
+	if tag == "" {
+		return
+	}
+	tag, elemTag, dive := strings.Cut(","+tag, ",dive")
+	tag = strings.TrimPrefix(tag, ",")
+	if dive && (fv.Kind() == reflect.Slice || fv.Kind() == reflect.Array) {
+		elemTag = strings.TrimPrefix(elemTag, ",")
+		for j := 0; j < fv.Len(); j++ {
+			checkField(fv.Index(j), fmt.Sprintf("%s[%d]", path, j), elemTag, errs)
+		}
+	}
+	if tag == "" {
+		return
+	}
+	rules := strings.Split(tag, ",")
+	empty := fv.IsZero()
+	if fv.Kind() == reflect.String {
+		empty = strings.TrimSpace(fv.String()) == ""
+	}
+
+	for _, rule := range rules {
+		if rule == "required" && empty {
+			*errs = append(*errs, FieldError{Field: path, Message: "is required"})
+			return
+		}
+	}
+	if empty {
+		return
+	}
+	val := reflect.Indirect(fv)
+
+	for _, rule := range rules {
+		name, arg, _ := strings.Cut(rule, "=")
+		if msg := applyRule(val, name, arg); msg != "" {
+			*errs = append(*errs, FieldError{Field: path, Message: msg})
+			return
+		}
+	}
+}
+
+func applyRule(v reflect.Value, rule, arg string) string {
+	switch rule {
+	case "required":
+		return ""
+	case "email":
+		if a, err := mail.ParseAddress(v.String()); err != nil || a.Address != strings.TrimSpace(v.String()) {
+			return "must be a valid email address"
+		}
+	case "uuid":
+		if _, err := uuid.Parse(v.String()); err != nil {
+			return "must be a valid ID"
+		}
+	case "oneof":
+		for _, opt := range strings.Fields(arg) {
+			if strings.EqualFold(fmt.Sprint(v.Interface()), opt) {
+				return ""
+			}
+		}
+		return "must be one of " + strings.Join(strings.Fields(arg), ", ")
+	case "min", "max", "gt", "gte":
+		limit, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return fmt.Sprintf("has an invalid %s rule", rule)
+		}
+		n, unit := measure(v)
+		return compare(rule, n, limit, unit)
+	default:
+		return fmt.Sprintf("has an unknown validation rule %q", rule)
+	}
+	return ""
+}
+
+// measure returns the length of strings and collections (with its unit) or
+// a number's value.
+func measure(v reflect.Value) (float64, string) {
+	switch v.Kind() {
+	case reflect.String:
+		return float64(len([]rune(v.String()))), "characters"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return float64(v.Len()), "items"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), ""
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), ""
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), ""
+	}
+	return 0, ""
+}
+
+func compare(rule string, n, limit float64, unit string) string {
+	lim := strconv.FormatFloat(limit, 'f', -1, 64)
+	switch rule {
+	case "min":
+		if n < limit {
+			if unit != "" {
+				return "must have at least " + lim + " " + unit
+			}
+			return "must be at least " + lim
+		}
+	case "max":
+		if n > limit {
+			if unit != "" {
+				return "must have at most " + lim + " " + unit
+			}
+			return "must be at most " + lim
+		}
+	case "gt":
+		if n <= limit {
+			return "must be greater than " + lim
+		}
+	case "gte":
+		if n < limit {
+			return "must be at least " + lim
+		}
+	}
+	return ""
+}